@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.yaml.in/yaml/v3"
+)
+
+// newConfigCommand 创建 config 命令
+func newConfigCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "配置管理",
+	}
+
+	var printConfig bool
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "校验配置文件",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+			if printConfig {
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent(2)
+				if err := enc.Encode(cfg.ToMap()); err != nil {
+					return err
+				}
+				if err := enc.Close(); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "配置校验通过: %s\n", opts.configPath)
+			return nil
+		},
+	}
+	validateCmd.Flags().BoolVar(&printConfig, "print", false, "打印展开环境变量和填充默认值后的配置（敏感字段脱敏）")
+	cmd.AddCommand(validateCmd)
+
+	return cmd
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// newEncryptionCommand 创建 encryption 命令
+func newEncryptionCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption",
+		Short: "敏感字段加密",
+	}
+
+	var decrypt bool
+	reencrypt := &cobra.Command{
+		Use:   "reencrypt",
+		Short: "使用当前密钥重新加密全部敏感字段",
+		Long: "将明文和使用旧密钥加密的敏感字段改用 encryption.active_key 加密，并补齐手机号盲索引；\n" +
+			"用户名与本人手机号相同的用户（此前自动注册时以手机号作为默认用户名）改为随机生成的默认用户名。\n" +
+			"执行迁移 000013、000016 后和轮换密钥后执行，可重复执行，已使用当前密钥加密的数据不会改写。\n" +
+			"--decrypt 将全部敏感字段还原为明文，回滚迁移 000016、000015、000013 前执行",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			application, err := opts.loadApp()
+			if err != nil {
+				return err
+			}
+			defer application.Close()
+
+			// 出错时同样输出已处理的结果
+			results, reencryptErr := application.EncryptionService.Reencrypt(cmd.Context(), decrypt)
+			if err := printJSON(cmd, results); err != nil {
+				return err
+			}
+			return reencryptErr
+		},
+	}
+	reencrypt.Flags().BoolVar(&decrypt, "decrypt", false, "还原为明文")
+
+	cmd.AddCommand(reencrypt)
+	return cmd
+}
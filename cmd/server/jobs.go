@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newJobsCommand 创建 jobs 命令
+func newJobsCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "后台任务",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "列出全部任务",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				application, err := opts.loadApp()
+				if err != nil {
+					return err
+				}
+				defer application.Close()
+
+				jobs := application.Jobs.List()
+				if len(jobs) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "没有已注册的任务")
+					return nil
+				}
+				for _, job := range jobs {
+					interval := "手动"
+					if job.Interval > 0 {
+						interval = job.Interval.String()
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "%-30s %-10s %s\n", job.Name, interval, job.Description)
+				}
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "run <name>",
+			Short: "立即执行指定任务",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				application, err := opts.loadApp()
+				if err != nil {
+					return err
+				}
+				defer application.Close()
+
+				start := time.Now()
+				if err := application.Jobs.Run(cmd.Context(), args[0]); err != nil {
+					return err
+				}
+				slog.Info("任务执行完成", slog.String("job", args[0]), slog.Duration("elapsed", time.Since(start)))
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
+
+// printJSON 以缩进 JSON 格式输出结果
+func printJSON(cmd *cobra.Command, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), string(data))
+	return nil
+}
@@ -1,19 +1,7 @@
 package main
 
 import (
-	"context"
-	"fmt"
-	"log"
-	"net/http"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/deantook/dove/internal/config"
-	"github.com/deantook/dove/pkg/redis"
-	"github.com/deantook/dove/wire"
-	"github.com/gin-gonic/gin"
 )
 
 // @title           dove API
@@ -31,63 +19,13 @@ import (
 // @host      localhost:8080
 // @BasePath  /api/v1
 
-func main() {
-	// 加载配置
-	configPath := "configs/config.yaml"
-	if len(os.Args) > 1 {
-		configPath = os.Args[1]
-	}
-
-	cfg, err := config.Load(configPath)
-	if err != nil {
-		log.Fatalf("加载配置失败: %v", err)
-	}
-
-	// 设置 Gin 模式
-	gin.SetMode(cfg.Server.Mode)
-
-	// 使用 Wire 初始化服务器
-	engine, err := wire.InitializeServer(cfg)
-	if err != nil {
-		log.Fatalf("初始化服务器失败: %v", err)
-	}
-
-	// 创建 HTTP 服务器
-	srv := &http.Server{
-		Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler:        engine,
-		ReadTimeout:    time.Duration(cfg.Server.ReadTimeout) * time.Second,
-		WriteTimeout:   time.Duration(cfg.Server.WriteTimeout) * time.Second,
-		MaxHeaderBytes: 1 << 20,
-	}
-
-	// 启动服务器（在 goroutine 中）
-	go func() {
-		log.Printf("服务器启动在端口 %d", cfg.Server.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("服务器启动失败: %v", err)
-		}
-	}()
-
-	// 等待中断信号以优雅地关闭服务器
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	log.Println("正在关闭服务器...")
-
-	// 设置 5 秒的超时时间用于关闭服务器
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Bearer <token>
 
-	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("服务器强制关闭: %v", err)
-	}
-
-	// 关闭 Redis 连接
-	if err := redis.Close(); err != nil {
-		log.Printf("关闭 Redis 连接失败: %v", err)
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
-
-	log.Println("服务器已关闭")
 }
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/deantook/dove/pkg/migrate"
+	"github.com/deantook/dove/wire"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCommand 创建 migrate 命令
+func newMigrateCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "数据库迁移",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "up [N]",
+			Short: "执行未应用的迁移（默认全部）",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps, err := parseSteps(args)
+				if err != nil {
+					return err
+				}
+				return withMigrator(opts, func(migrator *migrate.Migrator) error {
+					executed, err := migrator.Up(cmd.Context(), steps)
+					if err != nil {
+						return err
+					}
+					slog.Info("迁移执行完成", slog.Int("executed", len(executed)))
+					return nil
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down [N]",
+			Short: "回滚最近的迁移（默认 1 个）",
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				steps, err := parseSteps(args)
+				if err != nil {
+					return err
+				}
+				return withMigrator(opts, func(migrator *migrate.Migrator) error {
+					rolledBack, err := migrator.Down(cmd.Context(), steps)
+					if err != nil {
+						return err
+					}
+					slog.Info("迁移回滚完成", slog.Int("rolled_back", len(rolledBack)))
+					return nil
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "redo",
+			Short: "回滚并重新执行最近一次迁移",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withMigrator(opts, func(migrator *migrate.Migrator) error {
+					redone, err := migrator.Redo(cmd.Context())
+					if err != nil {
+						return err
+					}
+					if redone == nil {
+						slog.Info("没有可以重新执行的迁移")
+					}
+					return nil
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "查看迁移状态",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return withMigrator(opts, func(migrator *migrate.Migrator) error {
+					statuses, err := migrator.Status(cmd.Context())
+					if err != nil {
+						return err
+					}
+					printMigrationStatus(cmd, statuses)
+					return nil
+				})
+			},
+		},
+	)
+
+	return cmd
+}
+
+// withMigrator 初始化迁移器并执行 fn，只连接数据库
+func withMigrator(opts *rootOptions, fn func(migrator *migrate.Migrator) error) error {
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	migrator, err := wire.InitializeMigrator(cfg)
+	if err != nil {
+		return fmt.Errorf("初始化迁移器失败: %w", err)
+	}
+	defer migrator.Close()
+
+	return fn(migrator)
+}
+
+// parseSteps 解析迁移数量参数
+func parseSteps(args []string) (int, error) {
+	if len(args) == 0 {
+		return 0, nil
+	}
+	steps, err := strconv.Atoi(args[0])
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("无效的迁移数量: %s", args[0])
+	}
+	return steps, nil
+}
+
+// printMigrationStatus 打印迁移状态表
+func printMigrationStatus(cmd *cobra.Command, statuses []migrate.Status) {
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "%-8s %-45s %-10s %s\n", "VERSION", "NAME", "STATE", "APPLIED AT")
+	for _, status := range statuses {
+		state := "pending"
+		appliedAt := "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.ChecksumMismatch {
+			state = "modified"
+		}
+		if status.Missing {
+			state = "missing"
+		}
+		fmt.Fprintf(out, "%-8d %-45s %-10s %s\n", status.Version, status.Name, state, appliedAt)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/deantook/dove/internal/app"
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/wire"
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+)
+
+// rootOptions 全局参数
+type rootOptions struct {
+	configPath string
+	logLevel   string
+}
+
+// newRootCommand 创建根命令
+func newRootCommand() *cobra.Command {
+	opts := &rootOptions{}
+
+	cmd := &cobra.Command{
+		Use:          "dove",
+		Short:        "dove 服务",
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		// 未指定子命令时启动 HTTP 服务，兼容旧的 `server <配置文件>` 用法
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				opts.configPath = args[0]
+			}
+			return runServe(opts)
+		},
+	}
+
+	cmd.PersistentFlags().StringVarP(&opts.configPath, "config", "c", "configs/config.yaml", "配置文件路径")
+	cmd.PersistentFlags().StringVar(&opts.logLevel, "log-level", "", "日志级别（debug、info、warn、error），覆盖配置文件中的 log.level")
+
+	cmd.AddCommand(
+		newServeCommand(opts),
+		newMigrateCommand(opts),
+		newSeedCommand(opts),
+		newConfigCommand(opts),
+		newUserCommand(opts),
+		newTemplatesCommand(opts),
+		newJobsCommand(opts),
+		newEncryptionCommand(opts),
+	)
+
+	return cmd
+}
+
+// loadConfig 加载并校验配置，应用全局参数
+func (o *rootOptions) loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(o.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %w", err)
+	}
+	o.applyOverrides(cfg)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("配置校验失败:\n%w", err)
+	}
+
+	logger.Init(&cfg.Log)
+	slog.Debug("当前配置", slog.Any("config", cfg))
+	return cfg, nil
+}
+
+// loadApp 加载配置并初始化应用依赖
+func (o *rootOptions) loadApp() (*app.App, error) {
+	cfg, err := o.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return o.initApp(cfg)
+}
+
+// applyOverrides 应用命令行参数对配置的覆盖，重新加载配置时同样生效
+func (o *rootOptions) applyOverrides(cfg *config.Config) {
+	if o.logLevel != "" {
+		cfg.Log.Level = o.logLevel
+	}
+}
+
+// initApp 创建配置提供者并初始化应用依赖
+func (o *rootOptions) initApp(cfg *config.Config) (*app.App, error) {
+	gin.SetMode(cfg.Server.Mode)
+
+	provider := config.NewProvider(o.configPath, cfg, o.applyOverrides)
+	provider.Subscribe(func(old, new *config.Config) {
+		if old.Log.Level != new.Log.Level {
+			logger.SetLevel(new.Log.Level)
+			slog.Info("日志级别已更新", slog.String("from", old.Log.Level), slog.String("to", new.Log.Level))
+		}
+	})
+
+	application, err := wire.InitializeApp(provider)
+	if err != nil {
+		return nil, fmt.Errorf("初始化应用失败: %w", err)
+	}
+	return application, nil
+}
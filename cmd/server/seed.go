@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/migrations"
+	"github.com/spf13/cobra"
+)
+
+// newSeedCommand 创建 seed 命令
+func newSeedCommand(opts *rootOptions) *cobra.Command {
+	var (
+		dryRun bool
+		update bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "写入系统预设字段模板",
+		Long:  "导入内嵌的 " + migrations.TemplateSeedFile + "。默认只新建不存在的模板，--update 时同时更新已存在的模板",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := migrations.SeedFS.ReadFile(migrations.TemplateSeedFile)
+			if err != nil {
+				return fmt.Errorf("读取种子文件失败: %w", err)
+			}
+			bundle, err := service.DecodeTemplateBundle(data, service.BundleFormatYAML)
+			if err != nil {
+				return err
+			}
+
+			application, err := opts.loadApp()
+			if err != nil {
+				return err
+			}
+			defer application.Close()
+
+			result, err := application.TemplateService.ImportTemplates(cmd.Context(), bundle, service.ImportTemplatesOptions{
+				DryRun:     dryRun,
+				CreateOnly: !update,
+			})
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, result)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "仅预览变更，不写入数据库")
+	cmd.Flags().BoolVar(&update, "update", false, "同时更新已存在的模板")
+
+	return cmd
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/job"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/wire"
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand 创建 serve 命令
+func newServeCommand(opts *rootOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "启动 HTTP 服务",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(opts)
+		},
+	}
+}
+
+// runServe 启动 HTTP 服务并等待退出信号
+func runServe(opts *rootOptions) error {
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// 自动执行数据库迁移
+	if cfg.Database.AutoMigrate {
+		if err := runAutoMigrate(cfg); err != nil {
+			return fmt.Errorf("数据库迁移失败: %w", err)
+		}
+	}
+
+	application, err := opts.initApp(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := application.Close(); err != nil {
+			slog.Error("关闭连接失败", slog.Any("error", err))
+		}
+	}()
+
+	// 创建 HTTP 服务器
+	srv := &http.Server{
+		Addr:           fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:        application.Engine,
+		ReadTimeout:    time.Duration(cfg.Server.ReadTimeout) * time.Second,
+		WriteTimeout:   time.Duration(cfg.Server.WriteTimeout) * time.Second,
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	// 启动服务器（在 goroutine 中）
+	serveErr := make(chan error, 2)
+	go func() {
+		slog.Info("服务器启动", slog.Int("port", cfg.Server.Port))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	// 指标配置了独立监听地址时单独启动，避免暴露在公网端口上
+	var metricsSrv *http.Server
+	if cfg.Metrics.Enabled && cfg.Metrics.Addr != "" {
+		mux := http.NewServeMux()
+		mux.Handle(cfg.Metrics.GetPath(), metrics.Handler(application.Metrics))
+		metricsSrv = &http.Server{
+			Addr:              cfg.Metrics.Addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			slog.Info("指标服务启动", slog.String("addr", cfg.Metrics.Addr), slog.String("path", cfg.Metrics.GetPath()))
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				serveErr <- fmt.Errorf("指标服务: %w", err)
+			}
+		}()
+	}
+
+	// 监听配置文件变更和 SIGHUP，热更新可在线生效的配置
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go application.ConfigProvider.Watch(watchCtx)
+
+	// 定时任务
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var scheduler *job.Scheduler
+	if cfg.Jobs.Enabled {
+		scheduler = job.NewScheduler(application.Jobs, application.Redis, cfg.Jobs.GetIntervals())
+		scheduler.Start(jobCtx)
+	}
+
+	// 等待中断信号以优雅地关闭服务器
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("服务器启动失败: %w", err)
+	case <-quit:
+	}
+
+	// 先标记未就绪，等待负载均衡摘除流量后再停止接收请求
+	application.Health.SetShuttingDown()
+	if drain := time.Duration(cfg.Health.DrainDelay) * time.Second; drain > 0 {
+		slog.Info("等待流量摘除", slog.Duration("drain_delay", drain))
+		time.Sleep(drain)
+	}
+
+	slog.Info("正在关闭服务器...")
+
+	// 设置 5 秒的超时时间用于关闭服务器
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if metricsSrv != nil {
+		if err := metricsSrv.Shutdown(ctx); err != nil {
+			slog.Error("指标服务关闭失败", slog.Any("error", err))
+		}
+	}
+
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("服务器强制关闭: %w", err)
+	}
+
+	// 停止调度并等待正在执行的定时任务结束，避免关闭数据库连接时任务仍在执行
+	stopJobs()
+	if scheduler != nil {
+		scheduler.Wait()
+	}
+
+	slog.Info("服务器已关闭")
+	return nil
+}
+
+// runAutoMigrate 执行未应用的数据库迁移
+// 多个副本同时启动时由迁移器的咨询锁保证只有一个副本执行
+func runAutoMigrate(cfg *config.Config) error {
+	migrator, err := wire.InitializeMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	executed, err := migrator.Up(ctx, 0)
+	if err != nil {
+		return err
+	}
+	slog.Info("数据库迁移完成", slog.Int("executed", len(executed)))
+	return nil
+}
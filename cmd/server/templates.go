@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deantook/dove/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// newTemplatesCommand 创建 templates 命令
+func newTemplatesCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "templates",
+		Short: "资料字段模板导入导出",
+	}
+	cmd.AddCommand(newTemplatesExportCommand(opts), newTemplatesImportCommand(opts))
+	return cmd
+}
+
+// newTemplatesExportCommand 创建 templates export 命令
+func newTemplatesExportCommand(opts *rootOptions) *cobra.Command {
+	var (
+		output    string
+		format    string
+		category  string
+		fieldType string
+		active    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "导出字段模板",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" && output != "" {
+				format = strings.TrimPrefix(filepath.Ext(output), ".")
+			}
+			bundleFormat, err := service.ParseBundleFormat(format)
+			if err != nil {
+				return err
+			}
+
+			var isActive *bool
+			switch active {
+			case "":
+			case "true", "false":
+				value := active == "true"
+				isActive = &value
+			default:
+				return fmt.Errorf("无效的 --active 参数: %s", active)
+			}
+
+			application, err := opts.loadApp()
+			if err != nil {
+				return err
+			}
+			defer application.Close()
+
+			bundle, err := application.TemplateService.ExportTemplates(cmd.Context(), category, fieldType, isActive)
+			if err != nil {
+				return err
+			}
+			data, err := service.EncodeTemplateBundle(bundle, bundleFormat)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o644); err != nil {
+				return fmt.Errorf("写入文件失败: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "已导出 %d 个字段模板到 %s\n", len(bundle.Templates), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "输出文件（默认输出到标准输出）")
+	cmd.Flags().StringVar(&format, "format", "", "文件格式（json、yaml），默认根据输出文件扩展名判断")
+	cmd.Flags().StringVar(&category, "category", "", "按字段分类过滤")
+	cmd.Flags().StringVar(&fieldType, "field-type", "", "按字段类型过滤")
+	cmd.Flags().StringVar(&active, "active", "", "按启用状态过滤（true、false）")
+
+	return cmd
+}
+
+// newTemplatesImportCommand 创建 templates import 命令
+func newTemplatesImportCommand(opts *rootOptions) *cobra.Command {
+	var (
+		format   string
+		importOp service.ImportTemplatesOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "导入字段模板",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = strings.TrimPrefix(filepath.Ext(args[0]), ".")
+			}
+			bundleFormat, err := service.ParseBundleFormat(format)
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("读取文件失败: %w", err)
+			}
+			bundle, err := service.DecodeTemplateBundle(data, bundleFormat)
+			if err != nil {
+				return err
+			}
+
+			application, err := opts.loadApp()
+			if err != nil {
+				return err
+			}
+			defer application.Close()
+
+			result, err := application.TemplateService.ImportTemplates(cmd.Context(), bundle, importOp)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, result)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "", "文件格式（json、yaml），默认根据文件扩展名判断")
+	cmd.Flags().BoolVar(&importOp.DryRun, "dry-run", false, "仅预览变更，不写入数据库")
+	cmd.Flags().BoolVar(&importOp.DeactivateMissing, "deactivate-missing", false, "停用文件中不存在的模板")
+	cmd.Flags().BoolVar(&importOp.CreateOnly, "create-only", false, "只新建不存在的模板")
+
+	return cmd
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+)
+
+// newUserCommand 创建 user 命令
+func newUserCommand(opts *rootOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "用户管理",
+	}
+
+	var phone, username string
+	createAdmin := &cobra.Command{
+		Use:   "create-admin",
+		Short: "创建管理员（手机号已注册时提升为管理员）",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// 手机号按配置的地区规则在 CreateAdmin 中校验并规范化
+			if username != "" {
+				if err := validator.New().Var(username, "min=3,max=50"); err != nil {
+					return fmt.Errorf("用户名长度必须在 3 到 50 之间: %s", username)
+				}
+			}
+
+			application, err := opts.loadApp()
+			if err != nil {
+				return err
+			}
+			defer application.Close()
+
+			user, err := application.UserService.CreateAdmin(cmd.Context(), phone, username)
+			if err != nil {
+				return err
+			}
+			return printJSON(cmd, user)
+		},
+	}
+	createAdmin.Flags().StringVar(&phone, "phone", "", "手机号（E.164 格式，如 +8613800138000；默认地区的号码可省略国家码）")
+	createAdmin.Flags().StringVar(&username, "username", "", "用户名（默认随机生成，如 user_3f9a1c2b4d5e）")
+	_ = createAdmin.MarkFlagRequired("phone")
+
+	cmd.AddCommand(createAdmin)
+	return cmd
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/job"
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/health"
+	"github.com/deantook/dove/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// App 应用依赖集合
+// 由 Wire 初始化，HTTP 服务和命令行子命令共用同一套依赖
+type App struct {
+	Config            *config.Config
+	ConfigProvider    *config.Provider
+	DB                *gorm.DB
+	Redis             *redis.Client
+	Engine            *gin.Engine
+	UserService       service.UserService
+	TemplateService   service.ProfileFieldTemplateService
+	EncryptionService service.EncryptionService
+	Jobs              *job.Registry
+	Metrics           *prometheus.Registry
+	Tracing           *tracing.Provider
+	Health            *health.Checker
+}
+
+// New 创建应用依赖集合
+func New(
+	cfg *config.Config,
+	configProvider *config.Provider,
+	db *gorm.DB,
+	redisClient *redis.Client,
+	engine *gin.Engine,
+	userService service.UserService,
+	templateService service.ProfileFieldTemplateService,
+	encryptionService service.EncryptionService,
+	jobs *job.Registry,
+	metricsRegistry *prometheus.Registry,
+	tracer *tracing.Provider,
+	checker *health.Checker,
+) *App {
+	return &App{
+		Config:            cfg,
+		ConfigProvider:    configProvider,
+		DB:                db,
+		Redis:             redisClient,
+		Engine:            engine,
+		UserService:       userService,
+		TemplateService:   templateService,
+		EncryptionService: encryptionService,
+		Jobs:              jobs,
+		Metrics:           metricsRegistry,
+		Tracing:           tracer,
+		Health:            checker,
+	}
+}
+
+// Close 导出剩余的追踪数据并关闭数据库和 Redis 连接
+func (a *App) Close() error {
+	var errs []error
+	if a.Tracing != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := a.Tracing.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if a.Redis != nil {
+		if err := a.Redis.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if a.DB != nil {
+		if sqlDB, err := a.DB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
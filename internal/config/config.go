@@ -2,102 +2,367 @@ package config
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config 应用配置结构体
+// 字段标签说明：
+//   - default: 未配置时的默认值
+//   - validate: 校验规则，见 Validate
+//   - secret: 敏感字段，日志和打印时脱敏
+//   - reload: 值为 live 时支持热更新，见 Provider
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Redis      RedisConfig      `mapstructure:"redis"`
+	JWT        JWTConfig        `mapstructure:"jwt"`
+	Log        LogConfig        `mapstructure:"log"`
+	Metrics    MetricsConfig    `mapstructure:"metrics"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
+	Health     HealthConfig     `mapstructure:"health"`
+	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	CORS       CORSConfig       `mapstructure:"cors" reload:"live"`
+	Cache      CacheConfig      `mapstructure:"cache"`
+	Pagination PaginationConfig `mapstructure:"pagination"`
+	Storage    StorageConfig    `mapstructure:"storage"`
+	Upload     UploadConfig     `mapstructure:"upload"`
+	Phone      PhoneConfig      `mapstructure:"phone"`
+	SMS        SMSConfig        `mapstructure:"sms"`
+	Jobs       JobsConfig       `mapstructure:"jobs"`
+	User       UserConfig       `mapstructure:"user"`
+	DataExport DataExportConfig `mapstructure:"data_export"`
+	Audit      AuditConfig      `mapstructure:"audit"`
+	Encryption EncryptionConfig `mapstructure:"encryption"`
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Port         int    `mapstructure:"port"`
-	Mode         string `mapstructure:"mode"` // debug, release, test
-	ReadTimeout  int    `mapstructure:"read_timeout"`
-	WriteTimeout int    `mapstructure:"write_timeout"`
+	Port           int      `mapstructure:"port" default:"8080" validate:"min=1,max=65535"`
+	Mode           string   `mapstructure:"mode" default:"debug" validate:"oneof=debug release test"`
+	ReadTimeout    int      `mapstructure:"read_timeout" default:"30" validate:"gte=0"`  // 秒
+	WriteTimeout   int      `mapstructure:"write_timeout" default:"30" validate:"gte=0"` // 秒
+	TrustedProxies []string `mapstructure:"trusted_proxies" validate:"dive,ip|cidr"`     // 可信反向代理的 IP 或 CIDR，为空时不信任任何代理，客户端 IP 为连接的对端地址
 }
 
 // DatabaseConfig 数据库配置
 type DatabaseConfig struct {
-	Host            string `mapstructure:"host"`
-	Port            string `mapstructure:"port"`
-	User            string `mapstructure:"user"`
-	Password        string `mapstructure:"password"`
-	DBName          string `mapstructure:"dbname"`
-	MaxOpenConns    int    `mapstructure:"max_open_conns"`
-	MaxIdleConns    int    `mapstructure:"max_idle_conns"`
-	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime"` // 秒
+	Host            string `mapstructure:"host" validate:"required"`
+	Port            string `mapstructure:"port" default:"3306" validate:"required,numeric"`
+	User            string `mapstructure:"user" validate:"required"`
+	Password        string `mapstructure:"password" secret:"true"`
+	DBName          string `mapstructure:"dbname" validate:"required"`
+	MaxOpenConns    int    `mapstructure:"max_open_conns" default:"100" validate:"gte=0"`
+	MaxIdleConns    int    `mapstructure:"max_idle_conns" default:"10" validate:"gte=0"`
+	ConnMaxLifetime int    `mapstructure:"conn_max_lifetime" default:"3600" validate:"gte=0"` // 秒
+	AutoMigrate     bool   `mapstructure:"auto_migrate"`                                      // 启动时自动执行数据库迁移
+	SlowThreshold   int    `mapstructure:"slow_threshold" validate:"gte=0"`                   // 慢查询阈值（毫秒），0 表示不记录
+	LogParams       bool   `mapstructure:"log_params"`                                        // SQL 日志中打印参数值，默认以占位符代替
+	QueryTimeout    int    `mapstructure:"query_timeout" default:"5000" validate:"gte=0"`     // 单条 SQL 超时（毫秒），0 表示仅受请求上下文控制
+	TxMaxRetries    int    `mapstructure:"tx_max_retries" default:"3" validate:"gte=0"`       // 事务遇到死锁时的最大重试次数
+	TxRetryBackoff  int    `mapstructure:"tx_retry_backoff" default:"50" validate:"gt=0"`     // 事务重试的初始退避时间（毫秒），每次翻倍
 }
 
 // RedisConfig Redis 配置
 type RedisConfig struct {
-	Host     string `mapstructure:"host"`
-	Port     string `mapstructure:"port"`
-	Password string `mapstructure:"password"`
-	DB       int    `mapstructure:"db"`
-	PoolSize int    `mapstructure:"pool_size"`
+	Host     string `mapstructure:"host" validate:"required"`
+	Port     string `mapstructure:"port" default:"6379" validate:"required,numeric"`
+	Password string `mapstructure:"password" secret:"true"`
+	DB       int    `mapstructure:"db" validate:"gte=0"`
+	PoolSize int    `mapstructure:"pool_size" default:"10" validate:"gte=0"`
 }
 
-var globalConfig *Config
+// InsecureJWTSecret 历史版本内置的 JWT 密钥，已公开，不能用于签发 token
+const InsecureJWTSecret = "dove-secret-key-change-in-production"
+
+// JWTConfig JWT 配置
+type JWTConfig struct {
+	Secret string `mapstructure:"secret" validate:"required" secret:"true"` // HS256 签名密钥，多副本部署时必须一致，修改后已签发的 token 全部失效
+	Expire int    `mapstructure:"expire" default:"168" validate:"gt=0"`     // token 有效期（小时）
+}
+
+// GetExpire 获取 token 有效期
+func (c *JWTConfig) GetExpire() time.Duration {
+	return time.Duration(c.Expire) * time.Hour
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level  string `mapstructure:"level" default:"info" validate:"oneof=debug info warn error" reload:"live"`
+	Format string `mapstructure:"format" default:"json" validate:"oneof=json text"`
+}
+
+// MetricsConfig Prometheus 指标配置
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Addr    string `mapstructure:"addr"`                                            // 独立监听地址，如 ":9090"；为空时挂载在主服务端口上
+	Path    string `mapstructure:"path" default:"/metrics" validate:"startswith=/"` // 指标路径
+}
+
+// GetPath 获取指标路径
+func (c *MetricsConfig) GetPath() string {
+	if c.Path == "" {
+		return "/metrics"
+	}
+	return c.Path
+}
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	ServiceName string  `mapstructure:"service_name" default:"dove"`
+	Exporter    string  `mapstructure:"exporter" default:"otlp" validate:"oneof=otlp stdout file"`
+	Endpoint    string  `mapstructure:"endpoint"`                                           // OTLP 地址，如 localhost:4317；为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT
+	Protocol    string  `mapstructure:"protocol" default:"grpc" validate:"oneof=grpc http"` // OTLP 协议
+	Insecure    bool    `mapstructure:"insecure"`                                           // OTLP 不使用 TLS
+	FilePath    string  `mapstructure:"file_path"`                                          // file 导出器的输出文件
+	SampleRatio float64 `mapstructure:"sample_ratio" validate:"gte=0,lte=1"`                // 采样率 0~1，上游已采样的请求始终跟随上游
+}
+
+// GetServiceName 获取服务名
+func (c *TracingConfig) GetServiceName() string {
+	if c.ServiceName == "" {
+		return "dove"
+	}
+	return c.ServiceName
+}
+
+// HealthConfig 健康检查配置
+type HealthConfig struct {
+	CheckTimeout int `mapstructure:"check_timeout" default:"1000" validate:"gte=0"` // 单项依赖检查超时（毫秒）
+	DrainDelay   int `mapstructure:"drain_delay" validate:"gte=0"`                  // 收到退出信号后先标记未就绪，等待负载均衡摘除流量的时间（秒）
+}
+
+// GetCheckTimeout 获取单项依赖检查超时
+func (c *HealthConfig) GetCheckTimeout() time.Duration {
+	if c.CheckTimeout <= 0 {
+		return time.Second
+	}
+	return time.Duration(c.CheckTimeout) * time.Millisecond
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	Enabled   bool                       `mapstructure:"enabled" reload:"live"`
+	Backend   string                     `mapstructure:"backend" default:"redis" validate:"oneof=redis memory"` // memory 仅在单副本或测试时使用
+	KeyPrefix string                     `mapstructure:"key_prefix" default:"ratelimit:"`                       // Redis 键前缀
+	Policies  map[string]RateLimitPolicy `mapstructure:"policies" validate:"dive" reload:"live"`                // 按路由组名称声明的限流策略
+}
+
+// RateLimitPolicy 单个路由组的限流策略
+type RateLimitPolicy struct {
+	Algorithm string `mapstructure:"algorithm" validate:"oneof=token_bucket sliding_window"`
+	Limit     int    `mapstructure:"limit" validate:"gt=0"`                // 每个窗口允许的请求数
+	Window    int    `mapstructure:"window" validate:"gt=0"`               // 窗口长度（秒）
+	Burst     int    `mapstructure:"burst" validate:"gte=0"`               // 令牌桶容量，默认等于 limit
+	KeyBy     string `mapstructure:"key_by" default:"ip" validate:"keyby"` // ip, user, header:<名称>
+}
+
+// GetKeyPrefix 获取 Redis 键前缀
+func (c *RateLimitConfig) GetKeyPrefix() string {
+	if c.KeyPrefix == "" {
+		return "ratelimit:"
+	}
+	return c.KeyPrefix
+}
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	KeyPrefix   string  `mapstructure:"key_prefix" default:"cache:"`                 // Redis 键前缀
+	TTL         int     `mapstructure:"ttl" default:"300" validate:"gt=0"`           // 缓存有效期（秒）
+	NegativeTTL int     `mapstructure:"negative_ttl" default:"30" validate:"gte=0"`  // 不存在记录的缓存有效期（秒），0 表示不缓存
+	Jitter      float64 `mapstructure:"jitter" default:"0.1" validate:"gte=0,lte=1"` // 有效期随机浮动比例，避免同时失效
+}
+
+// GetTTL 获取缓存有效期
+func (c *CacheConfig) GetTTL() time.Duration {
+	return time.Duration(c.TTL) * time.Second
+}
+
+// GetNegativeTTL 获取不存在记录的缓存有效期
+func (c *CacheConfig) GetNegativeTTL() time.Duration {
+	return time.Duration(c.NegativeTTL) * time.Second
+}
+
+// PaginationConfig 分页配置
+type PaginationConfig struct {
+	CursorSecret string `mapstructure:"cursor_secret" secret:"true"` // 游标签名密钥，多副本部署时必须配置且一致
+}
+
+// StorageConfig 对象存储配置
+type StorageConfig struct {
+	Driver     string             `mapstructure:"driver" default:"local" validate:"oneof=local s3"`
+	PresignTTL int                `mapstructure:"presign_ttl" default:"900" validate:"gt=0"` // 预签名上传地址有效期（秒）
+	Local      LocalStorageConfig `mapstructure:"local"`
+	S3         S3StorageConfig    `mapstructure:"s3"`
+}
+
+// GetPresignTTL 获取预签名上传地址有效期
+func (c *StorageConfig) GetPresignTTL() time.Duration {
+	return time.Duration(c.PresignTTL) * time.Second
+}
+
+// LocalStorageConfig 本地文件系统存储配置
+type LocalStorageConfig struct {
+	Root        string `mapstructure:"root" default:"data/uploads"`                // 文件存放目录
+	PrivateRoot string `mapstructure:"private_root" default:"data/private"`        // 私有文件（个人数据导出）存放目录，不提供静态访问，不能位于 root 下
+	BaseURL     string `mapstructure:"base_url" default:"/uploads"`                // 文件访问地址前缀，以 / 开头时由本服务提供静态访问
+	UploadURL   string `mapstructure:"upload_url" default:"/api/v1/media/uploads"` // 预签名上传地址前缀
+	DownloadURL string `mapstructure:"download_url" default:"/api/v1/downloads"`   // 预签名下载地址前缀
+	SignSecret  string `mapstructure:"sign_secret" secret:"true"`                  // 预签名地址的签名密钥，未配置时每次启动随机生成
+}
+
+// S3StorageConfig S3 兼容对象存储配置
+type S3StorageConfig struct {
+	Endpoint      string `mapstructure:"endpoint"`                 // 如 s3.amazonaws.com、localhost:9000
+	Region        string `mapstructure:"region"`                   // 区域，MinIO 可留空
+	Bucket        string `mapstructure:"bucket"`                   // 存储桶
+	PrivateBucket string `mapstructure:"private_bucket"`           // 私有文件（个人数据导出）存储桶，不能开放公开读取，为空时使用 bucket，需确保 exports/ 前缀不可公开读取
+	AccessKey     string `mapstructure:"access_key"`               // 访问密钥 ID
+	SecretKey     string `mapstructure:"secret_key" secret:"true"` // 访问密钥
+	UseSSL        bool   `mapstructure:"use_ssl"`                  // 使用 HTTPS 访问
+	PathStyle     bool   `mapstructure:"path_style"`               // 使用路径风格地址（MinIO 等需要开启）
+	PublicURL     string `mapstructure:"public_url"`               // 文件访问地址前缀（如 CDN），为空时使用存储桶地址
+}
+
+// UploadConfig 文件上传配置
+type UploadConfig struct {
+	MaxImageSize  int64 `mapstructure:"max_image_size" default:"5242880" validate:"gt=0"`  // 图片大小上限（字节）
+	MaxVideoSize  int64 `mapstructure:"max_video_size" default:"52428800" validate:"gt=0"` // 视频大小上限（字节）
+	ThumbnailSize int   `mapstructure:"thumbnail_size" default:"256" validate:"gte=0"`     // 缩略图最长边（像素），0 表示不生成
+	OrphanTTL     int   `mapstructure:"orphan_ttl" default:"24" validate:"gt=0"`           // 未被引用的文件保留时间（小时），超过后由 media-gc 任务清理
+}
+
+// GetOrphanTTL 获取未被引用文件的保留时间
+func (c *UploadConfig) GetOrphanTTL() time.Duration {
+	return time.Duration(c.OrphanTTL) * time.Hour
+}
+
+// PhoneConfig 手机号配置
+type PhoneConfig struct {
+	DefaultRegion  string   `mapstructure:"default_region" default:"CN" validate:"region"` // 未带国家码的号码所属地区
+	AllowedRegions []string `mapstructure:"allowed_regions" validate:"dive,region"`        // 允许注册和登录的地区，为空时允许全部支持的地区
+}
+
+// SMSConfig 短信验证码配置
+type SMSConfig struct {
+	ExposeCode  bool `mapstructure:"expose_code"`                              // 在发送验证码接口的响应中返回验证码，仅用于开发和测试
+	MaxAttempts int  `mapstructure:"max_attempts" default:"5" validate:"gt=0"` // 每个验证码允许输错的次数，超过后验证码失效
+}
+
+// UserConfig 用户配置
+type UserConfig struct {
+	PurgeAfter int `mapstructure:"purge_after" default:"30" validate:"gt=0"` // 注销后的保留期（天），期间可由管理员恢复，超过后由 user-purge 任务清除全部数据
+}
+
+// GetPurgeAfter 获取注销用户的保留期
+func (c *UserConfig) GetPurgeAfter() time.Duration {
+	return time.Duration(c.PurgeAfter) * 24 * time.Hour
+}
+
+// DataExportConfig 个人数据导出配置
+type DataExportConfig struct {
+	LinkTTL   int `mapstructure:"link_ttl" default:"3600" validate:"gt=0"` // 下载地址有效期（秒），每次查询导出任务时重新生成
+	Retention int `mapstructure:"retention" default:"72" validate:"gt=0"`  // 导出文件保留时间（小时），超过后由 data-export-cleanup 任务删除
+}
+
+// GetLinkTTL 获取下载地址有效期
+func (c *DataExportConfig) GetLinkTTL() time.Duration {
+	return time.Duration(c.LinkTTL) * time.Second
+}
+
+// GetRetention 获取导出文件保留时间
+func (c *DataExportConfig) GetRetention() time.Duration {
+	return time.Duration(c.Retention) * time.Hour
+}
+
+// AuditConfig 审计日志配置
+type AuditConfig struct {
+	Retention int `mapstructure:"retention" default:"180" validate:"gt=0"` // 审计日志保留时间（天），超过后由 audit-log-purge 任务删除
+}
+
+// GetRetention 获取审计日志保留时间
+func (c *AuditConfig) GetRetention() time.Duration {
+	return time.Duration(c.Retention) * 24 * time.Hour
+}
+
+// EncryptionConfig 敏感字段加密配置
+// 密钥为 base64 编码的 32 字节随机数，密文中记录加密时使用的密钥 ID，更换 active_key 后旧密钥需保留到 encryption reencrypt 执行完成
+type EncryptionConfig struct {
+	ActiveKey string            `mapstructure:"active_key" default:"v1" validate:"required"`          // 加密新数据使用的密钥 ID
+	Keys      map[string]string `mapstructure:"keys" validate:"required,dive,required" secret:"true"` // 密钥 ID 到密钥，ID 不区分大小写
+	IndexKey  string            `mapstructure:"index_key" validate:"required" secret:"true"`          // 盲索引（HMAC-SHA256）密钥，用于按手机号查询，设置后不能更改
+}
+
+// JobsConfig 定时任务配置
+type JobsConfig struct {
+	Enabled   bool           `mapstructure:"enabled"`                         // serve 进程中执行定时任务
+	Intervals map[string]int `mapstructure:"intervals" validate:"dive,gte=0"` // 按任务名称覆盖执行间隔（秒），0 表示不定时执行
+}
+
+// GetIntervals 获取按任务名称覆盖的执行间隔
+func (c *JobsConfig) GetIntervals() map[string]time.Duration {
+	intervals := make(map[string]time.Duration, len(c.Intervals))
+	for name, seconds := range c.Intervals {
+		intervals[name] = time.Duration(seconds) * time.Second
+	}
+	return intervals
+}
 
 // Load 加载配置
+// 依次执行：读取文件和 APP_ 前缀环境变量覆盖、展开 ${VAR:-default} 占位符、解析、填充默认值
+// 校验由调用方通过 Validate 完成
 func Load(configPath string) (*Config, error) {
-	viper.SetConfigType("yaml")
-	viper.SetConfigFile(configPath)
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.SetConfigFile(configPath)
 
-	// 支持环境变量覆盖
-	viper.AutomaticEnv()
+	// 支持 APP_SERVER_PORT 形式的环境变量覆盖
+	v.SetEnvPrefix("APP")
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 
-	// 设置环境变量前缀（可选）
-	viper.SetEnvPrefix("APP")
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
 
-	// 设置环境变量替换函数，支持 ${VAR} 格式
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	// 在解析前展开占位符，非字符串字段（如 server.port）也可以使用环境变量
+	settings, err := expandSettings(v.AllSettings())
+	if err != nil {
+		return nil, fmt.Errorf("展开环境变量失败: %w", err)
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	expanded := viper.New()
+	if err := expanded.MergeConfigMap(settings); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	if err := expanded.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
-	// 替换配置结构体中的环境变量占位符
-	expandConfigEnvVars(&config)
+	if err := applyDefaults(&config); err != nil {
+		return nil, fmt.Errorf("填充默认配置失败: %w", err)
+	}
+	config.applyCORSDefaults()
 
-	globalConfig = &config
-	log.Printf("配置文件加载成功: %s", configPath)
+	slog.Info("配置文件加载成功", slog.String("path", configPath))
 	return &config, nil
 }
 
-// expandConfigEnvVars 展开配置结构体中的环境变量
-func expandConfigEnvVars(cfg *Config) {
-	// 展开数据库配置中的环境变量
-	cfg.Database.Port = os.ExpandEnv(cfg.Database.Port)
-	cfg.Database.Host = os.ExpandEnv(cfg.Database.Host)
-	cfg.Database.User = os.ExpandEnv(cfg.Database.User)
-	cfg.Database.Password = os.ExpandEnv(cfg.Database.Password)
-	cfg.Database.DBName = os.ExpandEnv(cfg.Database.DBName)
-
-	// 展开服务器配置中的环境变量
-	cfg.Server.Mode = os.ExpandEnv(cfg.Server.Mode)
-
-	// 展开 Redis 配置中的环境变量
-	cfg.Redis.Host = os.ExpandEnv(cfg.Redis.Host)
-	cfg.Redis.Password = os.ExpandEnv(cfg.Redis.Password)
+// GetQueryTimeout 获取单条 SQL 超时
+func (c *DatabaseConfig) GetQueryTimeout() time.Duration {
+	return time.Duration(c.QueryTimeout) * time.Millisecond
 }
 
-// Get 获取全局配置
-func Get() *Config {
-	return globalConfig
+// GetTxRetryBackoff 获取事务重试的初始退避时间
+func (c *DatabaseConfig) GetTxRetryBackoff() time.Duration {
+	return time.Duration(c.TxRetryBackoff) * time.Millisecond
 }
 
 // GetDSN 获取数据库连接字符串
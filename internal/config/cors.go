@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	AllowOrigins     []string `mapstructure:"allow_origins" validate:"dive,origin"`                                       // 精确来源如 https://app.example.com，或子域名通配如 https://*.example.com
+	AllowMethods     []string `mapstructure:"allow_methods" validate:"dive,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"` // 允许的请求方法
+	AllowHeaders     []string `mapstructure:"allow_headers"`                                                              // 允许的请求头
+	ExposeHeaders    []string `mapstructure:"expose_headers"`                                                             // 暴露给浏览器的响应头，请求 ID 和限流响应头始终暴露
+	AllowCredentials bool     `mapstructure:"allow_credentials"`                                                          // 允许携带凭证，不能与 * 同时使用
+	MaxAge           int      `mapstructure:"max_age" default:"43200" validate:"gte=0"`                                   // 预检结果缓存时间（秒）
+}
+
+// 跨域默认配置
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{
+		"Origin", "Content-Type", "Content-Length", "Accept", "Accept-Encoding",
+		"Authorization", "Cache-Control", "X-Requested-With", "X-Request-ID",
+	}
+	defaultCORSExposeHeaders = []string{"Content-Length"}
+	// defaultCORSDevOrigins debug 和 test 模式下未配置来源时允许的本地开发来源
+	defaultCORSDevOrigins = []string{
+		"http://localhost:3000", "http://127.0.0.1:3000",
+		"http://localhost:5173", "http://127.0.0.1:5173",
+		"http://localhost:8080", "http://127.0.0.1:8080",
+	}
+)
+
+// applyCORSDefaults 填充未配置的跨域选项
+// release 模式下未配置来源时不允许任何跨域请求
+func (c *Config) applyCORSDefaults() {
+	cors := &c.CORS
+	if len(cors.AllowOrigins) == 0 && c.Server.Mode != "release" {
+		cors.AllowOrigins = defaultCORSDevOrigins
+	}
+	if len(cors.AllowMethods) == 0 {
+		cors.AllowMethods = defaultCORSMethods
+	}
+	if len(cors.AllowHeaders) == 0 {
+		cors.AllowHeaders = defaultCORSHeaders
+	}
+	if len(cors.ExposeHeaders) == 0 {
+		cors.ExposeHeaders = defaultCORSExposeHeaders
+	}
+}
+
+// ValidateOriginPattern 校验来源格式：scheme://host[:port]，host 可以以 *. 开头表示任意子域名
+func ValidateOriginPattern(origin string) error {
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok || (scheme != "http" && scheme != "https") {
+		return fmt.Errorf("%q: 仅支持 http 和 https", origin)
+	}
+	host = strings.TrimPrefix(host, "*.")
+	if strings.Contains(host, "*") {
+		return fmt.Errorf("%q: 通配符只能出现在域名开头，如 https://*.example.com", origin)
+	}
+	u, err := url.Parse(scheme + "://" + host)
+	if err != nil {
+		return fmt.Errorf("%q: %w", origin, err)
+	}
+	if u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("%q: 格式应为 scheme://host[:port]", origin)
+	}
+	return nil
+}
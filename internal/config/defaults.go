@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// applyDefaults 根据 default 标签为零值字段填充默认值
+// 递归处理嵌套结构体和值为结构体的 map
+func applyDefaults(cfg *Config) error {
+	return setDefaults(reflect.ValueOf(cfg).Elem(), "")
+}
+
+// setDefaults 为结构体字段填充默认值
+func setDefaults(v reflect.Value, path string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		name := joinPath(path, field.Tag.Get("mapstructure"))
+
+		switch fv.Kind() {
+		case reflect.Struct:
+			if err := setDefaults(fv, name); err != nil {
+				return err
+			}
+			continue
+		case reflect.Map:
+			if fv.Type().Elem().Kind() != reflect.Struct {
+				break
+			}
+			iter := fv.MapRange()
+			for iter.Next() {
+				item := reflect.New(fv.Type().Elem()).Elem()
+				item.Set(iter.Value())
+				if err := setDefaults(item, joinPath(name, iter.Key().String())); err != nil {
+					return err
+				}
+				fv.SetMapIndex(iter.Key(), item)
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok || !fv.IsZero() {
+			continue
+		}
+		if err := setValue(fv, def); err != nil {
+			return fmt.Errorf("%s 的默认值 %q 无效: %w", name, def, err)
+		}
+	}
+	return nil
+}
+
+// setValue 将字符串形式的默认值写入字段
+func setValue(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("不支持的字段类型 %s", fv.Kind())
+	}
+	return nil
+}
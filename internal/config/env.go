@@ -0,0 +1,95 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// fileEnvSuffix 以文件形式提供变量值时的环境变量后缀，如 MYSQLPASSWORD_FILE=/run/secrets/db
+const fileEnvSuffix = "_FILE"
+
+// expandSettings 递归展开配置中所有字符串值的环境变量占位符
+func expandSettings(settings map[string]interface{}) (map[string]interface{}, error) {
+	var errs []error
+	out := expandValue(settings, "", &errs).(map[string]interface{})
+	return out, errors.Join(errs...)
+}
+
+// expandValue 展开单个配置值，path 用于错误信息
+func expandValue(value interface{}, path string, errs *[]error) interface{} {
+	switch v := value.(type) {
+	case string:
+		s, err := expandEnv(v)
+		if err != nil {
+			*errs = append(*errs, fmt.Errorf("%s: %w", path, err))
+		}
+		return s
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			out[key] = expandValue(item, joinPath(path, key), errs)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = expandValue(item, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// joinPath 拼接配置路径
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// expandEnv 展开字符串中的环境变量
+// 支持 $VAR、${VAR}、${VAR:-默认值}（变量未设置或为空时使用默认值），$$ 表示字面量 $
+// 变量未设置时读取 VAR_FILE 指向的文件内容，便于使用以文件挂载的密钥
+func expandEnv(s string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var errs []error
+	out := os.Expand(s, func(expr string) string {
+		if expr == "$" {
+			return "$"
+		}
+		name, def, hasDefault := strings.Cut(expr, ":-")
+		value, err := lookupEnv(name)
+		if err != nil {
+			errs = append(errs, err)
+			return ""
+		}
+		if value == "" && hasDefault {
+			return def
+		}
+		return value
+	})
+	return out, errors.Join(errs...)
+}
+
+// lookupEnv 读取环境变量，未设置时读取 NAME_FILE 指向的文件
+func lookupEnv(name string) (string, error) {
+	if value, ok := os.LookupEnv(name); ok {
+		return value, nil
+	}
+	path, ok := os.LookupEnv(name + fileEnvSuffix)
+	if !ok || path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("读取 %s%s 指向的文件失败: %w", name, fileEnvSuffix, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
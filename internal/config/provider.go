@@ -0,0 +1,203 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadDebounce 文件变更事件的合并时间，编辑器保存时通常会触发多次事件
+const reloadDebounce = 300 * time.Millisecond
+
+// Subscriber 配置变更订阅函数，old 和 new 均为只读快照
+type Subscriber func(old, new *Config)
+
+// ReloadResult 重新加载结果
+type ReloadResult struct {
+	Changed         []string // 已生效的变更
+	RestartRequired []string // 需要重启才能生效的变更
+}
+
+// Provider 配置提供者
+// 持有当前生效的只读配置快照，重新加载时原子替换并通知订阅者
+// 只有带 reload:"live" 标签的配置项可以热更新，其余配置项的变更需要重启，快照中保留原值
+type Provider struct {
+	path      string
+	current   atomic.Pointer[Config]
+	overrides []func(*Config)
+
+	mu          sync.Mutex // 串行化 Reload
+	subMu       sync.RWMutex
+	subscribers []Subscriber
+}
+
+// NewProvider 创建配置提供者
+// overrides 在每次加载后、校验前执行，用于保留命令行参数的覆盖
+func NewProvider(path string, cfg *Config, overrides ...func(*Config)) *Provider {
+	p := &Provider{path: path, overrides: overrides}
+	p.current.Store(cfg)
+	return p
+}
+
+// NewStaticProvider 创建不支持重新加载的配置提供者
+func NewStaticProvider(cfg *Config) *Provider {
+	return NewProvider("", cfg)
+}
+
+// Get 获取当前配置快照，调用方不得修改
+func (p *Provider) Get() *Config {
+	return p.current.Load()
+}
+
+// Subscribe 订阅配置变更，回调在 Reload 所在的 goroutine 中同步执行
+func (p *Provider) Subscribe(fn Subscriber) {
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+	p.subscribers = append(p.subscribers, fn)
+}
+
+// Reload 重新读取并校验配置文件，校验失败时保留当前配置
+func (p *Provider) Reload() (*ReloadResult, error) {
+	if p.path == "" {
+		return nil, fmt.Errorf("配置提供者未关联配置文件")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	next, err := Load(p.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range p.overrides {
+		override(next)
+	}
+	if err := next.Validate(); err != nil {
+		return nil, fmt.Errorf("配置校验失败:\n%w", err)
+	}
+
+	old := p.Get()
+	result := &ReloadResult{}
+	merged := mergeLive(old, next, result)
+
+	if len(result.Changed) > 0 {
+		p.current.Store(merged)
+
+		p.subMu.RLock()
+		subscribers := make([]Subscriber, len(p.subscribers))
+		copy(subscribers, p.subscribers)
+		p.subMu.RUnlock()
+
+		for _, fn := range subscribers {
+			fn(old, merged)
+		}
+	}
+
+	return result, nil
+}
+
+// Watch 监听配置文件变更和 SIGHUP 信号并重新加载，直到 ctx 结束
+func (p *Provider) Watch(ctx context.Context) {
+	if p.path == "" {
+		return
+	}
+
+	trigger := make(chan string, 1)
+	notify := func(reason string) {
+		select {
+		case trigger <- reason:
+		default:
+		}
+	}
+
+	// 文件变更
+	var (
+		timerMu sync.Mutex
+		timer   *time.Timer
+	)
+	v := viper.New()
+	v.SetConfigFile(p.path)
+	v.OnConfigChange(func(e fsnotify.Event) {
+		timerMu.Lock()
+		defer timerMu.Unlock()
+		if timer != nil {
+			timer.Stop()
+		}
+		timer = time.AfterFunc(reloadDebounce, func() { notify("file") })
+	})
+	v.WatchConfig()
+
+	// SIGHUP
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	slog.Info("已开启配置热更新", slog.String("path", p.path))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			notify("sighup")
+		case reason := <-trigger:
+			p.reloadAndLog(reason)
+		}
+	}
+}
+
+// reloadAndLog 重新加载配置并记录结果
+func (p *Provider) reloadAndLog(reason string) {
+	result, err := p.Reload()
+	if err != nil {
+		slog.Error("重新加载配置失败，继续使用当前配置", slog.String("trigger", reason), slog.Any("error", err))
+		return
+	}
+	if len(result.Changed) > 0 {
+		slog.Info("配置已重新加载", slog.String("trigger", reason), slog.Any("changed", result.Changed))
+	}
+	if len(result.RestartRequired) > 0 {
+		slog.Warn("以下配置变更需要重启才能生效", slog.String("trigger", reason), slog.Any("keys", result.RestartRequired))
+	}
+}
+
+// mergeLive 合并新旧配置：可热更新的配置项取新值，其余保留旧值
+func mergeLive(old, next *Config, result *ReloadResult) *Config {
+	merged := *old
+	mergeStruct(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(next).Elem(), "", false, result)
+	return &merged
+}
+
+// mergeStruct 按 reload 标签递归合并结构体字段
+func mergeStruct(dst, src reflect.Value, path string, live bool, result *ReloadResult) {
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := joinPath(path, field.Tag.Get("mapstructure"))
+		fieldLive := live || field.Tag.Get("reload") == "live"
+
+		dv, sv := dst.Field(i), src.Field(i)
+		if dv.Kind() == reflect.Struct {
+			mergeStruct(dv, sv, name, fieldLive, result)
+			continue
+		}
+		if reflect.DeepEqual(dv.Interface(), sv.Interface()) {
+			continue
+		}
+		if fieldLive {
+			dv.Set(sv)
+			result.Changed = append(result.Changed, name)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, name)
+		}
+	}
+}
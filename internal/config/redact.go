@@ -0,0 +1,53 @@
+package config
+
+import (
+	"log/slog"
+	"reflect"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// redactedValue 敏感字段脱敏后的值
+const redactedValue = "******"
+
+// Redacted 返回敏感字段脱敏后的副本
+func (c *Config) Redacted() *Config {
+	clone := *c
+	redact(reflect.ValueOf(&clone).Elem())
+	return &clone
+}
+
+// redact 将 secret 标签的非空字符串字段和字符串 map 的值替换为脱敏值
+// map 替换为新的副本，不修改原配置
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fv := v.Field(i)
+		secret := t.Field(i).Tag.Get("secret") == "true"
+		switch {
+		case fv.Kind() == reflect.Struct:
+			redact(fv)
+		case fv.Kind() == reflect.String && secret && fv.String() != "":
+			fv.SetString(redactedValue)
+		case fv.Kind() == reflect.Map && fv.Type().Elem().Kind() == reflect.String && secret && !fv.IsNil():
+			redacted := reflect.MakeMapWithSize(fv.Type(), fv.Len())
+			iter := fv.MapRange()
+			for iter.Next() {
+				redacted.SetMapIndex(iter.Key(), reflect.ValueOf(redactedValue).Convert(fv.Type().Elem()))
+			}
+			fv.Set(redacted)
+		}
+	}
+}
+
+// ToMap 将脱敏后的配置转换为与配置文件相同键名的 map，用于打印和日志
+func (c *Config) ToMap() map[string]interface{} {
+	out := make(map[string]interface{})
+	_ = mapstructure.Decode(c.Redacted(), &out)
+	return out
+}
+
+// LogValue 实现 slog.LogValuer，记录日志时自动脱敏
+func (c *Config) LogValue() slog.Value {
+	return slog.AnyValue(c.ToMap())
+}
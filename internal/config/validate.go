@@ -0,0 +1,149 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/deantook/dove/pkg/phone"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	validateOnce sync.Once
+	validate     *validator.Validate
+)
+
+// getValidator 获取配置校验器，字段名使用 mapstructure 标签，与配置文件中的键一致
+func getValidator() *validator.Validate {
+	validateOnce.Do(func() {
+		validate = validator.New(validator.WithRequiredStructEnabled())
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := field.Tag.Get("mapstructure")
+			if name == "" || name == "-" {
+				return field.Name
+			}
+			return name
+		})
+		_ = validate.RegisterValidation("keyby", func(fl validator.FieldLevel) bool {
+			keyBy := fl.Field().String()
+			switch {
+			case keyBy == "ip", keyBy == "user":
+				return true
+			case strings.HasPrefix(keyBy, "header:"):
+				return len(keyBy) > len("header:")
+			default:
+				return false
+			}
+		})
+		_ = validate.RegisterValidation("region", func(fl validator.FieldLevel) bool {
+			_, ok := phone.LookupRegion(fl.Field().String())
+			return ok
+		})
+		_ = validate.RegisterValidation("origin", func(fl validator.FieldLevel) bool {
+			origin := fl.Field().String()
+			return origin == "*" || ValidateOriginPattern(origin) == nil
+		})
+	})
+	return validate
+}
+
+// Validate 校验配置，返回全部问题
+// 单字段规则由 validate 标签声明，字段之间的约束在此处检查
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := getValidator().Struct(c); err != nil {
+		var verrs validator.ValidationErrors
+		if !errors.As(err, &verrs) {
+			return err
+		}
+		for _, fe := range verrs {
+			errs = append(errs, translateFieldError(fe))
+		}
+	}
+
+	if c.Tracing.Enabled && c.Tracing.Exporter == "file" && c.Tracing.FilePath == "" {
+		errs = append(errs, errors.New("tracing.file_path 不能为空（exporter 为 file）"))
+	}
+	if c.Storage.Driver == "s3" && (c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "") {
+		errs = append(errs, errors.New("storage.s3.endpoint 和 storage.s3.bucket 不能为空（driver 为 s3）"))
+	}
+	if c.Storage.Driver != "s3" && isSubPath(c.Storage.Local.Root, c.Storage.Local.PrivateRoot) {
+		errs = append(errs, errors.New("storage.local.private_root 不能与 storage.local.root 相同或位于其中"))
+	}
+	if len(c.Phone.AllowedRegions) > 0 && !slices.ContainsFunc(c.Phone.AllowedRegions, func(region string) bool {
+		return strings.EqualFold(region, c.Phone.DefaultRegion)
+	}) {
+		errs = append(errs, fmt.Errorf("phone.default_region %q 必须包含在 phone.allowed_regions 中", c.Phone.DefaultRegion))
+	}
+	if c.JWT.Secret == InsecureJWTSecret {
+		errs = append(errs, errors.New("jwt.secret 不能使用内置的默认密钥"))
+	}
+	if c.SMS.ExposeCode && c.Server.Mode == "release" {
+		errs = append(errs, errors.New("sms.expose_code 不能在 release 模式下开启"))
+	}
+	if _, ok := c.Encryption.Keys[strings.ToLower(c.Encryption.ActiveKey)]; len(c.Encryption.Keys) > 0 && !ok {
+		errs = append(errs, fmt.Errorf("encryption.active_key %q 必须包含在 encryption.keys 中", c.Encryption.ActiveKey))
+	}
+	if c.CORS.AllowCredentials {
+		for _, origin := range c.CORS.AllowOrigins {
+			if origin == "*" {
+				errs = append(errs, errors.New("cors.allow_origins 为 * 时不能开启 allow_credentials"))
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// translateFieldError 将校验错误转换为中文描述
+func translateFieldError(fe validator.FieldError) error {
+	// 去掉根结构体名称，如 Config.server.port -> server.port
+	path := fe.Namespace()
+	if _, rest, ok := strings.Cut(path, "."); ok {
+		path = rest
+	}
+	param := fe.Param()
+
+	switch fe.Tag() {
+	case "required":
+		return fmt.Errorf("%s 不能为空", path)
+	case "numeric":
+		return fmt.Errorf("%s 必须是数字: %q", path, fe.Value())
+	case "min", "gte":
+		return fmt.Errorf("%s 不能小于 %s: %v", path, param, fe.Value())
+	case "max", "lte":
+		return fmt.Errorf("%s 不能大于 %s: %v", path, param, fe.Value())
+	case "gt":
+		return fmt.Errorf("%s 必须大于 %s: %v", path, param, fe.Value())
+	case "oneof":
+		return fmt.Errorf("%s 无效: %q（可选 %s）", path, fe.Value(), strings.ReplaceAll(param, " ", "、"))
+	case "startswith":
+		return fmt.Errorf("%s 必须以 %s 开头: %q", path, param, fe.Value())
+	case "keyby":
+		return fmt.Errorf("%s 无效: %q（可选 ip、user、header:<名称>）", path, fe.Value())
+	case "region":
+		return fmt.Errorf("%s 无效: %q（可选 %s）", path, fe.Value(), strings.Join(phone.SupportedRegions(), "、"))
+	case "ip|cidr":
+		return fmt.Errorf("%s 必须是 IP 地址或 CIDR: %q", path, fe.Value())
+	case "origin":
+		return fmt.Errorf("%s 无效: %q（格式应为 scheme://host[:port]，可使用 https://*.example.com 通配子域名）", path, fe.Value())
+	default:
+		return fmt.Errorf("%s 校验失败: %s", path, fe.Tag())
+	}
+}
+
+// isSubPath 判断 target 是否与 base 相同或位于 base 目录下
+func isSubPath(base, target string) bool {
+	rel, err := filepath.Rel(filepath.Clean(base), filepath.Clean(target))
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
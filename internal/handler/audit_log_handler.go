@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditLogHandler 审计日志处理器
+type AuditLogHandler struct {
+	auditService service.AuditService
+	cursorCodec  *query.CursorCodec
+}
+
+// NewAuditLogHandler 创建审计日志处理器实例
+func NewAuditLogHandler(auditService service.AuditService, cursorCodec *query.CursorCodec) *AuditLogHandler {
+	return &AuditLogHandler{
+		auditService: auditService,
+		cursorCodec:  cursorCodec,
+	}
+}
+
+// ListAuditLogs 获取审计日志列表
+// @Summary 获取审计日志列表
+// @Description 按游标分页获取审计日志，支持按操作人、操作、对象和请求 ID 过滤（仅管理员）
+// @Description 审计日志只追加不修改，超过保留期后自动删除
+// @Tags audit
+// @Produce json
+// @Param cursor query string false "游标（取自上次响应的 next_cursor 或 prev_cursor）"
+// @Param limit query int false "每页数量（最大 100）" default(20)
+// @Param with_total query bool false "是否返回总数"
+// @Param actor_id query int false "操作人 ID，后台任务为 0"
+// @Param action query string false "操作，如 user.update、auth.login"
+// @Param target_type query string false "对象类型（user、profile_field_template）"
+// @Param target_id query int false "对象 ID"
+// @Param request_id query string false "请求 ID"
+// @Param created_from query string false "时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "时间止（RFC3339 或 YYYY-MM-DD，日期包含当天）"
+// @Param sort query string false "排序字段，逗号分隔，前缀 - 表示降序（id、created_at）" default(-id)
+// @Success 200 {object} response.Response{data=response.CursorListResponse{list=[]model.AuditLogResponse}}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/audit-logs [get]
+func (h *AuditLogHandler) ListAuditLogs(c *gin.Context) {
+	spec, err := model.AuditLogListQuery.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	params, err := parseCursorParams(c, h.cursorCodec, spec)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	page, total, err := h.auditService.ListLogsByCursor(c.Request.Context(), spec, params.cursor, params.limit, params.withTotal)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	respondCursorPage(c, h.cursorCodec, spec, page, params.limit, total)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/deantook/dove/internal/middleware"
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// DataExportHandler 个人数据导出处理器
+type DataExportHandler struct {
+	exportService service.DataExportService
+}
+
+// NewDataExportHandler 创建个人数据导出处理器实例
+func NewDataExportHandler(exportService service.DataExportService) *DataExportHandler {
+	return &DataExportHandler{exportService: exportService}
+}
+
+// CreateExport 申请导出个人数据
+// @Summary 申请导出个人数据
+// @Description 异步生成包含账号信息、资料字段、媒体文件记录和手机号变更记录的 ZIP 文件，通过查询导出任务获取限时下载地址
+// @Description 同一用户同时只能有一个未完成的导出任务，按用户限流
+// @Tags me
+// @Produce json
+// @Success 202 {object} response.Response{data=model.DataExportResponse}
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/exports [post]
+func (h *DataExportHandler) CreateExport(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	export, err := h.exportService.RequestExport(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithCode(c, http.StatusAccepted, "导出任务已创建", export)
+}
+
+// ListExports 获取数据导出任务列表
+// @Summary 获取数据导出任务列表
+// @Description 获取当前用户最近的数据导出任务，已生成的任务附带限时下载地址
+// @Tags me
+// @Produce json
+// @Success 200 {object} response.Response{data=[]model.DataExportResponse}
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/exports [get]
+func (h *DataExportHandler) ListExports(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	exports, err := h.exportService.ListExports(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", exports)
+}
+
+// GetExport 获取数据导出任务
+// @Summary 获取数据导出任务
+// @Description 获取当前用户的数据导出任务，状态为 ready 时返回限时下载地址，过期后重新获取即可
+// @Tags me
+// @Produce json
+// @Param id path int true "导出任务 ID"
+// @Success 200 {object} response.Response{data=model.DataExportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/exports/{id} [get]
+func (h *DataExportHandler) GetExport(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的导出任务 ID", err.Error())
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), userID, int(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", export)
+}
+
+// Download 下载导出文件（本地存储）
+// @Summary 下载导出文件
+// @Description 本地存储的预签名下载地址，由导出任务接口返回，不需要认证
+// @Tags me
+// @Produce application/zip
+// @Param key path string true "对象存储路径"
+// @Param expires query int true "过期时间"
+// @Param filename query string true "文件名"
+// @Param signature query string true "签名"
+// @Success 200 {file} file
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/downloads/{key} [get]
+func (h *DataExportHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	rc, filename, err := h.exportService.OpenDownload(c.Request.Context(), key, c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Header("Cache-Control", "no-store")
+	c.DataFromReader(http.StatusOK, -1, "application/zip", rc, nil)
+}
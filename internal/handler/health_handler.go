@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/deantook/dove/pkg/health"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler 健康检查处理器
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+// NewHealthHandler 创建健康检查处理器实例
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Livez 存活检查
+// @Summary 存活检查
+// @Description 进程存活即返回成功，不检查外部依赖
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	response.Success(c, gin.H{
+		"status": health.StatusUp,
+	})
+}
+
+// Readyz 就绪检查
+// @Summary 就绪检查
+// @Description 检查 MySQL、Redis 等依赖，返回每项依赖的状态和耗时；服务关闭期间返回未就绪
+// @Tags health
+// @Produce json
+// @Success 200 {object} response.Response{data=health.Report}
+// @Failure 503 {object} response.Response{data=health.Report}
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	report := h.checker.Readiness(c.Request.Context())
+	if !report.Ready() {
+		c.JSON(http.StatusServiceUnavailable, response.Response{
+			Code:    http.StatusServiceUnavailable,
+			Message: "服务未就绪",
+			Data:    report,
+		})
+		return
+	}
+
+	response.Success(c, report)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/middleware"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead multipart 请求中文件之外的表单字段和分隔符的预留大小
+const multipartOverhead = 1 << 20
+
+// MediaHandler 媒体文件处理器
+type MediaHandler struct {
+	mediaService service.MediaService
+	uploadConfig *config.UploadConfig
+}
+
+// NewMediaHandler 创建媒体文件处理器实例
+func NewMediaHandler(mediaService service.MediaService, uploadConfig *config.UploadConfig) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		uploadConfig: uploadConfig,
+	}
+}
+
+// Upload 上传文件
+// @Summary 上传文件
+// @Description 通过 multipart 表单上传头像或资料字段的图片、视频，文件类型按内容识别
+// @Description 图片会生成缩略图；purpose 为 avatar 时上传完成后设置为当前用户头像
+// @Tags media
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "文件"
+// @Param purpose formData string true "用途（avatar、profile_field）"
+// @Success 201 {object} response.Response{data=model.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/media [post]
+func (h *MediaHandler) Upload(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	maxSize := max(h.uploadConfig.MaxImageSize, h.uploadConfig.MaxVideoSize)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+multipartOverhead)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "读取文件失败", err.Error())
+		return
+	}
+	defer file.Close()
+
+	media, err := h.mediaService.Upload(c.Request.Context(), userID, c.PostForm("purpose"), file, fileHeader.Size)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithCode(c, http.StatusCreated, "上传成功", media)
+}
+
+// PresignUpload 获取预签名上传地址
+// @Summary 获取预签名上传地址
+// @Description 客户端使用返回的 upload.method、upload.url 和 upload.headers 直接上传文件，然后调用完成上传接口
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param request body model.PresignUploadRequest true "上传信息"
+// @Success 200 {object} response.Response{data=model.PresignUploadResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 415 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/media/presign [post]
+func (h *MediaHandler) PresignUpload(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	var req model.PresignUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	result, err := h.mediaService.PresignUpload(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, result)
+}
+
+// ReceiveUpload 接收预签名上传（本地存储）
+// @Summary 接收预签名上传
+// @Description 本地存储的预签名上传地址，由预签名接口返回，不需要认证；Content-Type 必须与声明一致
+// @Tags media
+// @Accept application/octet-stream
+// @Produce json
+// @Param key path string true "对象存储路径"
+// @Param expires query int true "过期时间"
+// @Param signature query string true "签名"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/media/uploads/{key} [put]
+func (h *MediaHandler) ReceiveUpload(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	err := h.mediaService.ReceiveUpload(c.Request.Context(), key, c.ContentType(), c.Request.URL.Query(), c.Request.Body)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "上传成功", nil)
+}
+
+// CompleteUpload 完成预签名上传
+// @Summary 完成预签名上传
+// @Description 校验已上传文件的大小和类型，图片生成缩略图；校验失败时删除文件，需要重新获取上传地址
+// @Tags media
+// @Produce json
+// @Param id path int true "媒体文件 ID"
+// @Success 200 {object} response.Response{data=model.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 413 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/media/{id}/complete [post]
+func (h *MediaHandler) CompleteUpload(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的媒体文件 ID", err.Error())
+		return
+	}
+
+	media, err := h.mediaService.CompleteUpload(c.Request.Context(), userID, int(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "上传成功", media)
+}
+
+// GetMedia 获取媒体文件
+// @Summary 获取媒体文件
+// @Description 获取当前用户上传的媒体文件
+// @Tags media
+// @Produce json
+// @Param id path int true "媒体文件 ID"
+// @Success 200 {object} response.Response{data=model.MediaResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/media/{id} [get]
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的媒体文件 ID", err.Error())
+		return
+	}
+
+	media, err := h.mediaService.GetMedia(c.Request.Context(), userID, int(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", media)
+}
+
+// DeleteMedia 删除媒体文件
+// @Summary 删除媒体文件
+// @Description 删除当前用户上传的媒体文件，正在被头像或资料字段引用的文件不能删除
+// @Tags media
+// @Produce json
+// @Param id path int true "媒体文件 ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/media/{id} [delete]
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的媒体文件 ID", err.Error())
+		return
+	}
+
+	if err := h.mediaService.DeleteMedia(c.Request.Context(), userID, int(id)); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "删除成功", nil)
+}
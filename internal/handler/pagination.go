@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// 游标分页每页数量
+const (
+	defaultCursorLimit = 20
+	maxCursorLimit     = 100
+)
+
+// cursorParams 游标分页参数
+type cursorParams struct {
+	cursor    *query.Cursor
+	limit     int
+	withTotal bool
+}
+
+// useCursor 判断请求是否使用游标分页
+// 携带 cursor 或 limit 参数时使用游标分页，否则保持原有的页码分页
+func useCursor(c *gin.Context) bool {
+	_, hasCursor := c.GetQuery("cursor")
+	_, hasLimit := c.GetQuery("limit")
+	return hasCursor || hasLimit
+}
+
+// parseCursorParams 解析游标分页参数
+func parseCursorParams(c *gin.Context, codec *query.CursorCodec, spec *query.Spec) (*cursorParams, error) {
+	params := &cursorParams{limit: defaultCursorLimit}
+
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil {
+		params.limit = min(max(limit, 1), maxCursorLimit)
+	}
+	params.withTotal, _ = strconv.ParseBool(c.Query("with_total"))
+
+	if token := c.Query("cursor"); token != "" {
+		cursor, err := codec.Decode(token, spec)
+		if err != nil {
+			return nil, err
+		}
+		params.cursor = cursor
+	}
+	return params, nil
+}
+
+// respondCursorPage 输出游标分页列表
+func respondCursorPage[T any](c *gin.Context, codec *query.CursorCodec, spec *query.Spec, page *query.CursorPage[T], limit int, total *int64) {
+	items := page.Items
+	if items == nil {
+		items = []T{}
+	}
+	response.SuccessCursorList(c, items, codec.Encode(page.Next, spec), codec.Encode(page.Prev, spec), limit, total)
+}
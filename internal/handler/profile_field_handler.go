@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/deantook/dove/internal/middleware"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileFieldHandler 用户资料字段处理器
+type ProfileFieldHandler struct {
+	fieldService service.ProfileFieldService
+	cursorCodec  *query.CursorCodec
+}
+
+// NewProfileFieldHandler 创建用户资料字段处理器实例
+func NewProfileFieldHandler(fieldService service.ProfileFieldService, cursorCodec *query.CursorCodec) *ProfileFieldHandler {
+	return &ProfileFieldHandler{
+		fieldService: fieldService,
+		cursorCodec:  cursorCodec,
+	}
+}
+
+// ListUserFields 获取用户资料字段列表
+// @Summary 获取用户资料字段列表
+// @Description 分页获取用户的资料字段，支持过滤和排序；非本人且非管理员时只返回公开字段
+// @Description 携带 cursor 或 limit 参数时使用游标分页，返回 CursorListResponse；否则使用页码分页，返回 ListResponse
+// @Tags users
+// @Produce json
+// @Param id path int true "用户 ID"
+// @Param field_type query string false "字段类型"
+// @Param is_public query bool false "是否公开"
+// @Param is_system query bool false "是否系统字段"
+// @Param sort query string false "排序字段，逗号分隔，前缀 - 表示降序（id、display_order、field_key、created_at）" default(display_order)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标（游标分页，取自上次响应的 next_cursor 或 prev_cursor）"
+// @Param limit query int false "每页数量（游标分页，最大 100）" default(20)
+// @Param with_total query bool false "是否返回总数（游标分页）"
+// @Success 200 {object} response.Response{data=response.ListResponse{list=[]model.ProfileField}}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /api/v1/users/{id}/profile-fields [get]
+func (h *ProfileFieldHandler) ListUserFields(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
+		return
+	}
+	userID := int(id)
+
+	spec, err := model.ProfileFieldListQuery.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	// 非本人且非管理员只能查看公开字段
+	if currentID, ok := middleware.CurrentUserID(c); (!ok || currentID != userID) && !middleware.IsAdmin(c) {
+		spec.Filters = append(spec.Filters, query.Filter{Column: "is_public", Op: query.Eq, Value: true})
+	}
+
+	h.listFields(c, userID, spec)
+}
+
+// GetMyProfile 获取当前用户资料
+// @Summary 获取当前用户资料
+// @Description 获取当前登录用户的信息和全部资料字段
+// @Tags me
+// @Produce json
+// @Success 200 {object} response.Response{data=model.UserProfileResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/profile [get]
+func (h *ProfileFieldHandler) GetMyProfile(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	profile, err := h.fieldService.GetUserProfile(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", profile)
+}
+
+// ListMyFields 获取当前用户资料字段列表
+// @Summary 获取当前用户资料字段列表
+// @Description 分页获取当前登录用户的资料字段，支持过滤和排序
+// @Description 携带 cursor 或 limit 参数时使用游标分页，返回 CursorListResponse；否则使用页码分页，返回 ListResponse
+// @Tags me
+// @Produce json
+// @Param field_type query string false "字段类型"
+// @Param is_public query bool false "是否公开"
+// @Param is_system query bool false "是否系统字段"
+// @Param sort query string false "排序字段，逗号分隔，前缀 - 表示降序（id、display_order、field_key、created_at）" default(display_order)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标（游标分页，取自上次响应的 next_cursor 或 prev_cursor）"
+// @Param limit query int false "每页数量（游标分页，最大 100）" default(20)
+// @Param with_total query bool false "是否返回总数（游标分页）"
+// @Success 200 {object} response.Response{data=response.ListResponse{list=[]model.ProfileField}}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/fields [get]
+func (h *ProfileFieldHandler) ListMyFields(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	spec, err := model.ProfileFieldListQuery.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	h.listFields(c, userID, spec)
+}
+
+// listFields 按分页方式输出用户资料字段列表
+func (h *ProfileFieldHandler) listFields(c *gin.Context, userID int, spec *query.Spec) {
+
+	if useCursor(c) {
+		params, err := parseCursorParams(c, h.cursorCodec, spec)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		page, total, err := h.fieldService.ListUserFieldsByCursor(c.Request.Context(), userID, spec, params.cursor, params.limit, params.withTotal)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		respondCursorPage(c, h.cursorCodec, spec, page, params.limit, total)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+
+	fields, total, err := h.fieldService.ListUserFields(c.Request.Context(), userID, spec, page, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessList(c, fields, total, page, pageSize)
+}
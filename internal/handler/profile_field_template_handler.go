@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/deantook/dove/internal/model"
 	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/query"
 	"github.com/deantook/dove/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -13,12 +18,14 @@ import (
 // ProfileFieldTemplateHandler 系统资料字段模板处理器
 type ProfileFieldTemplateHandler struct {
 	templateService service.ProfileFieldTemplateService
+	cursorCodec     *query.CursorCodec
 }
 
 // NewProfileFieldTemplateHandler 创建系统资料字段模板处理器实例
-func NewProfileFieldTemplateHandler(templateService service.ProfileFieldTemplateService) *ProfileFieldTemplateHandler {
+func NewProfileFieldTemplateHandler(templateService service.ProfileFieldTemplateService, cursorCodec *query.CursorCodec) *ProfileFieldTemplateHandler {
 	return &ProfileFieldTemplateHandler{
 		templateService: templateService,
+		cursorCodec:     cursorCodec,
 	}
 }
 
@@ -32,7 +39,10 @@ func NewProfileFieldTemplateHandler(templateService service.ProfileFieldTemplate
 // @Success 201 {object} response.Response{data=model.ProfileFieldTemplateResponse}
 // @Failure 400 {object} response.Response
 // @Failure 409 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/profile/field-templates [post]
 func (h *ProfileFieldTemplateHandler) CreateTemplate(c *gin.Context) {
 	var req model.CreateProfileFieldTemplateRequest
@@ -116,7 +126,10 @@ func (h *ProfileFieldTemplateHandler) GetTemplateByFieldKey(c *gin.Context) {
 // @Success 200 {object} response.Response{data=model.ProfileFieldTemplateResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/profile/field-templates/{id} [put]
 func (h *ProfileFieldTemplateHandler) UpdateTemplate(c *gin.Context) {
 	idStr := c.Param("id")
@@ -150,7 +163,10 @@ func (h *ProfileFieldTemplateHandler) UpdateTemplate(c *gin.Context) {
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/profile/field-templates/{id} [delete]
 func (h *ProfileFieldTemplateHandler) DeleteTemplate(c *gin.Context) {
 	idStr := c.Param("id")
@@ -170,34 +186,50 @@ func (h *ProfileFieldTemplateHandler) DeleteTemplate(c *gin.Context) {
 
 // ListTemplates 获取字段模板列表
 // @Summary 获取字段模板列表
-// @Description 分页获取字段模板列表
+// @Description 分页获取字段模板列表，支持过滤和排序
+// @Description 携带 cursor 或 limit 参数时使用游标分页，返回 CursorListResponse；否则使用页码分页，返回 ListResponse
 // @Tags profile-field-templates
 // @Produce json
 // @Param category query string false "字段分类"
 // @Param field_type query string false "字段类型"
 // @Param is_active query bool false "是否启用"
+// @Param keyword query string false "字段名称关键字"
+// @Param sort query string false "排序字段，逗号分隔，前缀 - 表示降序（id、category、display_order、field_key、created_at）" default(category,display_order,-created_at)
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标（游标分页，取自上次响应的 next_cursor 或 prev_cursor）"
+// @Param limit query int false "每页数量（游标分页，最大 100）" default(20)
+// @Param with_total query bool false "是否返回总数（游标分页）"
 // @Success 200 {object} response.Response{data=response.ListResponse{list=[]model.ProfileFieldTemplateResponse}}
 // @Failure 400 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/profile/field-templates [get]
 func (h *ProfileFieldTemplateHandler) ListTemplates(c *gin.Context) {
-	category := c.Query("category")
-	fieldType := c.Query("field_type")
-	isActiveStr := c.Query("is_active")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	var isActive *bool
-	if isActiveStr != "" {
-		active, err := strconv.ParseBool(isActiveStr)
-		if err == nil {
-			isActive = &active
+	spec, err := model.ProfileFieldTemplateListQuery.Parse(c.Request.URL.Query())
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	if useCursor(c) {
+		params, err := parseCursorParams(c, h.cursorCodec, spec)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		page, total, err := h.templateService.ListTemplatesByCursor(c.Request.Context(), spec, params.cursor, params.limit, params.withTotal)
+		if err != nil {
+			response.Error(c, err)
+			return
 		}
+		respondCursorPage(c, h.cursorCodec, spec, page, params.limit, total)
+		return
 	}
 
-	templates, total, err := h.templateService.ListTemplates(c.Request.Context(), category, fieldType, isActive, page, pageSize)
+	templates, total, err := h.templateService.ListTemplates(c.Request.Context(), spec, page, pageSize)
 	if err != nil {
 		response.Error(c, err)
 		return
@@ -314,3 +346,154 @@ func (h *ProfileFieldTemplateHandler) ApplyTemplatesToUser(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "批量应用完成", result)
 }
+
+// ExportTemplates 导出字段模板
+// @Summary 导出字段模板
+// @Description 将字段模板导出为带版本号的 YAML/JSON 文件（管理员）
+// @Tags profile-field-templates
+// @Produce json
+// @Produce application/x-yaml
+// @Param format query string false "文件格式（json、yaml）" default(json)
+// @Param category query string false "字段分类"
+// @Param field_type query string false "字段类型"
+// @Param is_active query bool false "是否启用"
+// @Success 200 {object} model.ProfileFieldTemplateBundle
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/profile/field-templates/export [get]
+func (h *ProfileFieldTemplateHandler) ExportTemplates(c *gin.Context) {
+	format, err := service.ParseBundleFormat(c.Query("format"))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	var isActive *bool
+	if isActiveStr := c.Query("is_active"); isActiveStr != "" {
+		active, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			response.BadRequest(c, "参数错误", err.Error())
+			return
+		}
+		isActive = &active
+	}
+
+	bundle, err := h.templateService.ExportTemplates(c.Request.Context(), c.Query("category"), c.Query("field_type"), isActive)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	data, err := service.EncodeTemplateBundle(bundle, format)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	contentType := "application/json; charset=utf-8"
+	if format == service.BundleFormatYAML {
+		contentType = "application/x-yaml; charset=utf-8"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="profile_field_templates.%s"`, format))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// ImportTemplates 导入字段模板
+// @Summary 导入字段模板
+// @Description 校验整个 YAML/JSON 文件后按 field_key 新建或更新字段模板（管理员）。文件可以作为请求体或 multipart 的 file 字段上传
+// @Tags profile-field-templates
+// @Accept json
+// @Accept application/x-yaml
+// @Accept multipart/form-data
+// @Produce json
+// @Param format query string false "文件格式（json、yaml），默认根据 Content-Type 或文件扩展名判断"
+// @Param dry_run query bool false "仅预览变更，不写入数据库" default(false)
+// @Param deactivate_missing query bool false "停用文件中不存在的模板" default(false)
+// @Param bundle body model.ProfileFieldTemplateBundle false "字段模板文件"
+// @Param file formData file false "字段模板文件"
+// @Success 200 {object} response.Response{data=service.ImportTemplatesResult}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/profile/field-templates/import [post]
+func (h *ProfileFieldTemplateHandler) ImportTemplates(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		response.BadRequest(c, "参数错误", err.Error())
+		return
+	}
+	deactivateMissing, err := strconv.ParseBool(c.DefaultQuery("deactivate_missing", "false"))
+	if err != nil {
+		response.BadRequest(c, "参数错误", err.Error())
+		return
+	}
+
+	data, formatHint, err := readBundleFile(c)
+	if err != nil {
+		response.BadRequest(c, "读取导入文件失败", err.Error())
+		return
+	}
+	if q := c.Query("format"); q != "" {
+		formatHint = q
+	}
+
+	format, err := service.ParseBundleFormat(formatHint)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	bundle, err := service.DecodeTemplateBundle(data, format)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	result, err := h.templateService.ImportTemplates(c.Request.Context(), bundle, service.ImportTemplatesOptions{
+		DryRun:            dryRun,
+		DeactivateMissing: deactivateMissing,
+	})
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, result.Message, result)
+}
+
+// readBundleFile 读取上传的字段模板文件，返回文件内容和格式提示
+func readBundleFile(c *gin.Context) ([]byte, string, error) {
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, "", err
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, "", err
+		}
+		return data, strings.TrimPrefix(filepath.Ext(fileHeader.Filename), "."), nil
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	formatHint := service.BundleFormatJSON
+	if strings.Contains(c.ContentType(), "yaml") {
+		formatHint = service.BundleFormatYAML
+	}
+	return data, formatHint, nil
+}
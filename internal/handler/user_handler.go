@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/deantook/dove/internal/middleware"
 	"github.com/deantook/dove/internal/model"
 	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/pkg/query"
 	"github.com/deantook/dove/pkg/response"
 	"github.com/gin-gonic/gin"
 )
@@ -13,31 +15,36 @@ import (
 // UserHandler 用户处理器
 type UserHandler struct {
 	userService service.UserService
+	cursorCodec *query.CursorCodec
 }
 
 // NewUserHandler 创建用户处理器实例
-func NewUserHandler(userService service.UserService) *UserHandler {
+func NewUserHandler(userService service.UserService, cursorCodec *query.CursorCodec) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		cursorCodec: cursorCodec,
 	}
 }
 
 // CreateUser 创建用户
 // @Summary 创建用户
-// @Description 创建新用户
+// @Description 管理员创建新用户
 // @Tags users
 // @Accept json
 // @Produce json
 // @Param user body model.CreateUserRequest true "用户信息"
 // @Success 201 {object} response.Response{data=model.UserResponse}
 // @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 409 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/users [post]
 func (h *UserHandler) CreateUser(c *gin.Context) {
 	var req model.CreateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ErrorWithCode(c, 500, 500, "无效的用户 ID", err.Error())
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
 		return
 	}
 
@@ -46,26 +53,30 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	maskPhones(c, user)
 
 	response.SuccessWithCode(c, http.StatusCreated, "创建成功", user)
 }
 
 // GetUser 获取用户详情
 // @Summary 获取用户详情
-// @Description 根据 ID 获取用户详情
+// @Description 根据 ID 获取用户详情，非管理员只能查看自己；非本人查看时手机号脱敏
 // @Tags users
 // @Produce json
 // @Param id path int true "用户 ID"
 // @Success 200 {object} response.Response{data=model.UserResponse}
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/users/{id} [get]
 func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		response.ErrorWithCode(c, 500, 500, "无效的用户 ID", err.Error())
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
 		return
 	}
 
@@ -74,13 +85,14 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	maskPhones(c, user)
 
 	response.SuccessWithMessage(c, "获取成功", user)
 }
 
 // UpdateUser 更新用户
 // @Summary 更新用户
-// @Description 更新用户信息
+// @Description 更新用户信息，非管理员只能更新自己；非本人查看时手机号脱敏
 // @Tags users
 // @Accept json
 // @Produce json
@@ -90,19 +102,22 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
 // @Failure 409 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/users/{id} [put]
 func (h *UserHandler) UpdateUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		response.ErrorWithCode(c, 500, 500, "无效的用户 ID", err.Error())
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
 		return
 	}
 
 	var req model.UpdateUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		response.ErrorWithCode(c, 500, 500, "参数错误", err.Error())
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
 		return
 	}
 
@@ -111,26 +126,30 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		response.Error(c, err)
 		return
 	}
+	maskPhones(c, user)
 
 	response.SuccessWithMessage(c, "更新成功", user)
 }
 
 // DeleteUser 删除用户
 // @Summary 删除用户
-// @Description 删除用户（软删除）
+// @Description 删除用户（软删除），非管理员只能删除自己；保留期内可由管理员恢复，超过后清除全部数据
 // @Tags users
 // @Produce json
 // @Param id path int true "用户 ID"
 // @Success 200 {object} response.Response
 // @Failure 400 {object} response.Response
 // @Failure 404 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/users/{id} [delete]
 func (h *UserHandler) DeleteUser(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		response.ErrorWithCode(c, 500, 500, "无效的用户 ID", err.Error())
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
 		return
 	}
 
@@ -142,39 +161,168 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 	response.SuccessWithMessage(c, "删除成功", nil)
 }
 
+// adminOnlyUserListParams 仅管理员可用的用户列表参数，避免普通用户探测手机号是否注册或枚举管理员
+var adminOnlyUserListParams = []string{"phone", "role", "include_deleted"}
+
 // ListUsers 获取用户列表
 // @Summary 获取用户列表
-// @Description 分页获取用户列表
+// @Description 分页获取用户列表，需要登录，支持过滤和排序；phone、role、include_deleted 仅管理员可用；除本人外手机号脱敏
+// @Description 携带 cursor 或 limit 参数时使用游标分页，返回 CursorListResponse；否则使用页码分页，返回 ListResponse
 // @Tags users
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(10)
+// @Param cursor query string false "游标（游标分页，取自上次响应的 next_cursor 或 prev_cursor）"
+// @Param limit query int false "每页数量（游标分页，最大 100）" default(20)
+// @Param with_total query bool false "是否返回总数（游标分页）"
+// @Param status query int false "用户状态"
+// @Param role query string false "用户角色（仅管理员）"
+// @Param created_from query string false "注册时间起（RFC3339 或 YYYY-MM-DD）"
+// @Param created_to query string false "注册时间止（RFC3339 或 YYYY-MM-DD，日期包含当天）"
+// @Param phone query string false "手机号（E.164 格式，如 +8613800138000，仅管理员）"
+// @Param keyword query string false "用户名关键字"
+// @Param sort query string false "排序字段，逗号分隔，前缀 - 表示降序（id、created_at、updated_at、username、status）" default(-created_at)
+// @Param include_deleted query bool false "包含已删除用户（仅管理员）"
 // @Success 200 {object} response.Response{data=response.ListResponse{list=[]model.UserResponse}}
 // @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
 // @Failure 500 {object} response.Response
+// @Security BearerAuth
 // @Router /api/v1/users [get]
 func (h *UserHandler) ListUsers(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "10"))
 
-	users, total, err := h.userService.ListUsers(c.Request.Context(), page, pageSize)
+	if !middleware.IsAdmin(c) {
+		for _, param := range adminOnlyUserListParams {
+			if _, ok := c.GetQuery(param); ok {
+				response.Forbidden(c, "无权限", "仅管理员可以使用 "+param+" 参数")
+				return
+			}
+		}
+	}
+
+	spec, err := model.UserListQuery.Parse(c.Request.URL.Query())
 	if err != nil {
 		response.Error(c, err)
 		return
 	}
+	spec.IncludeDeleted, _ = strconv.ParseBool(c.Query("include_deleted"))
+
+	if useCursor(c) {
+		params, err := parseCursorParams(c, h.cursorCodec, spec)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		page, total, err := h.userService.ListUsersByCursor(c.Request.Context(), spec, params.cursor, params.limit, params.withTotal)
+		if err != nil {
+			response.Error(c, err)
+			return
+		}
+		maskPhones(c, page.Items...)
+		respondCursorPage(c, h.cursorCodec, spec, page, params.limit, total)
+		return
+	}
+
+	users, total, err := h.userService.ListUsers(c.Request.Context(), spec, page, pageSize)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+	maskPhones(c, users...)
 
 	response.SuccessList(c, users, total, page, pageSize)
 }
 
+// GetMe 获取当前用户
+// @Summary 获取当前用户
+// @Description 获取当前登录用户的信息
+// @Tags me
+// @Produce json
+// @Success 200 {object} response.Response{data=model.UserResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	user, err := h.userService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "获取成功", user)
+}
+
+// UpdateMe 更新当前用户
+// @Summary 更新当前用户
+// @Description 更新当前登录用户的信息，只更新传入的字段
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param user body model.UpdateUserRequest true "用户信息"
+// @Success 200 {object} response.Response{data=model.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me [patch]
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	var req model.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateUser(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "更新成功", user)
+}
+
+// DeleteMe 注销当前用户
+// @Summary 注销当前用户
+// @Description 注销当前登录用户（软删除）；保留期内可由管理员恢复，超过后清除全部数据
+// @Tags me
+// @Produce json
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me [delete]
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	if err := h.userService.DeleteUser(c.Request.Context(), userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "注销成功", nil)
+}
+
 // SendCode 发送验证码
 // @Summary 发送验证码
-// @Description 发送手机验证码（开发阶段返回验证码）
+// @Description 发送手机验证码，同一验证码输错 sms.max_attempts 次后失效（开启 sms.expose_code 时在响应中返回验证码）
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body model.SendCodeRequest true "发送验证码请求"
 // @Success 200 {object} response.Response{data=model.SendCodeResponse}
 // @Failure 400 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/auth/send-code [post]
 func (h *UserHandler) SendCode(c *gin.Context) {
@@ -203,6 +351,7 @@ func (h *UserHandler) SendCode(c *gin.Context) {
 // @Success 200 {object} response.Response{data=model.LoginResponse}
 // @Failure 400 {object} response.Response
 // @Failure 401 {object} response.Response
+// @Failure 429 {object} response.Response
 // @Failure 500 {object} response.Response
 // @Router /api/v1/auth/login [post]
 func (h *UserHandler) LoginOrRegister(c *gin.Context) {
@@ -220,3 +369,85 @@ func (h *UserHandler) LoginOrRegister(c *gin.Context) {
 
 	response.SuccessWithMessage(c, "登录成功", result)
 }
+
+// maskPhones 对非当前用户的手机号脱敏
+func maskPhones(c *gin.Context, users ...*model.UserResponse) {
+	currentID, _ := middleware.CurrentUserID(c)
+	for _, user := range users {
+		if user.ID != currentID {
+			user.MaskPhone()
+		}
+	}
+}
+
+// UpdateUserStatus 修改用户状态
+// @Summary 修改用户状态
+// @Description 管理员冻结、封禁、停用或恢复用户，不能修改自己的状态
+// @Description 冻结和封禁可设置到期时间，到期后自动恢复；冻结、封禁、停用后用户已签发的 token 全部失效
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param id path int true "用户 ID"
+// @Param request body model.UpdateUserStatusRequest true "状态信息"
+// @Success 200 {object} response.Response{data=model.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/status [put]
+func (h *UserHandler) UpdateUserStatus(c *gin.Context) {
+	operatorID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
+		return
+	}
+
+	var req model.UpdateUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	user, err := h.userService.UpdateUserStatus(c.Request.Context(), operatorID, int(id), &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "修改成功", user)
+}
+
+// RestoreUser 恢复已删除的用户
+// @Summary 恢复已删除的用户
+// @Description 管理员恢复保留期内已删除的用户，恢复后用户需要重新登录；手机号已被其他用户注册时不能恢复
+// @Tags users
+// @Produce json
+// @Param id path int true "用户 ID"
+// @Success 200 {object} response.Response{data=model.UserResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/restore [post]
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
+		return
+	}
+
+	user, err := h.userService.RestoreUser(c.Request.Context(), int(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "恢复成功", user)
+}
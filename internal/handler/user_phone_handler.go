@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/deantook/dove/internal/middleware"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SendOldPhoneCode 向原手机号发送验证码
+// @Summary 向原手机号发送验证码
+// @Description 更换手机号第一步：向当前手机号发送验证码（开启 sms.expose_code 时返回验证码）
+// @Tags me
+// @Produce json
+// @Success 200 {object} response.Response{data=model.SendCodeResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/phone/old-code [post]
+func (h *UserHandler) SendOldPhoneCode(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	result, err := h.userService.SendOldPhoneCode(c.Request.Context(), userID)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "验证码发送成功", result)
+}
+
+// VerifyOldPhone 验证原手机号
+// @Summary 验证原手机号
+// @Description 更换手机号第二步：校验原手机号验证码，返回 15 分钟内有效的更换手机号凭证
+// @Description 原手机号无法接收验证码时，可联系管理员核实身份后签发凭证
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyOldPhoneRequest true "原手机号验证码"
+// @Success 200 {object} response.Response{data=model.PhoneChangeTicketResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/phone/verify-old [post]
+func (h *UserHandler) VerifyOldPhone(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	var req model.VerifyOldPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	result, err := h.userService.VerifyOldPhone(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "验证成功", result)
+}
+
+// SendNewPhoneCode 向新手机号发送验证码
+// @Summary 向新手机号发送验证码
+// @Description 更换手机号第三步：校验凭证，向新手机号发送验证码（开启 sms.expose_code 时返回验证码）
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body model.SendNewPhoneCodeRequest true "凭证和新手机号"
+// @Success 200 {object} response.Response{data=model.SendCodeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 429 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/phone/new-code [post]
+func (h *UserHandler) SendNewPhoneCode(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	var req model.SendNewPhoneCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	result, err := h.userService.SendNewPhoneCode(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "验证码发送成功", result)
+}
+
+// ChangePhone 更换手机号
+// @Summary 更换手机号
+// @Description 更换手机号最后一步：校验凭证和新手机号验证码后更换手机号
+// @Description 更换后之前签发的 token 全部失效，响应中返回新的 token
+// @Tags me
+// @Accept json
+// @Produce json
+// @Param request body model.ChangePhoneRequest true "凭证、新手机号和验证码"
+// @Success 200 {object} response.Response{data=model.LoginResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/me/phone [put]
+func (h *UserHandler) ChangePhone(c *gin.Context) {
+	userID, _ := middleware.CurrentUserID(c)
+
+	var req model.ChangePhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "参数错误", err.Error())
+		return
+	}
+
+	result, err := h.userService.ChangePhone(c.Request.Context(), userID, &req)
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "手机号更换成功", result)
+}
+
+// IssuePhoneChangeTicket 签发更换手机号凭证
+// @Summary 签发更换手机号凭证
+// @Description 管理员为原手机号无法接收验证码的用户签发更换手机号凭证，需先核实用户身份
+// @Description 用户凭此凭证从向新手机号发送验证码一步继续更换流程
+// @Tags users
+// @Produce json
+// @Param id path int true "用户 ID"
+// @Success 200 {object} response.Response{data=model.PhoneChangeTicketResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Security BearerAuth
+// @Router /api/v1/users/{id}/phone-change-ticket [post]
+func (h *UserHandler) IssuePhoneChangeTicket(c *gin.Context) {
+	operatorID, _ := middleware.CurrentUserID(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.ErrorWithCode(c, http.StatusBadRequest, 400, "无效的用户 ID", err.Error())
+		return
+	}
+
+	result, err := h.userService.IssuePhoneChangeTicket(c.Request.Context(), operatorID, int(id))
+	if err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.SuccessWithMessage(c, "签发成功", result)
+}
@@ -0,0 +1,60 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Func 任务执行函数
+type Func func(ctx context.Context) error
+
+// Job 后台任务
+type Job struct {
+	Name        string
+	Description string
+	Run         Func
+	Interval    time.Duration // serve 进程中定时执行的默认间隔，0 表示只能手动执行，可通过 jobs.intervals 配置覆盖
+}
+
+// Registry 任务注册表
+// 任务可以通过命令行 `jobs run <name>` 手动执行，设置了执行间隔的任务由 Scheduler 定时执行
+type Registry struct {
+	jobs map[string]*Job
+}
+
+// NewRegistry 创建任务注册表
+func NewRegistry() *Registry {
+	return &Registry{jobs: make(map[string]*Job)}
+}
+
+// Register 注册任务，名称重复时覆盖
+func (r *Registry) Register(job *Job) {
+	r.jobs[job.Name] = job
+}
+
+// Get 根据名称获取任务
+func (r *Registry) Get(name string) (*Job, bool) {
+	job, ok := r.jobs[name]
+	return job, ok
+}
+
+// List 按名称排序返回全部任务
+func (r *Registry) List() []*Job {
+	jobs := make([]*Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// Run 执行指定任务
+func (r *Registry) Run(ctx context.Context, name string) error {
+	job, ok := r.Get(name)
+	if !ok {
+		return fmt.Errorf("任务不存在: %s", name)
+	}
+	return job.Run(ctx)
+}
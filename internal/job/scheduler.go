@@ -0,0 +1,97 @@
+package job
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// lockKeyPrefix 定时任务锁的 Redis 键前缀
+const lockKeyPrefix = "job:lock:"
+
+// Scheduler 定时任务调度器
+// 在 serve 进程中按间隔执行任务；配置了 Redis 时每个周期通过锁保证多个副本中只有一个执行
+type Scheduler struct {
+	registry  *Registry
+	redis     *redis.Client
+	intervals map[string]time.Duration
+	owner     string
+	wg        sync.WaitGroup
+}
+
+// NewScheduler 创建定时任务调度器
+// intervals 按任务名称覆盖任务的默认执行间隔，值为 0 表示不定时执行
+func NewScheduler(registry *Registry, redisClient *redis.Client, intervals map[string]time.Duration) *Scheduler {
+	host, _ := os.Hostname()
+	return &Scheduler{
+		registry:  registry,
+		redis:     redisClient,
+		intervals: intervals,
+		owner:     host,
+	}
+}
+
+// Start 启动全部定时任务，ctx 取消后停止调度，通过 Wait 等待正在执行的任务结束
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.registry.List() {
+		interval := job.Interval
+		if v, ok := s.intervals[job.Name]; ok {
+			interval = v
+		}
+		if interval <= 0 {
+			continue
+		}
+
+		slog.Info("定时任务已启动", slog.String("job", job.Name), slog.Duration("interval", interval))
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.loop(ctx, job, interval)
+		}()
+	}
+}
+
+// Wait 等待全部调度循环退出
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+// loop 按间隔执行任务直到 ctx 取消
+func (s *Scheduler) loop(ctx context.Context, job *Job, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job, interval)
+		}
+	}
+}
+
+// runOnce 获取锁后执行一次任务，锁在略短于一个间隔后自动过期，不主动释放
+func (s *Scheduler) runOnce(ctx context.Context, job *Job, interval time.Duration) {
+	if s.redis != nil {
+		ok, err := s.redis.SetNX(ctx, lockKeyPrefix+job.Name, s.owner, interval*9/10).Result()
+		if err != nil {
+			slog.WarnContext(ctx, "获取定时任务锁失败", slog.String("job", job.Name), slog.Any("error", err))
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		slog.ErrorContext(ctx, "定时任务执行失败", slog.String("job", job.Name), slog.Any("error", err))
+		return
+	}
+	slog.DebugContext(ctx, "定时任务执行完成", slog.String("job", job.Name), slog.Duration("elapsed", time.Since(start)))
+}
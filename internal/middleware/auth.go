@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/jwt"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/requestinfo"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 认证相关的 gin 上下文键
+const (
+	ContextKeyUserID = "user_id" // 当前用户 ID
+	ContextKeyRole   = "role"    // 当前用户角色
+)
+
+// bearerPrefix Authorization 请求头前缀
+const bearerPrefix = "Bearer "
+
+// Authenticator 认证器，校验 token 并从数据库加载当前用户
+// token 中的版本与用户当前版本不一致（如更换手机号、修改状态后）时视为已吊销，账号冻结、封禁或停用时返回 403
+type Authenticator struct {
+	userRepo repository.UserRepository
+	jwt      *jwt.Manager
+}
+
+// NewAuthenticator 创建认证器实例
+func NewAuthenticator(userRepo repository.UserRepository, jwtManager *jwt.Manager) *Authenticator {
+	return &Authenticator{userRepo: userRepo, jwt: jwtManager}
+}
+
+// OptionalAuth 可选认证中间件
+// 携带 Bearer token 时校验并写入当前用户，未携带时按匿名请求处理；token 无效或已吊销时返回 401
+// 当前用户角色以数据库为准，角色变更后无需重新登录
+func (a *Authenticator) OptionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, bearerPrefix)
+		if !ok || token == "" {
+			response.Unauthorized(c, "认证失败", "Authorization 请求头格式应为 Bearer <token>")
+			c.Abort()
+			return
+		}
+
+		claims, err := a.jwt.ParseToken(token)
+		if err != nil {
+			response.Unauthorized(c, "认证失败", "token 无效或已过期")
+			c.Abort()
+			return
+		}
+
+		ctx := c.Request.Context()
+		user, err := a.userRepo.GetByID(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				response.Unauthorized(c, "认证失败", "用户不存在")
+			} else {
+				logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
+				response.InternalServerError(c, "认证失败", "查询用户失败")
+			}
+			c.Abort()
+			return
+		}
+		if user.TokenVersion != claims.Version {
+			response.Unauthorized(c, "认证失败", "token 已失效，请重新登录")
+			c.Abort()
+			return
+		}
+		if appErr := user.StatusError(time.Now()); appErr != nil {
+			response.Error(c, appErr)
+			c.Abort()
+			return
+		}
+
+		c.Set(ContextKeyUserID, user.ID)
+		c.Set(ContextKeyRole, user.Role)
+		c.Request = c.Request.WithContext(requestinfo.WithActor(ctx, user.ID))
+		c.Next()
+	}
+}
+
+// RequireAuth 强制认证中间件，需在 OptionalAuth 之后使用，匿名请求返回 401
+func RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := CurrentUserID(c); !ok {
+			response.Unauthorized(c, "未登录", "请在 Authorization 请求头中携带 Bearer token")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireOwnerOrAdmin 资源归属校验中间件，需在 RequireAuth 之后使用
+// 路径参数 param 指定的用户 ID 不是当前用户且当前用户不是管理员时返回 403
+func RequireOwnerOrAdmin(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if IsAdmin(c) {
+			c.Next()
+			return
+		}
+		userID, _ := CurrentUserID(c)
+		if c.Param(param) != strconv.Itoa(userID) {
+			response.Forbidden(c, "无权限", "只能访问自己的账号")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin 管理员权限中间件，需在 RequireAuth 之后使用，非管理员返回 403
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !IsAdmin(c) {
+			response.Forbidden(c, "无权限", "需要管理员权限")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUserID 获取当前用户 ID，匿名请求返回 false
+func CurrentUserID(c *gin.Context) (int, bool) {
+	userID, ok := c.Get(ContextKeyUserID)
+	if !ok {
+		return 0, false
+	}
+	id, ok := userID.(int)
+	return id, ok
+}
+
+// IsAdmin 判断当前用户是否为管理员
+func IsAdmin(c *gin.Context) bool {
+	return c.GetString(ContextKeyRole) == model.UserRoleAdmin
+}
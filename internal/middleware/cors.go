@@ -1,17 +1,120 @@
 package middleware
 
 import (
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
+// alwaysExposeHeaders 始终暴露给浏览器的响应头
+var alwaysExposeHeaders = []string{
+	RequestIDHeader,
+	HeaderRateLimitLimit,
+	HeaderRateLimitRemaining,
+	HeaderRateLimitReset,
+	HeaderRateLimitPolicy,
+	HeaderRetryAfter,
+}
+
 // CORS 跨域中间件
-func CORS() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "accept", "origin", "Cache-Control", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
+// 来源支持精确匹配和 https://*.example.com 形式的子域名通配
+// 配置热更新时重新构建处理函数并原子替换，进行中的请求不受影响
+func CORS(provider *config.Provider) gin.HandlerFunc {
+	var current atomic.Pointer[gin.HandlerFunc]
+	handler := newCORSHandler(&provider.Get().CORS)
+	current.Store(&handler)
+
+	provider.Subscribe(func(old, new *config.Config) {
+		if reflect.DeepEqual(old.CORS, new.CORS) {
+			return
+		}
+		handler := newCORSHandler(&new.CORS)
+		current.Store(&handler)
 	})
+
+	return func(c *gin.Context) {
+		(*current.Load())(c)
+	}
+}
+
+// newCORSHandler 根据配置创建跨域处理函数
+func newCORSHandler(cfg *config.CORSConfig) gin.HandlerFunc {
+	exposeHeaders := slices.Clone(cfg.ExposeHeaders)
+	for _, h := range alwaysExposeHeaders {
+		if !slices.ContainsFunc(exposeHeaders, func(v string) bool { return strings.EqualFold(v, h) }) {
+			exposeHeaders = append(exposeHeaders, h)
+		}
+	}
+
+	corsCfg := cors.Config{
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    exposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           time.Duration(cfg.MaxAge) * time.Second,
+	}
+	if slices.Contains(cfg.AllowOrigins, "*") && !cfg.AllowCredentials {
+		corsCfg.AllowAllOrigins = true
+	} else {
+		corsCfg.AllowOriginFunc = newOriginMatcher(cfg.AllowOrigins).match
+	}
+
+	return cors.New(corsCfg)
+}
+
+// originMatcher 来源匹配
+type originMatcher struct {
+	exact     map[string]struct{}
+	wildcards []wildcardOrigin
+}
+
+// wildcardOrigin 子域名通配来源，如 https://*.example.com
+type wildcardOrigin struct {
+	scheme string
+	suffix string // 含端口，如 .example.com:8443
+}
+
+// newOriginMatcher 创建来源匹配器，来源格式已在配置校验时检查
+func newOriginMatcher(origins []string) *originMatcher {
+	m := &originMatcher{exact: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		origin = strings.ToLower(strings.TrimSuffix(origin, "/"))
+		scheme, host, ok := strings.Cut(origin, "://")
+		if !ok {
+			continue
+		}
+		if strings.HasPrefix(host, "*.") {
+			m.wildcards = append(m.wildcards, wildcardOrigin{scheme: scheme, suffix: host[1:]})
+			continue
+		}
+		m.exact[origin] = struct{}{}
+	}
+	return m
+}
+
+// match 判断来源是否允许
+func (m *originMatcher) match(origin string) bool {
+	origin = strings.ToLower(origin)
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, w := range m.wildcards {
+		// 通配只匹配子域名，不匹配根域名本身
+		if u.Scheme == w.scheme && strings.HasSuffix(u.Host, w.suffix) && len(u.Host) > len(w.suffix) {
+			return true
+		}
+	}
+	return false
 }
@@ -1,24 +1,71 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
+	"net/url"
 	"time"
 
+	"github.com/deantook/dove/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
 
-// Logger 日志中间件
+// redactedQueryParams 日志中脱敏的查询参数：手机号过滤条件、预签名地址的签名和 token
+var redactedQueryParams = []string{"phone", "signature", "token"}
+
+// Logger 日志中间件，需要注册在 RequestID 之后
 func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		log.Printf(
-			"[%s] %s %s %d %s %s\n",
-			param.TimeStamp.Format(time.RFC3339),
-			param.Method,
-			param.Path,
-			param.StatusCode,
-			param.Latency,
-			param.ErrorMessage,
-		)
-		return ""
-	})
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if c.Request.URL.RawQuery != "" {
+			path += "?" + redactQuery(c.Request.URL.RawQuery)
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		ctx := c.Request.Context()
+		logger.FromContext(ctx).LogAttrs(ctx, level, "HTTP 请求", attrs...)
+	}
+}
+
+// redactQuery 将敏感查询参数的值替换为 REDACTED，无法解析的查询字符串整体替换
+func redactQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "REDACTED"
+	}
+	redacted := false
+	for _, name := range redactedQueryParams {
+		if _, ok := values[name]; ok {
+			values[name] = []string{"REDACTED"}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
 }
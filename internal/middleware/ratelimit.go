@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/ratelimit"
+	"github.com/deantook/dove/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+)
+
+// 限流响应头
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	HeaderRetryAfter         = "Retry-After"
+)
+
+// KeyFunc 从请求中提取限流键
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP 按客户端 IP 限流
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser 按当前用户限流，未登录时按 IP 限流
+func KeyByUser(c *gin.Context) string {
+	if userID, ok := c.Get(ContextKeyUserID); ok {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return KeyByIP(c)
+}
+
+// KeyByHeader 按请求头限流，请求头为空时按 IP 限流
+func KeyByHeader(name string) KeyFunc {
+	return func(c *gin.Context) string {
+		if v := c.GetHeader(name); v != "" {
+			return "header:" + name + ":" + v
+		}
+		return KeyByIP(c)
+	}
+}
+
+// keyFuncFromConfig 根据 key_by 配置选择限流键
+func keyFuncFromConfig(keyBy string) KeyFunc {
+	switch {
+	case keyBy == "user":
+		return KeyByUser
+	case strings.HasPrefix(keyBy, "header:"):
+		return KeyByHeader(strings.TrimPrefix(keyBy, "header:"))
+	default:
+		return KeyByIP
+	}
+}
+
+// RateLimiter 按路由组策略限流
+// 是否启用和策略在每次请求时从配置快照读取，支持热更新；后端和键前缀变更需要重启
+type RateLimiter struct {
+	provider *config.Provider
+	limiter  ratelimit.Limiter
+}
+
+// NewRateLimiter 创建限流器
+// backend 为 memory 或未提供 Redis 客户端时使用进程内限流
+func NewRateLimiter(provider *config.Provider, redisClient *redis.Client) *RateLimiter {
+	cfg := provider.Get().RateLimit
+	var limiter ratelimit.Limiter
+	if cfg.Backend == "memory" || redisClient == nil {
+		limiter = ratelimit.NewMemoryLimiter()
+	} else {
+		limiter = ratelimit.NewRedisLimiter(redisClient, cfg.GetKeyPrefix())
+	}
+	return &RateLimiter{provider: provider, limiter: limiter}
+}
+
+// Policy 返回指定策略的限流中间件
+// 策略未在配置中声明或限流未启用时直接放行；传入 keyFunc 时覆盖配置中的 key_by
+func (r *RateLimiter) Policy(name string, keyFunc ...KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := &r.provider.Get().RateLimit
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+		pc, ok := cfg.Policies[name]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		extract := keyFuncFromConfig(pc.KeyBy)
+		if len(keyFunc) > 0 {
+			extract = keyFunc[0]
+		}
+
+		policy := ratelimit.Policy{
+			Algorithm: pc.Algorithm,
+			Limit:     pc.Limit,
+			Window:    time.Duration(pc.Window) * time.Second,
+			Burst:     pc.Burst,
+		}
+
+		ctx := c.Request.Context()
+		result, err := r.limiter.Allow(ctx, name+":"+extract(c), policy)
+		if err != nil {
+			// 限流后端不可用时放行，避免 Redis 故障导致整体不可用
+			logger.FromContext(ctx).WarnContext(ctx, "限流检查失败，已放行",
+				slog.String("policy", name),
+				slog.Any("error", err),
+			)
+			c.Next()
+			return
+		}
+
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Header(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		c.Header(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", pc.Limit, pc.Window))
+
+		if !result.Allowed {
+			retryAfter := max(ceilSeconds(result.RetryAfter), 1)
+			c.Header(HeaderRetryAfter, strconv.Itoa(retryAfter))
+			metrics.HTTPRateLimited.WithLabelValues(name).Inc()
+			response.TooManyRequests(c, "请求过于频繁", fmt.Sprintf("请在 %d 秒后重试", retryAfter))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ceilSeconds 向上取整为秒
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
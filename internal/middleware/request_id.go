@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/requestinfo"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 请求头/响应头
+const RequestIDHeader = "X-Request-ID"
+
+// ContextKeyRequestID gin 上下文中请求 ID 的键
+const ContextKeyRequestID = "request_id"
+
+// requestIDPattern 允许透传的请求 ID 格式，避免日志注入
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID 请求 ID 中间件
+// 优先使用客户端或网关传入的 X-Request-ID，否则生成新的 ID，并在响应头中返回
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(ContextKeyRequestID, requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
+
+// newRequestID 生成 32 位十六进制请求 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// RequestInfo 请求来源中间件，将客户端 IP、User-Agent 和请求 ID 写入请求 context
+// 需在 RequestID 之后使用
+func RequestInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(requestinfo.WithInfo(c.Request.Context(), requestinfo.Info{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+			RequestID: c.GetString(ContextKeyRequestID),
+		}))
+		c.Next()
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/deantook/dove/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+)
+
+// tracingSkipPrefixes 不创建 Span 的路径前缀
+var tracingSkipPrefixes = []string{"/health", "/livez", "/readyz", "/metrics", "/swagger"}
+
+// Tracing 链路追踪中间件
+// 从请求头提取 W3C trace-context，并以路由模板作为 Span 名称
+func Tracing(tracer *tracing.Provider) gin.HandlerFunc {
+	return otelgin.Middleware(tracer.ServiceName(),
+		otelgin.WithTracerProvider(tracer.TracerProvider()),
+		otelgin.WithGinFilter(func(c *gin.Context) bool {
+			for _, prefix := range tracingSkipPrefixes {
+				if strings.HasPrefix(c.Request.URL.Path, prefix) {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+}
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/deantook/dove/pkg/query"
+)
+
+// 审计操作
+const (
+	AuditActionUserCreate       = "user.create"              // 创建用户（含登录时自动注册）
+	AuditActionUserUpdate       = "user.update"              // 修改用户资料
+	AuditActionUserDelete       = "user.delete"              // 注销用户
+	AuditActionUserRestore      = "user.restore"             // 恢复已注销的用户
+	AuditActionUserPurge        = "user.purge"               // 清除注销用户的全部数据
+	AuditActionUserStatus       = "user.status"              // 修改用户状态
+	AuditActionUserPromote      = "user.promote_admin"       // 提升为管理员
+	AuditActionUserPhoneChange  = "user.phone_change"        // 更换手机号
+	AuditActionUserPhoneTicket  = "user.phone_change_ticket" // 管理员签发更换手机号凭证
+	AuditActionUserDataExport   = "user.data_export"         // 申请导出个人数据
+	AuditActionAuthLogin        = "auth.login"               // 登录成功
+	AuditActionAuthLoginRefused = "auth.login_refused"       // 账号状态异常，拒绝登录
+	AuditActionTemplateCreate   = "template.create"          // 创建字段模板
+	AuditActionTemplateUpdate   = "template.update"          // 修改字段模板
+	AuditActionTemplateDelete   = "template.delete"          // 删除字段模板
+	AuditActionTemplateImport   = "template.import"          // 导入字段模板
+)
+
+// 审计对象类型
+const (
+	AuditTargetUser     = "user"
+	AuditTargetTemplate = "profile_field_template"
+)
+
+// AuditLog 审计日志模型
+// 只追加不修改，超过 audit.retention 天后由 audit-log-purge 任务删除
+type AuditLog struct {
+	ID         int       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	ActorID    int       `gorm:"column:actor_id;type:int;index" json:"actor_id"` // 操作人 ID，后台任务和命令行为 0
+	Action     string    `gorm:"column:action;type:varchar(50);index" json:"action"`
+	TargetType string    `gorm:"column:target_type;type:varchar(50)" json:"target_type"`
+	TargetID   int       `gorm:"column:target_id;type:int" json:"target_id"`
+	Changes    string    `gorm:"column:changes;type:text" json:"changes"` // 变更前后的字段值，JSON 格式
+	IP         string    `gorm:"column:ip;type:varchar(64)" json:"ip"`
+	UserAgent  string    `gorm:"column:user_agent;type:varchar(500)" json:"user_agent"`
+	RequestID  string    `gorm:"column:request_id;type:varchar(64)" json:"request_id"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime;index" json:"create_time"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditChange 单个字段的变更，创建时 old 为空，删除时 new 为空
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditLogListQuery 审计日志列表查询参数
+// 支持 actor_id、action、target_type、target_id、request_id、created_from、created_to 过滤，按 id、created_at 排序，默认按时间倒序
+var AuditLogListQuery = query.NewBuilder("id").
+	Filter("actor_id", "actor_id", query.Eq, query.Int).
+	Filter("action", "action", query.Eq, query.String).
+	Filter("target_type", "target_type", query.Eq, query.String).
+	Filter("target_id", "target_id", query.Eq, query.Int).
+	Filter("request_id", "request_id", query.Eq, query.String).
+	Filter("created_from", "create_time", query.Gte, query.Time).
+	Filter("created_to", "create_time", query.Lte, query.Time).
+	Sortable("id", "id").
+	Sortable("created_at", "create_time").
+	DefaultSort("-id")
+
+// AuditLogResponse 审计日志响应
+type AuditLogResponse struct {
+	ID         int                     `json:"id"`
+	ActorID    int                     `json:"actor_id"`
+	Action     string                  `json:"action" example:"user.update"`
+	TargetType string                  `json:"target_type" example:"user"`
+	TargetID   int                     `json:"target_id"`
+	Changes    map[string]*AuditChange `json:"changes,omitempty"`
+	IP         string                  `json:"ip"`
+	UserAgent  string                  `json:"user_agent"`
+	RequestID  string                  `json:"request_id"`
+	CreateTime time.Time               `json:"create_time"`
+}
+
+// ToResponse 转换为响应结构
+func (l *AuditLog) ToResponse() *AuditLogResponse {
+	resp := &AuditLogResponse{
+		ID:         l.ID,
+		ActorID:    l.ActorID,
+		Action:     l.Action,
+		TargetType: l.TargetType,
+		TargetID:   l.TargetID,
+		IP:         l.IP,
+		UserAgent:  l.UserAgent,
+		RequestID:  l.RequestID,
+		CreateTime: l.CreateTime,
+	}
+	if l.Changes != "" {
+		_ = json.Unmarshal([]byte(l.Changes), &resp.Changes)
+	}
+	return resp
+}
@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// 数据导出状态
+const (
+	DataExportStatusPending    = "pending"    // 等待生成
+	DataExportStatusProcessing = "processing" // 生成中
+	DataExportStatusReady      = "ready"      // 已生成，可下载
+	DataExportStatusFailed     = "failed"     // 生成失败
+	DataExportStatusExpired    = "expired"    // 超过保留时间，文件已删除
+)
+
+// DataExportKeyPrefix 导出文件在私有对象存储中的路径前缀，导出文件只能通过预签名下载地址访问
+const DataExportKeyPrefix = "exports/"
+
+// DataExport 个人数据导出任务
+// 由 data-export 任务异步生成 ZIP 文件并保存到对象存储，超过保留时间后由 data-export-cleanup 任务删除文件
+type DataExport struct {
+	ID          int        `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID      int        `gorm:"column:user_id;type:int;index" json:"user_id"`
+	Status      string     `gorm:"column:status;type:varchar(20);default:pending" json:"status"`
+	StorageKey  string     `gorm:"column:storage_key;type:varchar(500)" json:"storage_key"`
+	Size        int64      `gorm:"column:size;type:bigint" json:"size"`
+	Error       string     `gorm:"column:error;type:varchar(500)" json:"error"`
+	ExpiresAt   *time.Time `gorm:"column:expires_at" json:"expires_at"` // 文件保留到期时间，生成完成后设置
+	CompletedAt *time.Time `gorm:"column:completed_at" json:"completed_at"`
+	CreateTime  time.Time  `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+	UpdateTime  time.Time  `gorm:"column:update_time;autoUpdateTime" json:"update_time"`
+}
+
+// TableName 指定表名
+func (DataExport) TableName() string {
+	return "user_data_exports"
+}
+
+// DataExportResponse 数据导出任务响应
+// 状态为 ready 时返回限时下载地址，过期后重新查询任务即可获取新地址
+type DataExportResponse struct {
+	ID             int        `json:"id"`
+	Status         string     `json:"status" example:"ready"`
+	Size           int64      `json:"size,omitempty"`                // ZIP 文件大小（字节）
+	Error          string     `json:"error,omitempty"`               // 生成失败的原因
+	DownloadURL    string     `json:"download_url,omitempty"`        // 限时下载地址
+	DownloadExpiry *time.Time `json:"download_expires_at,omitempty"` // 下载地址过期时间
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`          // 文件保留到期时间
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`        // 生成完成时间
+	CreateTime     time.Time  `json:"create_time"`
+}
+
+// ToResponse 转换为响应结构，不包含下载地址
+func (e *DataExport) ToResponse() *DataExportResponse {
+	return &DataExportResponse{
+		ID:          e.ID,
+		Status:      e.Status,
+		Size:        e.Size,
+		Error:       e.Error,
+		ExpiresAt:   e.ExpiresAt,
+		CompletedAt: e.CompletedAt,
+		CreateTime:  e.CreateTime,
+	}
+}
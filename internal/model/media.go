@@ -0,0 +1,73 @@
+package model
+
+import "time"
+
+// 媒体文件用途
+const (
+	MediaPurposeAvatar       = "avatar"        // 头像，上传完成后设置为用户头像
+	MediaPurposeProfileField = "profile_field" // IMAGE、VIDEO 类型的资料字段
+)
+
+// 媒体文件状态
+const (
+	MediaStatusPending = "pending" // 已生成预签名上传地址，等待客户端上传
+	MediaStatusReady   = "ready"   // 已上传并校验
+)
+
+// MediaObject 媒体文件模型
+// 记录上传到对象存储的文件，未被用户头像或资料字段引用的文件由 media-gc 任务清理
+type MediaObject struct {
+	ID           int       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID       int       `gorm:"column:user_id;type:int;index" json:"user_id"`
+	Purpose      string    `gorm:"column:purpose;type:varchar(20)" json:"purpose"`
+	StorageKey   string    `gorm:"column:storage_key;type:varchar(500);uniqueIndex" json:"storage_key"`
+	ThumbnailKey string    `gorm:"column:thumbnail_key;type:varchar(500)" json:"thumbnail_key"`
+	ContentType  string    `gorm:"column:content_type;type:varchar(100)" json:"content_type"`
+	Size         int64     `gorm:"column:size;type:bigint" json:"size"`
+	Width        int       `gorm:"column:width;type:int" json:"width"`
+	Height       int       `gorm:"column:height;type:int" json:"height"`
+	Status       string    `gorm:"column:status;type:varchar(20);default:pending" json:"status"`
+	CreateTime   time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+	UpdateTime   time.Time `gorm:"column:update_time;autoUpdateTime" json:"update_time"`
+}
+
+// TableName 指定表名
+func (MediaObject) TableName() string {
+	return "media_objects"
+}
+
+// PresignUploadRequest 预签名上传请求
+type PresignUploadRequest struct {
+	Purpose     string `json:"purpose" binding:"required,oneof=avatar profile_field" example:"avatar"`
+	ContentType string `json:"content_type" binding:"required" example:"image/jpeg"` // 文件类型，上传时的 Content-Type 必须一致
+	Size        int64  `json:"size" binding:"required,gt=0" example:"102400"`        // 文件大小（字节）
+}
+
+// MediaResponse 媒体文件响应
+type MediaResponse struct {
+	ID           int       `json:"id"`
+	Purpose      string    `json:"purpose"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"`
+	ContentType  string    `json:"content_type"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width,omitempty"`
+	Height       int       `json:"height,omitempty"`
+	Status       string    `json:"status"`
+	CreateTime   time.Time `json:"create_time"`
+}
+
+// PresignUploadResponse 预签名上传响应
+// 客户端使用 upload 中的方法、地址和请求头上传文件，然后调用完成上传接口
+type PresignUploadResponse struct {
+	Media  *MediaResponse `json:"media"`
+	Upload *UploadTarget  `json:"upload"`
+}
+
+// UploadTarget 预签名上传目标
+type UploadTarget struct {
+	Method    string            `json:"method" example:"PUT"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// 更换手机号方式
+const (
+	PhoneChangeMethodVerify   = "verify"   // 验证原手机号
+	PhoneChangeMethodRecovery = "recovery" // 原手机号无法使用，由管理员签发更换凭证
+)
+
+// UserPhoneChange 手机号变更记录
+type UserPhoneChange struct {
+	ID         int       `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	UserID     int       `gorm:"column:user_id;type:int;index" json:"user_id"`
+	OldPhone   string    `gorm:"column:old_phone;type:varchar(255);serializer:encrypted" json:"old_phone"` // 加密存储
+	NewPhone   string    `gorm:"column:new_phone;type:varchar(255);serializer:encrypted" json:"new_phone"` // 加密存储
+	Method     string    `gorm:"column:method;type:varchar(20)" json:"method"`
+	OperatorID int       `gorm:"column:operator_id;type:int" json:"operator_id"` // 签发更换凭证的管理员 ID，验证原手机号时为用户本人
+	IP         string    `gorm:"column:ip;type:varchar(64)" json:"ip"`
+	UserAgent  string    `gorm:"column:user_agent;type:varchar(500)" json:"user_agent"`
+	RequestID  string    `gorm:"column:request_id;type:varchar(64)" json:"request_id"`
+	CreateTime time.Time `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+}
+
+// TableName 指定表名
+func (UserPhoneChange) TableName() string {
+	return "user_phone_changes"
+}
+
+// VerifyOldPhoneRequest 验证原手机号请求
+type VerifyOldPhoneRequest struct {
+	Code string `json:"code" binding:"required,len=6" example:"123456"`
+}
+
+// PhoneChangeTicketResponse 更换手机号凭证响应
+// 凭证用于发送新手机号验证码和提交更换，有效期内只能使用一次
+type PhoneChangeTicketResponse struct {
+	Ticket    string    `json:"ticket" example:"3f9a0c7e5b2d4e1f8a6c9b0d2e4f6a8c"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// SendNewPhoneCodeRequest 发送新手机号验证码请求
+type SendNewPhoneCodeRequest struct {
+	Ticket string `json:"ticket" binding:"required" example:"3f9a0c7e5b2d4e1f8a6c9b0d2e4f6a8c"`
+	Phone  string `json:"phone" binding:"required,phone" example:"+8613900139000"`
+}
+
+// ChangePhoneRequest 更换手机号请求
+type ChangePhoneRequest struct {
+	Ticket string `json:"ticket" binding:"required" example:"3f9a0c7e5b2d4e1f8a6c9b0d2e4f6a8c"`
+	Phone  string `json:"phone" binding:"required,phone" example:"+8613900139000"`
+	Code   string `json:"code" binding:"required,len=6" example:"123456"`
+}
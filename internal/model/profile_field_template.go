@@ -3,6 +3,7 @@ package model
 import (
 	"time"
 
+	"github.com/deantook/dove/pkg/query"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +17,7 @@ type ProfileFieldTemplate struct {
 	IsRequired         bool           `gorm:"column:is_required;type:tinyint(1);default:0" json:"is_required"`
 	IsSearchable       bool           `gorm:"column:is_searchable;type:tinyint(1);default:0" json:"is_searchable"`
 	IsPublic           bool           `gorm:"column:is_public;type:tinyint(1);default:0" json:"is_public"`
+	IsSensitive        bool           `gorm:"column:is_sensitive;type:tinyint(1);default:0" json:"is_sensitive"` // 敏感字段，用户资料字段的值加密存储
 	DefaultValue       string         `gorm:"column:default_value;type:text" json:"default_value"`
 	Options            string         `gorm:"column:options;type:text" json:"options"`
 	Validation         string         `gorm:"column:validation;type:text" json:"validation"`
@@ -43,6 +45,7 @@ type CreateProfileFieldTemplateRequest struct {
 	IsRequired         bool   `json:"is_required" example:"false"`
 	IsSearchable       bool   `json:"is_searchable" example:"true"`
 	IsPublic           bool   `json:"is_public" example:"false"`
+	IsSensitive        bool   `json:"is_sensitive" example:"false"`
 	DefaultValue       string `json:"default_value" binding:"omitempty" example:""`
 	Options            string `json:"options" binding:"omitempty" example:"{\"options\":[{\"key\":\"bachelor\",\"label\":\"本科\"}]}"`
 	Validation         string `json:"validation" binding:"omitempty" example:"{}"`
@@ -60,6 +63,7 @@ type UpdateProfileFieldTemplateRequest struct {
 	IsRequired         *bool  `json:"is_required" example:"false"`
 	IsSearchable       *bool  `json:"is_searchable" example:"true"`
 	IsPublic           *bool  `json:"is_public" example:"false"`
+	IsSensitive        *bool  `json:"is_sensitive" example:"false"`
 	DefaultValue       string `json:"default_value" binding:"omitempty" example:""`
 	Options            string `json:"options" binding:"omitempty" example:"{}"`
 	Validation         string `json:"validation" binding:"omitempty" example:"{}"`
@@ -71,6 +75,21 @@ type UpdateProfileFieldTemplateRequest struct {
 	IsActive           *bool  `json:"is_active" example:"true"`
 }
 
+// ProfileFieldTemplateListQuery 字段模板列表查询参数
+// 支持 category、field_type、is_active、keyword 过滤，
+// 按 id、category、display_order、field_key、created_at 排序，默认按分类和显示顺序
+var ProfileFieldTemplateListQuery = query.NewBuilder("id").
+	Filter("category", "category", query.Eq, query.String).
+	Filter("field_type", "field_type", query.Eq, query.String).
+	Filter("is_active", "is_active", query.Eq, query.Bool).
+	Filter("keyword", "field_name", query.Contains, query.String).
+	Sortable("id", "id").
+	Sortable("category", "category").
+	Sortable("display_order", "display_order").
+	Sortable("field_key", "field_key").
+	Sortable("created_at", "create_time").
+	DefaultSort("category,display_order,-created_at")
+
 // ProfileFieldTemplateResponse 字段模板响应
 type ProfileFieldTemplateResponse struct {
 	ID                 int       `json:"id"`
@@ -80,6 +99,7 @@ type ProfileFieldTemplateResponse struct {
 	IsRequired         bool      `json:"is_required"`
 	IsSearchable       bool      `json:"is_searchable"`
 	IsPublic           bool      `json:"is_public"`
+	IsSensitive        bool      `json:"is_sensitive"`
 	DefaultValue       string    `json:"default_value"`
 	Options            string    `json:"options"`
 	Validation         string    `json:"validation"`
@@ -103,6 +123,7 @@ func (t *ProfileFieldTemplate) ToResponse() *ProfileFieldTemplateResponse {
 		IsRequired:         t.IsRequired,
 		IsSearchable:       t.IsSearchable,
 		IsPublic:           t.IsPublic,
+		IsSensitive:        t.IsSensitive,
 		DefaultValue:       t.DefaultValue,
 		Options:            t.Options,
 		Validation:         t.Validation,
@@ -129,6 +150,7 @@ func (t *ProfileFieldTemplate) ApplyToUser(userID int) *ProfileField {
 		IsRequired:   t.IsRequired,
 		IsSearchable: t.IsSearchable,
 		IsPublic:     t.IsPublic,
+		IsSensitive:  t.IsSensitive,
 		DefaultValue: t.DefaultValue,
 		Options:      t.Options,
 		Validation:   t.Validation,
@@ -149,7 +171,8 @@ type ProfileField struct {
 	IsRequired   bool      `gorm:"column:is_required;type:tinyint(1);default:0" json:"is_required"`
 	IsSearchable bool      `gorm:"column:is_searchable;type:tinyint(1);default:0" json:"is_searchable"`
 	IsPublic     bool      `gorm:"column:is_public;type:tinyint(1);default:0" json:"is_public"`
-	DefaultValue string    `gorm:"column:default_value;type:text" json:"default_value"`
+	IsSensitive  bool      `gorm:"column:is_sensitive;type:tinyint(1);default:0" json:"is_sensitive"`
+	DefaultValue string    `gorm:"column:default_value;type:text;serializer:sensitive" json:"default_value"` // 字段的值，IsSensitive 为 true 时加密存储
 	Options      string    `gorm:"column:options;type:text" json:"options"`
 	Validation   string    `gorm:"column:validation;type:text" json:"validation"`
 	DisplayOrder int       `gorm:"column:display_order;type:int;default:0" json:"display_order"`
@@ -163,3 +186,47 @@ type ProfileField struct {
 func (ProfileField) TableName() string {
 	return "profile_fields"
 }
+
+// ProfileFieldListQuery 资料字段列表查询参数
+// 支持 field_type、is_public、is_system 过滤，按 id、display_order、field_key、created_at 排序，默认按显示顺序
+var ProfileFieldListQuery = query.NewBuilder("id").
+	Filter("field_type", "field_type", query.Eq, query.String).
+	Filter("is_public", "is_public", query.Eq, query.Bool).
+	Filter("is_system", "is_system", query.Eq, query.Bool).
+	Sortable("id", "id").
+	Sortable("display_order", "display_order").
+	Sortable("field_key", "field_key").
+	Sortable("created_at", "create_time").
+	DefaultSort("display_order")
+
+// ProfileFieldTemplateBundleVersion 字段模板导入导出文件的当前版本
+const ProfileFieldTemplateBundleVersion = 1
+
+// ProfileFieldTemplateBundle 字段模板导入导出文件（YAML/JSON）
+// 以 field_key 作为模板的唯一标识，用于在不同环境之间评审和迁移字段模板
+type ProfileFieldTemplateBundle struct {
+	Version    int                        `json:"version" yaml:"version"`
+	ExportedAt *time.Time                 `json:"exported_at,omitempty" yaml:"exported_at,omitempty"`
+	Templates  []ProfileFieldTemplateSpec `json:"templates" yaml:"templates"`
+}
+
+// ProfileFieldTemplateSpec 导入导出文件中的单个字段模板定义
+// Options、Validation、DefaultUnlockRules 以结构化对象表示，便于评审
+type ProfileFieldTemplateSpec struct {
+	FieldKey           string                 `json:"field_key" yaml:"field_key"`
+	FieldName          string                 `json:"field_name" yaml:"field_name"`
+	FieldType          string                 `json:"field_type" yaml:"field_type"`
+	Category           string                 `json:"category,omitempty" yaml:"category,omitempty"`
+	IsRequired         bool                   `json:"is_required" yaml:"is_required"`
+	IsSearchable       bool                   `json:"is_searchable" yaml:"is_searchable"`
+	IsPublic           bool                   `json:"is_public" yaml:"is_public"`
+	IsSensitive        bool                   `json:"is_sensitive,omitempty" yaml:"is_sensitive,omitempty"` // 用户资料字段的值加密存储
+	IsActive           *bool                  `json:"is_active,omitempty" yaml:"is_active,omitempty"`       // 为空时视为启用
+	DisplayOrder       int                    `json:"display_order" yaml:"display_order"`
+	DefaultValue       string                 `json:"default_value,omitempty" yaml:"default_value,omitempty"`
+	Options            map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+	Validation         map[string]interface{} `json:"validation,omitempty" yaml:"validation,omitempty"`
+	Icon               string                 `json:"icon,omitempty" yaml:"icon,omitempty"`
+	Description        string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	DefaultUnlockRules map[string]interface{} `json:"default_unlock_rules,omitempty" yaml:"default_unlock_rules,omitempty"`
+}
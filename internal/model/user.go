@@ -1,21 +1,58 @@
 package model
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
 	"time"
 
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/phone"
+	"github.com/deantook/dove/pkg/query"
 	"gorm.io/gorm"
 )
 
+// 用户角色
+const (
+	UserRoleUser  = "user"  // 普通用户
+	UserRoleAdmin = "admin" // 管理员
+)
+
+// DefaultUsernamePrefix 自动注册用户的默认用户名前缀，后接随机十六进制字符
+const DefaultUsernamePrefix = "user_"
+
+// NewDefaultUsername 生成自动注册用户的默认用户名
+// 不使用手机号，避免手机号以明文出现在用户名、接口响应和用户名搜索中
+func NewDefaultUsername() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return DefaultUsernamePrefix + hex.EncodeToString(b)
+}
+
+// 用户状态
+const (
+	UserStatusActive      = 1 // 正常
+	UserStatusFrozen      = 2 // 冻结，可设置到期时间，到期后自动恢复
+	UserStatusBanned      = 3 // 封禁，可设置到期时间，到期后自动恢复
+	UserStatusDeactivated = 4 // 停用，只能由管理员恢复
+)
+
 // User 用户模型
 type User struct {
-	ID         int            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
-	Username   string         `gorm:"column:username;type:varchar(255)" json:"username"`
-	Phone      string         `gorm:"column:phone;type:varchar(20);uniqueIndex" json:"phone"`
-	Avatar     string         `gorm:"column:avatar;type:varchar(500)" json:"avatar"`
-	Status     int            `gorm:"column:status;type:tinyint;default:1" json:"status"`
-	CreateTime time.Time      `gorm:"column:create_time;autoCreateTime" json:"create_time"`
-	UpdateTime time.Time      `gorm:"column:update_time;autoUpdateTime" json:"update_time"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              int            `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	Username        string         `gorm:"column:username;type:varchar(255)" json:"username"`
+	Phone           string         `gorm:"column:phone;type:varchar(255);serializer:encrypted" json:"phone"` // E.164 格式，加密存储
+	PhoneHash       string         `gorm:"column:phone_hash;type:char(64);index" json:"-"`                   // 手机号盲索引，由仓储写入，用于按手机号查询；唯一约束由生成列 active_phone_hash 实现，只作用于未删除的用户
+	Avatar          string         `gorm:"column:avatar;type:varchar(500)" json:"avatar"`
+	Status          int            `gorm:"column:status;type:tinyint;default:1" json:"status"`
+	StatusReason    string         `gorm:"column:status_reason;type:varchar(255)" json:"status_reason"`
+	StatusExpiresAt *time.Time     `gorm:"column:status_expires_at" json:"status_expires_at"` // 冻结或封禁的到期时间，为空表示不自动恢复
+	Role            string         `gorm:"column:role;type:varchar(20);default:user" json:"role"`
+	TokenVersion    int            `gorm:"column:token_version;type:int;default:0" json:"token_version"` // 递增后已签发的 token 全部失效
+	CreateTime      time.Time      `gorm:"column:create_time;autoCreateTime" json:"create_time"`
+	UpdateTime      time.Time      `gorm:"column:update_time;autoUpdateTime" json:"update_time"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TableName 指定表名
@@ -23,55 +60,152 @@ func (User) TableName() string {
 	return "u_user"
 }
 
+// UserPhoneHashColumn 手机号盲索引列，手机号加密存储，按手机号过滤时由仓储将过滤值转换为盲索引
+const UserPhoneHashColumn = "phone_hash"
+
+// UserListQuery 用户列表查询参数
+// 支持 status、role、created_from、created_to、phone（E.164 格式，精确匹配）、keyword 过滤，
+// 按 id、created_at、updated_at、username、status 排序
+var UserListQuery = query.NewBuilder("id").
+	Filter("status", "status", query.Eq, query.Int).
+	Filter("role", "role", query.Eq, query.String).
+	Filter("created_from", "create_time", query.Gte, query.Time).
+	Filter("created_to", "create_time", query.Lte, query.Time).
+	Filter("phone", UserPhoneHashColumn, query.Eq, query.String).
+	Filter("keyword", "username", query.Contains, query.String).
+	Sortable("id", "id").
+	Sortable("created_at", "create_time").
+	Sortable("updated_at", "update_time").
+	Sortable("username", "username").
+	Sortable("status", "status").
+	DefaultSort("-created_at")
+
 // CreateUserRequest 创建用户请求
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
-	Phone    string `json:"phone" binding:"required,phone" example:"13800138000"`
+	Phone    string `json:"phone" binding:"required,phone" example:"+8613800138000"`
 }
 
 // UpdateUserRequest 更新用户请求
+// 手机号是登录凭证，需要通过更换手机号流程修改
 type UpdateUserRequest struct {
 	Username string `json:"username" binding:"omitempty,min=3,max=50" example:"john_doe"`
-	Phone    string `json:"phone" binding:"omitempty,phone" example:"13800138000"`
 }
 
 // UserResponse 用户响应
 type UserResponse struct {
-	ID         int       `json:"id"`
-	Username   string    `json:"username"`
-	Phone      string    `json:"phone"`
-	Avatar     string    `json:"avatar"`
-	Status     int       `json:"status"`
-	CreateTime time.Time `json:"create_time"`
-	UpdateTime time.Time `json:"update_time"`
+	ID              int        `json:"id"`
+	Username        string     `json:"username"`
+	Phone           string     `json:"phone"` // E.164 格式，如 +8613800138000
+	Avatar          string     `json:"avatar"`
+	Status          int        `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+	Role            string     `json:"role"`
+	CreateTime      time.Time  `json:"create_time"`
+	UpdateTime      time.Time  `json:"update_time"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty"` // 仅管理员查询已删除用户时返回
+}
+
+// UserProfileResponse 用户资料响应，包含用户信息和全部资料字段
+type UserProfileResponse struct {
+	User   *UserResponse   `json:"user"`
+	Fields []*ProfileField `json:"fields"`
 }
 
 // ToResponse 转换为响应格式
 func (u *User) ToResponse() *UserResponse {
-	return &UserResponse{
-		ID:         u.ID,
-		Username:   u.Username,
-		Phone:      u.Phone,
-		Avatar:     u.Avatar,
-		Status:     u.Status,
-		CreateTime: u.CreateTime,
-		UpdateTime: u.UpdateTime,
+	resp := &UserResponse{
+		ID:              u.ID,
+		Username:        u.Username,
+		Phone:           u.Phone,
+		Avatar:          u.Avatar,
+		Status:          u.Status,
+		StatusReason:    u.StatusReason,
+		StatusExpiresAt: u.StatusExpiresAt,
+		Role:            u.Role,
+		CreateTime:      u.CreateTime,
+		UpdateTime:      u.UpdateTime,
+	}
+	if u.DeletedAt.Valid {
+		resp.DeletedAt = &u.DeletedAt.Time
+	}
+	return resp
+}
+
+// MaskPhone 脱敏手机号，非本人查看时使用
+func (r *UserResponse) MaskPhone() {
+	r.Phone = MaskPhone(r.Phone)
+}
+
+// MaskPhone 手机号脱敏，保留国家码，国内号码至少一半的数字脱敏，如 +86 13******000
+func MaskPhone(e164 string) string {
+	return phone.Mask(e164)
+}
+
+// CurrentStatus 当前生效的状态，冻结或封禁已到期但尚未被 user-status-expire 任务恢复时视为正常
+func (u *User) CurrentStatus(now time.Time) int {
+	if u.StatusExpiresAt != nil && !now.Before(*u.StatusExpiresAt) &&
+		(u.Status == UserStatusFrozen || u.Status == UserStatusBanned) {
+		return UserStatusActive
 	}
+	return u.Status
+}
+
+// StatusError 当前状态不允许登录和访问时返回对应的业务错误，否则返回 nil
+func (u *User) StatusError(now time.Time) *appErrors.AppError {
+	var err *appErrors.AppError
+	switch u.CurrentStatus(now) {
+	case UserStatusActive:
+		return nil
+	case UserStatusFrozen:
+		err = appErrors.New(http.StatusForbidden, appErrors.CodeUserFrozen, "账号已冻结")
+	case UserStatusBanned:
+		err = appErrors.New(http.StatusForbidden, appErrors.CodeUserBanned, "账号已封禁")
+	case UserStatusDeactivated:
+		err = appErrors.New(http.StatusForbidden, appErrors.CodeUserDeactivated, "账号已停用")
+	default:
+		err = appErrors.Forbidden("账号状态异常")
+	}
+
+	var details []string
+	if u.StatusReason != "" {
+		details = append(details, "原因: "+u.StatusReason)
+	}
+	if u.StatusExpiresAt != nil {
+		details = append(details, "解除时间: "+u.StatusExpiresAt.Format(time.RFC3339))
+	}
+	if len(details) > 0 {
+		err = err.WithDetail(strings.Join(details, "；"))
+	}
+	return err
+}
+
+// IsAdmin 是否为管理员
+func (u *User) IsAdmin() bool {
+	return u.Role == UserRoleAdmin
+}
+
+// UpdateUserStatusRequest 修改用户状态请求
+type UpdateUserStatusRequest struct {
+	Status    int        `json:"status" binding:"required,oneof=1 2 3 4" example:"2"` // 1 正常，2 冻结，3 封禁，4 停用
+	Reason    string     `json:"reason" binding:"max=255" example:"违反社区规范"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2026-01-01T00:00:00+08:00"` // 冻结或封禁的到期时间，为空表示不自动恢复
 }
 
 // SendCodeRequest 发送验证码请求
 type SendCodeRequest struct {
-	Phone string `json:"phone" binding:"required,phone" example:"13800138000"`
+	Phone string `json:"phone" binding:"required,phone" example:"+8613800138000"`
 }
 
 // SendCodeResponse 发送验证码响应
 type SendCodeResponse struct {
-	Code string `json:"code" example:"123456"` // 验证码（开发阶段返回，生产环境不返回）
+	Code string `json:"code,omitempty" example:"123456"` // 验证码，仅开启 sms.expose_code 时返回
 }
 
 // LoginRequest 登录/注册请求
 type LoginRequest struct {
-	Phone string `json:"phone" binding:"required,phone" example:"13800138000"`
+	Phone string `json:"phone" binding:"required,phone" example:"+8613800138000"`
 	Code  string `json:"code" binding:"required,len=6" example:"123456"`
 }
 
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/query"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepository 审计日志仓储接口
+// 审计日志只追加，不提供修改，仅按保留期删除
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *model.AuditLog) error
+	ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.AuditLog], error)
+	Count(ctx context.Context, spec *query.Spec) (int64, error)
+	ListSelfActions(ctx context.Context, userID int) ([]*model.AuditLog, error)
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+// auditLogRepository 审计日志仓储实现
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository 创建审计日志仓储实例
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+// Create 创建审计日志
+func (r *auditLogRepository) Create(ctx context.Context, log *model.AuditLog) error {
+	return database.Conn(ctx, r.db).Create(log).Error
+}
+
+// ListByCursor 按查询规格和游标获取审计日志列表
+func (r *auditLogRepository) ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.AuditLog], error) {
+	return query.FindPage[model.AuditLog](database.Conn(ctx, r.db), spec, cursor, limit)
+}
+
+// Count 按查询规格统计审计日志数量
+func (r *auditLogRepository) Count(ctx context.Context, spec *query.Spec) (int64, error) {
+	var total int64
+	err := spec.ApplyFilters(database.Conn(ctx, r.db).Model(&model.AuditLog{})).Count(&total).Error
+	return total, err
+}
+
+// ListSelfActions 按时间顺序获取用户本人对自己账号的操作记录（登录、修改资料等），不包含管理员等其他操作人的记录
+func (r *auditLogRepository) ListSelfActions(ctx context.Context, userID int) ([]*model.AuditLog, error) {
+	var logs []*model.AuditLog
+	err := database.Conn(ctx, r.db).
+		Where("actor_id = ? AND target_type = ? AND target_id = ?", userID, model.AuditTargetUser, userID).
+		Order("id ASC").
+		Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// DeleteBefore 删除 before 之前创建的审计日志，每次最多删除 limit 条，返回删除数量
+func (r *auditLogRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	db := database.Conn(ctx, r.db)
+	var ids []int
+	err := db.Model(&model.AuditLog{}).
+		Where("create_time < ?", before).
+		Order("id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	result := db.Where("id IN ?", ids).Delete(&model.AuditLog{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/cache"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/logger"
+)
+
+// templateCacheName 字段模板缓存名称
+const templateCacheName = "field_template"
+
+// cachedProfileFieldTemplateRepository 带缓存的系统资料字段模板仓储
+// 按 ID、字段标识、分类查询走缓存，写操作后清除新旧值对应的全部缓存键
+type cachedProfileFieldTemplateRepository struct {
+	ProfileFieldTemplateRepository
+	cache *cache.Cache
+}
+
+// NewCachedProfileFieldTemplateRepository 为字段模板仓储增加缓存，c 为 nil 时返回原仓储
+func NewCachedProfileFieldTemplateRepository(repo ProfileFieldTemplateRepository, c *cache.Cache) ProfileFieldTemplateRepository {
+	if c == nil {
+		return repo
+	}
+	return &cachedProfileFieldTemplateRepository{ProfileFieldTemplateRepository: repo, cache: c}
+}
+
+// inTx 判断上下文中是否存在事务，事务中直接查询数据库，避免读到或缓存未提交的数据
+func inTx(ctx context.Context) bool {
+	_, ok := database.TxFromContext(ctx)
+	return ok
+}
+
+// templateIDKey 按 ID 查询的缓存键
+func templateIDKey(id int) string {
+	return fmt.Sprintf("field_template:%d", id)
+}
+
+// templateFieldKeyKey 按字段标识查询的缓存键
+func templateFieldKeyKey(fieldKey string) string {
+	return "field_template:key:" + fieldKey
+}
+
+// templateCategoryKey 按分类查询的缓存键
+func templateCategoryKey(category string) string {
+	return "field_template:category:" + category
+}
+
+// templateKeys 字段模板相关的全部缓存键
+func templateKeys(templates ...*model.ProfileFieldTemplate) []string {
+	var keys []string
+	for _, t := range templates {
+		if t == nil {
+			continue
+		}
+		keys = append(keys, templateIDKey(t.ID), templateFieldKeyKey(t.FieldKey), templateCategoryKey(t.Category))
+	}
+	return keys
+}
+
+// GetByID 根据 ID 获取字段模板
+func (r *cachedProfileFieldTemplateRepository) GetByID(ctx context.Context, id int) (*model.ProfileFieldTemplate, error) {
+	if inTx(ctx) {
+		return r.ProfileFieldTemplateRepository.GetByID(ctx, id)
+	}
+	t, err := cache.Fetch(ctx, r.cache, templateCacheName, templateIDKey(id), func(ctx context.Context) (*model.ProfileFieldTemplate, error) {
+		return notFoundToCache(r.ProfileFieldTemplateRepository.GetByID(ctx, id))
+	})
+	return t, cacheToNotFound(err)
+}
+
+// GetByFieldKey 根据字段标识获取字段模板
+func (r *cachedProfileFieldTemplateRepository) GetByFieldKey(ctx context.Context, fieldKey string) (*model.ProfileFieldTemplate, error) {
+	if inTx(ctx) {
+		return r.ProfileFieldTemplateRepository.GetByFieldKey(ctx, fieldKey)
+	}
+	t, err := cache.Fetch(ctx, r.cache, templateCacheName, templateFieldKeyKey(fieldKey), func(ctx context.Context) (*model.ProfileFieldTemplate, error) {
+		return notFoundToCache(r.ProfileFieldTemplateRepository.GetByFieldKey(ctx, fieldKey))
+	})
+	return t, cacheToNotFound(err)
+}
+
+// GetByCategory 根据分类获取字段模板列表
+func (r *cachedProfileFieldTemplateRepository) GetByCategory(ctx context.Context, category string) ([]*model.ProfileFieldTemplate, error) {
+	if inTx(ctx) {
+		return r.ProfileFieldTemplateRepository.GetByCategory(ctx, category)
+	}
+	return cache.Fetch(ctx, r.cache, templateCacheName, templateCategoryKey(category), func(ctx context.Context) ([]*model.ProfileFieldTemplate, error) {
+		return r.ProfileFieldTemplateRepository.GetByCategory(ctx, category)
+	})
+}
+
+// Create 创建字段模板，同时清除该字段标识的空值缓存和所属分类的列表缓存
+func (r *cachedProfileFieldTemplateRepository) Create(ctx context.Context, template *model.ProfileFieldTemplate) error {
+	if err := r.ProfileFieldTemplateRepository.Create(ctx, template); err != nil {
+		return err
+	}
+	r.invalidate(ctx, templateKeys(template)...)
+	return nil
+}
+
+// Update 更新字段模板，清除修改前后的缓存键
+func (r *cachedProfileFieldTemplateRepository) Update(ctx context.Context, template *model.ProfileFieldTemplate) error {
+	old, _ := r.ProfileFieldTemplateRepository.GetByID(ctx, template.ID)
+	if err := r.ProfileFieldTemplateRepository.Update(ctx, template); err != nil {
+		return err
+	}
+	r.invalidate(ctx, templateKeys(old, template)...)
+	return nil
+}
+
+// Delete 删除字段模板（软删除）
+func (r *cachedProfileFieldTemplateRepository) Delete(ctx context.Context, id int) error {
+	old, _ := r.ProfileFieldTemplateRepository.GetByID(ctx, id)
+	if err := r.ProfileFieldTemplateRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	keys := templateKeys(old)
+	if old == nil {
+		keys = []string{templateIDKey(id)}
+	}
+	r.invalidate(ctx, keys...)
+	return nil
+}
+
+// Restore 恢复已软删除的字段模板
+func (r *cachedProfileFieldTemplateRepository) Restore(ctx context.Context, id int) error {
+	if err := r.ProfileFieldTemplateRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+	restored, _ := r.ProfileFieldTemplateRepository.GetByID(ctx, id)
+	keys := templateKeys(restored)
+	if restored == nil {
+		keys = []string{templateIDKey(id)}
+	}
+	r.invalidate(ctx, keys...)
+	return nil
+}
+
+// invalidate 清除缓存，失败时仅记录日志，由有效期兜底
+// 事务中立即清除一次，提交后再清除一次，避免提交前被其他请求以旧值回填
+func (r *cachedProfileFieldTemplateRepository) invalidate(ctx context.Context, keys ...string) {
+	del := func(ctx context.Context) {
+		if err := r.cache.Delete(ctx, keys...); err != nil {
+			logger.FromContext(ctx).WarnContext(ctx, "清除字段模板缓存失败", slog.Any("keys", keys), slog.Any("error", err))
+		}
+	}
+	if inTx(ctx) {
+		del(ctx)
+	}
+	database.AfterCommit(ctx, del)
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/cache"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/deantook/dove/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// userCacheName 用户缓存名称
+const userCacheName = "user"
+
+// cachedUserRepository 带缓存的用户仓储
+// 按 ID、用户名、手机号查询走缓存，写操作后清除新旧值对应的全部缓存键
+// 手机号在缓存键中使用盲索引，在缓存值中加密保存，Redis 中不出现明文手机号
+type cachedUserRepository struct {
+	UserRepository
+	cache   *cache.Cache
+	keyring *encryption.Keyring
+}
+
+// NewCachedUserRepository 为用户仓储增加缓存，c 为 nil 时返回原仓储
+func NewCachedUserRepository(repo UserRepository, c *cache.Cache, keyring *encryption.Keyring) UserRepository {
+	if c == nil {
+		return repo
+	}
+	return &cachedUserRepository{UserRepository: repo, cache: c, keyring: keyring}
+}
+
+// cachedUser 缓存中的用户
+type cachedUser struct {
+	model.User
+	Phone string `json:"phone"` // 手机号密文，覆盖 model.User 的明文手机号
+}
+
+// userPhoneAAD 缓存中手机号密文的附加认证数据，与数据库列一致
+var userPhoneAAD = encryption.ColumnAAD(model.User{}.TableName(), "phone")
+
+// userIDKey 按 ID 查询的缓存键
+func userIDKey(id int) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// userUsernameKey 按用户名查询的缓存键
+func userUsernameKey(username string) string {
+	return "user:username:" + username
+}
+
+// userPhoneKey 按手机号查询的缓存键，phoneHash 为手机号盲索引
+func userPhoneKey(phoneHash string) string {
+	return "user:phone:" + phoneHash
+}
+
+// userKeys 用户相关的全部缓存键
+func (r *cachedUserRepository) userKeys(users ...*model.User) []string {
+	var keys []string
+	for _, u := range users {
+		if u == nil {
+			continue
+		}
+		keys = append(keys, userIDKey(u.ID), userUsernameKey(u.Username), userPhoneKey(r.keyring.BlindIndex(u.Phone)))
+	}
+	return keys
+}
+
+// GetByID 根据 ID 获取用户
+func (r *cachedUserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	return r.fetch(ctx, userIDKey(id), func(ctx context.Context) (*model.User, error) {
+		return r.UserRepository.GetByID(ctx, id)
+	})
+}
+
+// GetByUsername 根据用户名获取用户
+func (r *cachedUserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.fetch(ctx, userUsernameKey(username), func(ctx context.Context) (*model.User, error) {
+		return r.UserRepository.GetByUsername(ctx, username)
+	})
+}
+
+// GetByPhone 根据手机号获取用户
+func (r *cachedUserRepository) GetByPhone(ctx context.Context, phone string) (*model.User, error) {
+	return r.fetch(ctx, userPhoneKey(r.keyring.BlindIndex(phone)), func(ctx context.Context) (*model.User, error) {
+		return r.UserRepository.GetByPhone(ctx, phone)
+	})
+}
+
+// Create 创建用户，同时清除该用户名和手机号的空值缓存
+func (r *cachedUserRepository) Create(ctx context.Context, user *model.User) error {
+	if err := r.UserRepository.Create(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, r.userKeys(user)...)
+	return nil
+}
+
+// Update 更新用户，清除修改前后的缓存键
+func (r *cachedUserRepository) Update(ctx context.Context, user *model.User) error {
+	old, _ := r.UserRepository.GetByID(ctx, user.ID)
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, r.userKeys(old, user)...)
+	return nil
+}
+
+// Delete 删除用户（软删除）
+func (r *cachedUserRepository) Delete(ctx context.Context, id int) error {
+	old, _ := r.UserRepository.GetByID(ctx, id)
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	keys := r.userKeys(old)
+	if old == nil {
+		keys = []string{userIDKey(id)}
+	}
+	r.invalidate(ctx, keys...)
+	return nil
+}
+
+// Restore 恢复已软删除的用户，清除删除期间写入的空值缓存
+func (r *cachedUserRepository) Restore(ctx context.Context, id int) error {
+	old, _ := r.UserRepository.GetDeletedByID(ctx, id)
+	if err := r.UserRepository.Restore(ctx, id); err != nil {
+		return err
+	}
+	keys := r.userKeys(old)
+	if old == nil {
+		keys = []string{userIDKey(id)}
+	}
+	r.invalidate(ctx, keys...)
+	return nil
+}
+
+// fetch 读取缓存，记录不存在时保持返回 gorm.ErrRecordNotFound
+// 事务中直接查询数据库，避免读到或缓存未提交的数据
+func (r *cachedUserRepository) fetch(ctx context.Context, key string, load func(ctx context.Context) (*model.User, error)) (*model.User, error) {
+	if inTx(ctx) {
+		return load(ctx)
+	}
+	cached, err := cache.Fetch(ctx, r.cache, userCacheName, key, func(ctx context.Context) (*cachedUser, error) {
+		user, err := load(ctx)
+		if err != nil {
+			return notFoundToCache[*cachedUser](nil, err)
+		}
+		return r.seal(user)
+	})
+	if err != nil {
+		return nil, cacheToNotFound(err)
+	}
+	return r.open(cached)
+}
+
+// seal 将用户转换为缓存值，手机号加密
+func (r *cachedUserRepository) seal(user *model.User) (*cachedUser, error) {
+	phone, err := r.keyring.Encrypt(user.Phone, userPhoneAAD)
+	if err != nil {
+		return nil, err
+	}
+	return &cachedUser{User: *user, Phone: phone}, nil
+}
+
+// open 将缓存值还原为用户，手机号解密
+func (r *cachedUserRepository) open(cached *cachedUser) (*model.User, error) {
+	user := cached.User
+	phone, err := r.keyring.Decrypt(cached.Phone, userPhoneAAD)
+	if err != nil {
+		return nil, err
+	}
+	user.Phone = phone
+	return &user, nil
+}
+
+// invalidate 清除缓存，失败时仅记录日志，由有效期兜底
+// 事务中立即清除一次，提交后再清除一次，避免提交前被其他请求以旧值回填
+func (r *cachedUserRepository) invalidate(ctx context.Context, keys ...string) {
+	del := func(ctx context.Context) {
+		if err := r.cache.Delete(ctx, keys...); err != nil {
+			logger.FromContext(ctx).WarnContext(ctx, "清除用户缓存失败", slog.Any("keys", keys), slog.Any("error", err))
+		}
+	}
+	if inTx(ctx) {
+		del(ctx)
+	}
+	database.AfterCommit(ctx, del)
+}
+
+// notFoundToCache 将 gorm.ErrRecordNotFound 转换为 cache.ErrNotFound 以写入空值缓存
+func notFoundToCache[T any](v T, err error) (T, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return v, cache.ErrNotFound
+	}
+	return v, err
+}
+
+// cacheToNotFound 将 cache.ErrNotFound 还原为 gorm.ErrRecordNotFound，调用方无需感知缓存
+func cacheToNotFound(err error) error {
+	if errors.Is(err, cache.ErrNotFound) {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/cache"
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// fakeUserRepository 基于内存的用户仓储，记录按 ID 查询的次数
+type fakeUserRepository struct {
+	UserRepository
+	users map[int]*model.User
+	gets  int
+}
+
+func (r *fakeUserRepository) find(match func(u *model.User) bool) (*model.User, error) {
+	r.gets++
+	for _, u := range r.users {
+		if match(u) {
+			clone := *u
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.ID == id })
+}
+
+func (r *fakeUserRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.Username == username })
+}
+
+func (r *fakeUserRepository) GetByPhone(ctx context.Context, phone string) (*model.User, error) {
+	return r.find(func(u *model.User) bool { return u.Phone == phone })
+}
+
+func (r *fakeUserRepository) Create(ctx context.Context, user *model.User) error {
+	clone := *user
+	r.users[user.ID] = &clone
+	return nil
+}
+
+func (r *fakeUserRepository) Update(ctx context.Context, user *model.User) error {
+	clone := *user
+	r.users[user.ID] = &clone
+	return nil
+}
+
+func (r *fakeUserRepository) Delete(ctx context.Context, id int) error {
+	delete(r.users, id)
+	return nil
+}
+
+func newTestCachedUserRepository(t *testing.T) (UserRepository, *fakeUserRepository, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	key := base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32)))
+	keyring, err := encryption.New(encryption.Options{ActiveKey: "v1", Keys: map[string]string{"v1": key}, IndexKey: key})
+	if err != nil {
+		t.Fatalf("encryption.New() error = %v", err)
+	}
+
+	fake := &fakeUserRepository{users: map[int]*model.User{
+		1: {ID: 1, Username: "alice", Phone: "+8613800138000"},
+	}}
+	c := cache.New(cache.Options{TTL: time.Minute, NegativeTTL: time.Minute}, client)
+	return NewCachedUserRepository(fake, c, keyring), fake, mr
+}
+
+func TestCachedUserRepositoryCachesLookups(t *testing.T) {
+	repo, fake, mr := newTestCachedUserRepository(t)
+	ctx := context.Background()
+
+	lookups := []struct {
+		name string
+		get  func() (*model.User, error)
+	}{
+		{"按 ID", func() (*model.User, error) { return repo.GetByID(ctx, 1) }},
+		{"按用户名", func() (*model.User, error) { return repo.GetByUsername(ctx, "alice") }},
+		{"按手机号", func() (*model.User, error) { return repo.GetByPhone(ctx, "+8613800138000") }},
+	}
+	for _, l := range lookups {
+		t.Run(l.name, func(t *testing.T) {
+			before := fake.gets
+			for range 2 {
+				u, err := l.get()
+				if err != nil || u.ID != 1 || u.Phone != "+8613800138000" {
+					t.Fatalf("get = %+v, %v, want user 1 with plaintext phone", u, err)
+				}
+			}
+			if got := fake.gets - before; got != 1 {
+				t.Errorf("underlying repository called %d times, want 1", got)
+			}
+		})
+	}
+
+	for _, key := range mr.Keys() {
+		if strings.Contains(key, "13800138000") {
+			t.Errorf("cache key %q contains plaintext phone", key)
+		}
+		if v, _ := mr.Get(key); strings.Contains(v, "13800138000") {
+			t.Errorf("cache value of %q contains plaintext phone", key)
+		}
+	}
+}
+
+func TestCachedUserRepositoryNegativeCache(t *testing.T) {
+	repo, fake, _ := newTestCachedUserRepository(t)
+	ctx := context.Background()
+
+	for range 2 {
+		if _, err := repo.GetByUsername(ctx, "bob"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("GetByUsername() error = %v, want gorm.ErrRecordNotFound", err)
+		}
+	}
+	if fake.gets != 1 {
+		t.Errorf("underlying repository called %d times, want 1", fake.gets)
+	}
+
+	// 创建后清除空值缓存
+	if err := repo.Create(ctx, &model.User{ID: 2, Username: "bob", Phone: "+8613800138001"}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if u, err := repo.GetByUsername(ctx, "bob"); err != nil || u.ID != 2 {
+		t.Errorf("GetByUsername() after Create() = %+v, %v, want user 2", u, err)
+	}
+}
+
+func TestCachedUserRepositoryUpdateInvalidatesOldAndNewKeys(t *testing.T) {
+	repo, _, _ := newTestCachedUserRepository(t)
+	ctx := context.Background()
+
+	// 预热缓存，同时缓存新用户名和新手机号不存在
+	repo.GetByID(ctx, 1)
+	repo.GetByUsername(ctx, "alice")
+	repo.GetByPhone(ctx, "+8613800138000")
+	repo.GetByUsername(ctx, "alice2")
+	repo.GetByPhone(ctx, "+8613800138009")
+
+	if err := repo.Update(ctx, &model.User{ID: 1, Username: "alice2", Phone: "+8613800138009"}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if u, err := repo.GetByID(ctx, 1); err != nil || u.Username != "alice2" || u.Phone != "+8613800138009" {
+		t.Errorf("GetByID() after Update() = %+v, %v, want updated user", u, err)
+	}
+	if _, err := repo.GetByUsername(ctx, "alice"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetByUsername(old) after Update() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if _, err := repo.GetByPhone(ctx, "+8613800138000"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetByPhone(old) after Update() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if u, err := repo.GetByUsername(ctx, "alice2"); err != nil || u.ID != 1 {
+		t.Errorf("GetByUsername(new) after Update() = %+v, %v, want user 1", u, err)
+	}
+	if u, err := repo.GetByPhone(ctx, "+8613800138009"); err != nil || u.ID != 1 {
+		t.Errorf("GetByPhone(new) after Update() = %+v, %v, want user 1", u, err)
+	}
+}
+
+func TestCachedUserRepositoryDeleteInvalidatesKeys(t *testing.T) {
+	repo, _, _ := newTestCachedUserRepository(t)
+	ctx := context.Background()
+
+	repo.GetByID(ctx, 1)
+	repo.GetByUsername(ctx, "alice")
+	repo.GetByPhone(ctx, "+8613800138000")
+
+	if err := repo.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := repo.GetByID(ctx, 1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetByID() after Delete() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if _, err := repo.GetByUsername(ctx, "alice"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetByUsername() after Delete() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+	if _, err := repo.GetByPhone(ctx, "+8613800138000"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetByPhone() after Delete() error = %v, want gorm.ErrRecordNotFound", err)
+	}
+}
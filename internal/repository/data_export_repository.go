@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"gorm.io/gorm"
+)
+
+// DataExportRepository 数据导出任务仓储接口
+type DataExportRepository interface {
+	Create(ctx context.Context, export *model.DataExport) error
+	GetByID(ctx context.Context, id int) (*model.DataExport, error)
+	Update(ctx context.Context, export *model.DataExport) error
+	ListRecentByUserID(ctx context.Context, userID, limit int) ([]*model.DataExport, error)
+	ListByUserID(ctx context.Context, userID int) ([]*model.DataExport, error)
+	ListPending(ctx context.Context, staleBefore time.Time, limit int) ([]*model.DataExport, error)
+	Claim(ctx context.Context, id int, staleBefore time.Time) (bool, error)
+	ListExpired(ctx context.Context, before time.Time, afterID, limit int) ([]*model.DataExport, error)
+	DeleteByUserID(ctx context.Context, userID int) error
+}
+
+// dataExportRepository 数据导出任务仓储实现
+type dataExportRepository struct {
+	db *gorm.DB
+}
+
+// NewDataExportRepository 创建数据导出任务仓储实例
+func NewDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &dataExportRepository{db: db}
+}
+
+// Create 创建数据导出任务
+// 用户已有未完成的任务时违反唯一约束 uk_user_data_exports_in_progress
+func (r *dataExportRepository) Create(ctx context.Context, export *model.DataExport) error {
+	return database.Conn(ctx, r.db).Create(export).Error
+}
+
+// GetByID 根据 ID 获取数据导出任务
+func (r *dataExportRepository) GetByID(ctx context.Context, id int) (*model.DataExport, error) {
+	var export model.DataExport
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Update 更新数据导出任务
+func (r *dataExportRepository) Update(ctx context.Context, export *model.DataExport) error {
+	return database.Conn(ctx, r.db).Save(export).Error
+}
+
+// ListRecentByUserID 获取用户最近的数据导出任务，按创建时间倒序
+func (r *dataExportRepository) ListRecentByUserID(ctx context.Context, userID, limit int) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// ListByUserID 获取用户的全部数据导出任务
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID int) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// ListPending 获取等待生成的任务，以及 staleBefore 之前开始生成但未完成（进程退出等原因中断）的任务
+func (r *dataExportRepository) ListPending(ctx context.Context, staleBefore time.Time, limit int) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	err := r.pending(database.Conn(ctx, r.db), staleBefore).
+		Order("id ASC").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// Claim 将任务标记为生成中，任务已被其他副本领取时返回 false
+func (r *dataExportRepository) Claim(ctx context.Context, id int, staleBefore time.Time) (bool, error) {
+	result := r.pending(database.Conn(ctx, r.db).Model(&model.DataExport{}), staleBefore).
+		Where("id = ?", id).
+		Updates(map[string]any{"status": model.DataExportStatusProcessing, "update_time": time.Now()})
+	return result.RowsAffected > 0, result.Error
+}
+
+// pending 限定等待生成或生成中断的任务
+func (r *dataExportRepository) pending(db *gorm.DB, staleBefore time.Time) *gorm.DB {
+	return db.Where("status = ? OR (status = ? AND update_time < ?)",
+		model.DataExportStatusPending, model.DataExportStatusProcessing, staleBefore)
+}
+
+// ListExpired 按 ID 顺序获取 before 之前保留到期的已生成任务，afterID 用于分批遍历
+func (r *dataExportRepository) ListExpired(ctx context.Context, before time.Time, afterID, limit int) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+	err := database.Conn(ctx, r.db).
+		Where("status = ? AND expires_at < ? AND id > ?", model.DataExportStatusReady, before, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&exports).Error
+	if err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// DeleteByUserID 删除用户的全部数据导出任务
+func (r *dataExportRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&model.DataExport{}).Error
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/encryption"
+	"gorm.io/gorm"
+)
+
+// EncryptedColumn 加密存储的列
+type EncryptedColumn struct {
+	Table      string
+	Column     string
+	HashColumn string // 盲索引列，为空表示没有
+	Sensitive  bool   // 只加密敏感标记列为 true 的行（serializer:sensitive）
+}
+
+// EncryptedColumns 全部加密存储的列，模型新增 serializer:encrypted 或 serializer:sensitive 字段时需要同时加入
+var EncryptedColumns = []EncryptedColumn{
+	{Table: model.User{}.TableName(), Column: "phone", HashColumn: model.UserPhoneHashColumn},
+	{Table: model.UserPhoneChange{}.TableName(), Column: "old_phone"},
+	{Table: model.UserPhoneChange{}.TableName(), Column: "new_phone"},
+	{Table: model.ProfileField{}.TableName(), Column: "default_value", Sensitive: true},
+}
+
+// ReencryptBatch 一批数据的重新加密结果
+type ReencryptBatch struct {
+	LastID  int // 本批最后一行的 ID，用于获取下一批
+	Scanned int
+	Updated int
+}
+
+// EncryptedColumnRepository 加密列仓储接口
+// 直接读写列的原始值，用于轮换密钥和迁移历史明文数据
+type EncryptedColumnRepository interface {
+	Reencrypt(ctx context.Context, column EncryptedColumn, afterID, limit int, decrypt bool) (*ReencryptBatch, error)
+	RenamePhoneUsernames(ctx context.Context, afterID, limit int) (*ReencryptBatch, error)
+}
+
+// encryptedColumnRepository 加密列仓储实现
+type encryptedColumnRepository struct {
+	db      *gorm.DB
+	keyring *encryption.Keyring
+}
+
+// NewEncryptedColumnRepository 创建加密列仓储实例
+func NewEncryptedColumnRepository(db *gorm.DB, keyring *encryption.Keyring) EncryptedColumnRepository {
+	return &encryptedColumnRepository{db: db, keyring: keyring}
+}
+
+// encryptedRow 加密列的原始值
+type encryptedRow struct {
+	ID        int
+	Value     *string
+	Hash      *string
+	Sensitive bool
+}
+
+// Reencrypt 按 ID 顺序处理 afterID 之后的最多 limit 行（包括已软删除的行）
+// 明文和非 active 密钥加密的值使用 active 密钥重新加密，并补齐或修正盲索引；decrypt 为 true 时还原为明文
+// 按行加密的列只加密标记为敏感的行，未标记但已加密的行（如标记被清除）还原为明文
+// 按原值条件更新，读取后被业务修改的行跳过（业务写入时已使用 active 密钥）
+func (r *encryptedColumnRepository) Reencrypt(ctx context.Context, column EncryptedColumn, afterID, limit int, decrypt bool) (*ReencryptBatch, error) {
+	db := database.Conn(ctx, r.db)
+	hashSelect := "NULL"
+	if column.HashColumn != "" {
+		hashSelect = column.HashColumn
+	}
+
+	sensitiveSelect := "1"
+	if column.Sensitive {
+		sensitiveSelect = encryption.SensitiveFlagColumn
+	}
+
+	var rows []encryptedRow
+	err := db.Table(column.Table).
+		Select(fmt.Sprintf("id, %s AS value, %s AS hash, %s AS sensitive", column.Column, hashSelect, sensitiveSelect)).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &ReencryptBatch{LastID: afterID, Scanned: len(rows)}
+	aad := encryption.ColumnAAD(column.Table, column.Column)
+	for _, row := range rows {
+		batch.LastID = row.ID
+		if row.Value == nil || *row.Value == "" {
+			continue
+		}
+
+		plaintext, err := r.keyring.Decrypt(*row.Value, aad)
+		if err != nil {
+			return batch, fmt.Errorf("解密 %s.%s（id=%d）失败: %w", column.Table, column.Column, row.ID, err)
+		}
+
+		updates := make(map[string]any)
+		switch {
+		case (decrypt || !row.Sensitive) && encryption.IsEncrypted(*row.Value):
+			updates[column.Column] = plaintext
+		case !decrypt && row.Sensitive && r.keyring.NeedsReencrypt(*row.Value):
+			ciphertext, err := r.keyring.Encrypt(plaintext, aad)
+			if err != nil {
+				return batch, err
+			}
+			updates[column.Column] = ciphertext
+		}
+		if hash := r.keyring.BlindIndex(plaintext); column.HashColumn != "" && (row.Hash == nil || *row.Hash != hash) {
+			updates[column.HashColumn] = hash
+		}
+		if len(updates) == 0 {
+			continue
+		}
+
+		result := db.Table(column.Table).
+			Where(fmt.Sprintf("id = ? AND %s = ?", column.Column), row.ID, *row.Value).
+			UpdateColumns(updates)
+		if result.Error != nil {
+			return batch, fmt.Errorf("更新 %s.%s（id=%d）失败: %w", column.Table, column.Column, row.ID, result.Error)
+		}
+		batch.Updated += int(result.RowsAffected)
+	}
+	return batch, nil
+}
+
+// phoneUsernameRow 用户名和加密手机号的原始值
+type phoneUsernameRow struct {
+	ID       int
+	Username string
+	Phone    *string
+}
+
+// RenamePhoneUsernames 按 ID 顺序处理 afterID 之后的最多 limit 个用户（包括已软删除的用户）
+// 此前自动注册的用户以手机号作为默认用户名，用户名与本人手机号相同时改为随机生成的默认用户名；
+// 用户自行设置的数字用户名与本人手机号不同，不会改写。按原用户名条件更新，读取后被修改的行跳过
+// 直接改写数据库，缓存中按旧用户名的记录在缓存有效期后失效
+func (r *encryptedColumnRepository) RenamePhoneUsernames(ctx context.Context, afterID, limit int) (*ReencryptBatch, error) {
+	db := database.Conn(ctx, r.db)
+	table := model.User{}.TableName()
+
+	var rows []phoneUsernameRow
+	err := db.Table(table).
+		Select("id, username, phone").
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &ReencryptBatch{LastID: afterID, Scanned: len(rows)}
+	aad := encryption.ColumnAAD(table, "phone")
+	for _, row := range rows {
+		batch.LastID = row.ID
+		if row.Phone == nil || *row.Phone == "" || row.Username == "" {
+			continue
+		}
+
+		phone, err := r.keyring.Decrypt(*row.Phone, aad)
+		if err != nil {
+			return batch, fmt.Errorf("解密 %s.phone（id=%d）失败: %w", table, row.ID, err)
+		}
+		if !isPhoneUsername(row.Username, phone) {
+			continue
+		}
+
+		result := db.Table(table).
+			Where("id = ? AND username = ?", row.ID, row.Username).
+			UpdateColumn("username", model.NewDefaultUsername())
+		if result.Error != nil {
+			return batch, fmt.Errorf("更新 %s.username（id=%d）失败: %w", table, row.ID, result.Error)
+		}
+		batch.Updated += int(result.RowsAffected)
+	}
+	return batch, nil
+}
+
+// isPhoneUsername 判断用户名是否为本人的手机号
+// 000008 之前只支持中国大陆手机号，当时注册的用户名为不带 +86 的 11 位号码
+func isPhoneUsername(username, phone string) bool {
+	return username == phone || (strings.HasPrefix(phone, "+86") && "+86"+username == phone)
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"gorm.io/gorm"
+)
+
+// MediaRepository 媒体文件仓储接口
+type MediaRepository interface {
+	Create(ctx context.Context, media *model.MediaObject) error
+	GetByID(ctx context.Context, id int) (*model.MediaObject, error)
+	GetByStorageKey(ctx context.Context, key string) (*model.MediaObject, error)
+	Update(ctx context.Context, media *model.MediaObject) error
+	Delete(ctx context.Context, id int) error
+	ListStale(ctx context.Context, before time.Time, afterID, limit int) ([]*model.MediaObject, error)
+	IsReferenced(ctx context.Context, media *model.MediaObject, urls ...string) (bool, error)
+	ListByUserID(ctx context.Context, userID int) ([]*model.MediaObject, error)
+	DeleteByUserID(ctx context.Context, userID int) error
+}
+
+// mediaRepository 媒体文件仓储实现
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+// NewMediaRepository 创建媒体文件仓储实例
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+// Create 创建媒体文件记录
+func (r *mediaRepository) Create(ctx context.Context, media *model.MediaObject) error {
+	return database.Conn(ctx, r.db).Create(media).Error
+}
+
+// GetByID 根据 ID 获取媒体文件
+func (r *mediaRepository) GetByID(ctx context.Context, id int) (*model.MediaObject, error) {
+	var media model.MediaObject
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetByStorageKey 根据对象存储路径获取媒体文件
+func (r *mediaRepository) GetByStorageKey(ctx context.Context, key string) (*model.MediaObject, error) {
+	var media model.MediaObject
+	err := database.Conn(ctx, r.db).Where("storage_key = ?", key).First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Update 更新媒体文件
+func (r *mediaRepository) Update(ctx context.Context, media *model.MediaObject) error {
+	return database.Conn(ctx, r.db).Save(media).Error
+}
+
+// Delete 删除媒体文件记录
+func (r *mediaRepository) Delete(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Delete(&model.MediaObject{}, id).Error
+}
+
+// ListStale 按 ID 顺序获取 before 之前最后更新的媒体文件，afterID 用于分批遍历
+func (r *mediaRepository) ListStale(ctx context.Context, before time.Time, afterID, limit int) ([]*model.MediaObject, error) {
+	var items []*model.MediaObject
+	err := database.Conn(ctx, r.db).
+		Where("update_time < ? AND id > ?", before, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// IsReferenced 判断媒体文件是否被用户头像、资料字段或字段模板图标引用
+// 已软删除的用户仍视为引用，文件在用户被彻底删除后才会清理
+func (r *mediaRepository) IsReferenced(ctx context.Context, media *model.MediaObject, urls ...string) (bool, error) {
+	db := database.Conn(ctx, r.db)
+
+	var count int64
+	if err := db.Unscoped().Model(&model.User{}).Where("avatar IN ?", urls).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// 资料字段的值以 JSON 形式保存在 default_value 中，按对象路径匹配
+	if err := db.Model(&model.ProfileField{}).
+		Where("icon IN ? OR default_value LIKE ?", urls, "%"+media.StorageKey+"%").
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	if err := db.Unscoped().Model(&model.ProfileFieldTemplate{}).Where("icon IN ?", urls).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListByUserID 获取用户上传的全部媒体文件
+func (r *mediaRepository) ListByUserID(ctx context.Context, userID int) ([]*model.MediaObject, error) {
+	var items []*model.MediaObject
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DeleteByUserID 删除用户的全部媒体文件记录
+func (r *mediaRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&model.MediaObject{}).Error
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"gorm.io/gorm"
+)
+
+// PhoneChangeRepository 手机号变更记录仓储接口
+// 变更记录只追加，不提供修改，仅在清除用户数据时删除
+type PhoneChangeRepository interface {
+	Create(ctx context.Context, change *model.UserPhoneChange) error
+	ListByUserID(ctx context.Context, userID int) ([]*model.UserPhoneChange, error)
+	DeleteByUserID(ctx context.Context, userID int) error
+}
+
+// phoneChangeRepository 手机号变更记录仓储实现
+type phoneChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewPhoneChangeRepository 创建手机号变更记录仓储实例
+func NewPhoneChangeRepository(db *gorm.DB) PhoneChangeRepository {
+	return &phoneChangeRepository{db: db}
+}
+
+// Create 创建手机号变更记录
+func (r *phoneChangeRepository) Create(ctx context.Context, change *model.UserPhoneChange) error {
+	return database.Conn(ctx, r.db).Create(change).Error
+}
+
+// ListByUserID 获取用户的全部手机号变更记录
+func (r *phoneChangeRepository) ListByUserID(ctx context.Context, userID int) ([]*model.UserPhoneChange, error) {
+	var changes []*model.UserPhoneChange
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("id ASC").Find(&changes).Error
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// DeleteByUserID 删除用户的全部手机号变更记录
+func (r *phoneChangeRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&model.UserPhoneChange{}).Error
+}
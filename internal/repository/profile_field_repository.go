@@ -1,19 +1,26 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/query"
 	"gorm.io/gorm"
 )
 
 // ProfileFieldRepository 资料字段仓储接口
 type ProfileFieldRepository interface {
-	Create(field *model.ProfileField) error
-	GetByID(id int) (*model.ProfileField, error)
-	GetByUserIDAndFieldKey(userID int, fieldKey string) (*model.ProfileField, error)
-	GetByUserID(userID int) ([]*model.ProfileField, error)
-	Update(field *model.ProfileField) error
-	Delete(id int) error
-	List(userID int, offset, limit int) ([]*model.ProfileField, int64, error)
+	Create(ctx context.Context, field *model.ProfileField) error
+	GetByID(ctx context.Context, id int) (*model.ProfileField, error)
+	GetByUserIDAndFieldKey(ctx context.Context, userID int, fieldKey string) (*model.ProfileField, error)
+	GetByUserID(ctx context.Context, userID int) ([]*model.ProfileField, error)
+	Update(ctx context.Context, field *model.ProfileField) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, userID int, spec *query.Spec, offset, limit int) ([]*model.ProfileField, int64, error)
+	ListByCursor(ctx context.Context, userID int, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.ProfileField], error)
+	Count(ctx context.Context, userID int, spec *query.Spec) (int64, error)
+	DeleteByUserID(ctx context.Context, userID int) error
 }
 
 // profileFieldRepository 资料字段仓储实现
@@ -27,14 +34,14 @@ func NewProfileFieldRepository(db *gorm.DB) ProfileFieldRepository {
 }
 
 // Create 创建字段
-func (r *profileFieldRepository) Create(field *model.ProfileField) error {
-	return r.db.Create(field).Error
+func (r *profileFieldRepository) Create(ctx context.Context, field *model.ProfileField) error {
+	return database.Conn(ctx, r.db).Create(field).Error
 }
 
 // GetByID 根据 ID 获取字段
-func (r *profileFieldRepository) GetByID(id int) (*model.ProfileField, error) {
+func (r *profileFieldRepository) GetByID(ctx context.Context, id int) (*model.ProfileField, error) {
 	var field model.ProfileField
-	err := r.db.Where("id = ?", id).First(&field).Error
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&field).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +49,9 @@ func (r *profileFieldRepository) GetByID(id int) (*model.ProfileField, error) {
 }
 
 // GetByUserIDAndFieldKey 根据用户ID和字段标识获取字段
-func (r *profileFieldRepository) GetByUserIDAndFieldKey(userID int, fieldKey string) (*model.ProfileField, error) {
+func (r *profileFieldRepository) GetByUserIDAndFieldKey(ctx context.Context, userID int, fieldKey string) (*model.ProfileField, error) {
 	var field model.ProfileField
-	err := r.db.Where("user_id = ? AND field_key = ?", userID, fieldKey).First(&field).Error
+	err := database.Conn(ctx, r.db).Where("user_id = ? AND field_key = ?", userID, fieldKey).First(&field).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,9 +59,9 @@ func (r *profileFieldRepository) GetByUserIDAndFieldKey(userID int, fieldKey str
 }
 
 // GetByUserID 根据用户ID获取所有字段
-func (r *profileFieldRepository) GetByUserID(userID int) ([]*model.ProfileField, error) {
+func (r *profileFieldRepository) GetByUserID(ctx context.Context, userID int) ([]*model.ProfileField, error) {
 	var fields []*model.ProfileField
-	err := r.db.Where("user_id = ?", userID).
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).
 		Order("display_order ASC").
 		Find(&fields).Error
 	if err != nil {
@@ -64,32 +71,49 @@ func (r *profileFieldRepository) GetByUserID(userID int) ([]*model.ProfileField,
 }
 
 // Update 更新字段
-func (r *profileFieldRepository) Update(field *model.ProfileField) error {
-	return r.db.Save(field).Error
+func (r *profileFieldRepository) Update(ctx context.Context, field *model.ProfileField) error {
+	return database.Conn(ctx, r.db).Save(field).Error
 }
 
 // Delete 删除字段
-func (r *profileFieldRepository) Delete(id int) error {
-	return r.db.Delete(&model.ProfileField{}, id).Error
+func (r *profileFieldRepository) Delete(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Delete(&model.ProfileField{}, id).Error
 }
 
-// List 获取字段列表（分页）
-func (r *profileFieldRepository) List(userID int, offset, limit int) ([]*model.ProfileField, int64, error) {
+// List 按查询规格获取用户的资料字段列表（分页）
+func (r *profileFieldRepository) List(ctx context.Context, userID int, spec *query.Spec, offset, limit int) ([]*model.ProfileField, int64, error) {
 	var fields []*model.ProfileField
-	var total int64
 
-	query := r.db.Model(&model.ProfileField{}).Where("user_id = ?", userID)
-
-	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
+	total, err := r.Count(ctx, userID, spec)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	// 获取列表
-	if err := query.Order("display_order ASC").
-		Offset(offset).Limit(limit).Find(&fields).Error; err != nil {
+	if err := spec.Apply(r.byUser(ctx, userID)).Offset(offset).Limit(limit).Find(&fields).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return fields, total, nil
 }
+
+// ListByCursor 按查询规格和游标获取用户的资料字段列表
+func (r *profileFieldRepository) ListByCursor(ctx context.Context, userID int, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.ProfileField], error) {
+	return query.FindPage[model.ProfileField](r.byUser(ctx, userID), spec, cursor, limit)
+}
+
+// Count 按查询规格统计用户的资料字段数量
+func (r *profileFieldRepository) Count(ctx context.Context, userID int, spec *query.Spec) (int64, error) {
+	var total int64
+	err := spec.ApplyFilters(r.byUser(ctx, userID).Model(&model.ProfileField{})).Count(&total).Error
+	return total, err
+}
+
+// byUser 限定为指定用户的资料字段
+func (r *profileFieldRepository) byUser(ctx context.Context, userID int) *gorm.DB {
+	return database.Conn(ctx, r.db).Where("user_id = ?", userID)
+}
+
+// DeleteByUserID 删除用户的全部字段
+func (r *profileFieldRepository) DeleteByUserID(ctx context.Context, userID int) error {
+	return database.Conn(ctx, r.db).Where("user_id = ?", userID).Delete(&model.ProfileField{}).Error
+}
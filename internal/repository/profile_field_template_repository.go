@@ -1,19 +1,28 @@
 package repository
 
 import (
+	"context"
+
 	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/query"
 	"gorm.io/gorm"
 )
 
 // ProfileFieldTemplateRepository 系统资料字段模板仓储接口
 type ProfileFieldTemplateRepository interface {
-	Create(template *model.ProfileFieldTemplate) error
-	GetByID(id int) (*model.ProfileFieldTemplate, error)
-	GetByFieldKey(fieldKey string) (*model.ProfileFieldTemplate, error)
-	Update(template *model.ProfileFieldTemplate) error
-	Delete(id int) error
-	List(category string, fieldType string, isActive *bool, offset, limit int) ([]*model.ProfileFieldTemplate, int64, error)
-	GetByCategory(category string) ([]*model.ProfileFieldTemplate, error)
+	Create(ctx context.Context, template *model.ProfileFieldTemplate) error
+	GetByID(ctx context.Context, id int) (*model.ProfileFieldTemplate, error)
+	GetByFieldKey(ctx context.Context, fieldKey string) (*model.ProfileFieldTemplate, error)
+	Update(ctx context.Context, template *model.ProfileFieldTemplate) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, spec *query.Spec, offset, limit int) ([]*model.ProfileFieldTemplate, int64, error)
+	ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.ProfileFieldTemplate], error)
+	Count(ctx context.Context, spec *query.Spec) (int64, error)
+	GetByCategory(ctx context.Context, category string) ([]*model.ProfileFieldTemplate, error)
+	ListAll(ctx context.Context, category string, fieldType string, isActive *bool) ([]*model.ProfileFieldTemplate, error)
+	ListWithDeleted(ctx context.Context) ([]*model.ProfileFieldTemplate, error)
+	Restore(ctx context.Context, id int) error
 }
 
 // profileFieldTemplateRepository 系统资料字段模板仓储实现
@@ -27,14 +36,14 @@ func NewProfileFieldTemplateRepository(db *gorm.DB) ProfileFieldTemplateReposito
 }
 
 // Create 创建字段模板
-func (r *profileFieldTemplateRepository) Create(template *model.ProfileFieldTemplate) error {
-	return r.db.Create(template).Error
+func (r *profileFieldTemplateRepository) Create(ctx context.Context, template *model.ProfileFieldTemplate) error {
+	return database.Conn(ctx, r.db).Create(template).Error
 }
 
 // GetByID 根据 ID 获取字段模板
-func (r *profileFieldTemplateRepository) GetByID(id int) (*model.ProfileFieldTemplate, error) {
+func (r *profileFieldTemplateRepository) GetByID(ctx context.Context, id int) (*model.ProfileFieldTemplate, error) {
 	var template model.ProfileFieldTemplate
-	err := r.db.Where("id = ?", id).First(&template).Error
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&template).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +51,9 @@ func (r *profileFieldTemplateRepository) GetByID(id int) (*model.ProfileFieldTem
 }
 
 // GetByFieldKey 根据字段标识获取字段模板
-func (r *profileFieldTemplateRepository) GetByFieldKey(fieldKey string) (*model.ProfileFieldTemplate, error) {
+func (r *profileFieldTemplateRepository) GetByFieldKey(ctx context.Context, fieldKey string) (*model.ProfileFieldTemplate, error) {
 	var template model.ProfileFieldTemplate
-	err := r.db.Where("field_key = ?", fieldKey).First(&template).Error
+	err := database.Conn(ctx, r.db).Where("field_key = ?", fieldKey).First(&template).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,55 +61,49 @@ func (r *profileFieldTemplateRepository) GetByFieldKey(fieldKey string) (*model.
 }
 
 // Update 更新字段模板
-func (r *profileFieldTemplateRepository) Update(template *model.ProfileFieldTemplate) error {
-	return r.db.Save(template).Error
+func (r *profileFieldTemplateRepository) Update(ctx context.Context, template *model.ProfileFieldTemplate) error {
+	return database.Conn(ctx, r.db).Save(template).Error
 }
 
 // Delete 删除字段模板（软删除）
-func (r *profileFieldTemplateRepository) Delete(id int) error {
-	return r.db.Delete(&model.ProfileFieldTemplate{}, id).Error
+func (r *profileFieldTemplateRepository) Delete(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Delete(&model.ProfileFieldTemplate{}, id).Error
 }
 
-// List 获取字段模板列表（分页）
-func (r *profileFieldTemplateRepository) List(category string, fieldType string, isActive *bool, offset, limit int) ([]*model.ProfileFieldTemplate, int64, error) {
+// List 按查询规格获取字段模板列表（分页）
+func (r *profileFieldTemplateRepository) List(ctx context.Context, spec *query.Spec, offset, limit int) ([]*model.ProfileFieldTemplate, int64, error) {
 	var templates []*model.ProfileFieldTemplate
 	var total int64
 
-	query := r.db.Model(&model.ProfileFieldTemplate{})
-
-	// 按分类筛选
-	if category != "" {
-		query = query.Where("category = ?", category)
-	}
-
-	// 按字段类型筛选
-	if fieldType != "" {
-		query = query.Where("field_type = ?", fieldType)
-	}
-
-	// 按启用状态筛选
-	if isActive != nil {
-		query = query.Where("is_active = ?", *isActive)
-	}
-
 	// 获取总数
-	if err := query.Count(&total).Error; err != nil {
+	if err := spec.ApplyFilters(database.Conn(ctx, r.db).Model(&model.ProfileFieldTemplate{})).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 获取列表，按分类和显示顺序排序
-	if err := query.Order("category ASC, display_order ASC, create_time DESC").
-		Offset(offset).Limit(limit).Find(&templates).Error; err != nil {
+	// 获取列表
+	if err := spec.Apply(database.Conn(ctx, r.db)).Offset(offset).Limit(limit).Find(&templates).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return templates, total, nil
 }
 
+// ListByCursor 按查询规格和游标获取字段模板列表
+func (r *profileFieldTemplateRepository) ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.ProfileFieldTemplate], error) {
+	return query.FindPage[model.ProfileFieldTemplate](database.Conn(ctx, r.db), spec, cursor, limit)
+}
+
+// Count 按查询规格统计字段模板数量
+func (r *profileFieldTemplateRepository) Count(ctx context.Context, spec *query.Spec) (int64, error) {
+	var total int64
+	err := spec.ApplyFilters(database.Conn(ctx, r.db).Model(&model.ProfileFieldTemplate{})).Count(&total).Error
+	return total, err
+}
+
 // GetByCategory 根据分类获取字段模板列表
-func (r *profileFieldTemplateRepository) GetByCategory(category string) ([]*model.ProfileFieldTemplate, error) {
+func (r *profileFieldTemplateRepository) GetByCategory(ctx context.Context, category string) ([]*model.ProfileFieldTemplate, error) {
 	var templates []*model.ProfileFieldTemplate
-	err := r.db.Where("category = ? AND is_active = ?", category, true).
+	err := database.Conn(ctx, r.db).Where("category = ? AND is_active = ?", category, true).
 		Order("display_order ASC").
 		Find(&templates).Error
 	if err != nil {
@@ -108,3 +111,40 @@ func (r *profileFieldTemplateRepository) GetByCategory(category string) ([]*mode
 	}
 	return templates, nil
 }
+
+// ListAll 获取全部符合条件的字段模板（不分页）
+func (r *profileFieldTemplateRepository) ListAll(ctx context.Context, category string, fieldType string, isActive *bool) ([]*model.ProfileFieldTemplate, error) {
+	var templates []*model.ProfileFieldTemplate
+
+	db := database.Conn(ctx, r.db).Model(&model.ProfileFieldTemplate{})
+	if category != "" {
+		db = db.Where("category = ?", category)
+	}
+	if fieldType != "" {
+		db = db.Where("field_type = ?", fieldType)
+	}
+	if isActive != nil {
+		db = db.Where("is_active = ?", *isActive)
+	}
+
+	if err := db.Order("category ASC, display_order ASC, field_key ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// ListWithDeleted 获取全部字段模板（包含已软删除的记录）
+func (r *profileFieldTemplateRepository) ListWithDeleted(ctx context.Context) ([]*model.ProfileFieldTemplate, error) {
+	var templates []*model.ProfileFieldTemplate
+	if err := database.Conn(ctx, r.db).Unscoped().Order("id ASC").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Restore 恢复已软删除的字段模板
+func (r *profileFieldTemplateRepository) Restore(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Unscoped().Model(&model.ProfileFieldTemplate{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
@@ -1,40 +1,58 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/deantook/dove/pkg/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository 用户仓储接口
 type UserRepository interface {
-	Create(user *model.User) error
-	GetByID(id int) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
-	GetByPhone(phone string) (*model.User, error)
-	Update(user *model.User) error
-	Delete(id int) error
-	List(offset, limit int) ([]*model.User, int64, error)
+	Create(ctx context.Context, user *model.User) error
+	GetByID(ctx context.Context, id int) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByPhone(ctx context.Context, phone string) (*model.User, error)
+	Update(ctx context.Context, user *model.User) error
+	Delete(ctx context.Context, id int) error
+	List(ctx context.Context, spec *query.Spec, offset, limit int) ([]*model.User, int64, error)
+	ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.User], error)
+	Count(ctx context.Context, spec *query.Spec) (int64, error)
+	ListStatusExpired(ctx context.Context, before time.Time, limit int) ([]*model.User, error)
+	GetDeletedByID(ctx context.Context, id int) (*model.User, error)
+	Restore(ctx context.Context, id int) error
+	ListDeletedBefore(ctx context.Context, before time.Time, afterID, limit int) ([]*model.User, error)
+	LockDeletedBefore(ctx context.Context, id int, before time.Time) (*model.User, error)
+	Purge(ctx context.Context, id int, before time.Time) (bool, error)
 }
 
 // userRepository 用户仓储实现
+// 手机号加密存储，写入时同时更新盲索引，按手机号查询和过滤使用盲索引
 type userRepository struct {
-	db *gorm.DB
+	db      *gorm.DB
+	keyring *encryption.Keyring
 }
 
 // NewUserRepository 创建用户仓储实例
-func NewUserRepository(db *gorm.DB) UserRepository {
-	return &userRepository{db: db}
+func NewUserRepository(db *gorm.DB, keyring *encryption.Keyring) UserRepository {
+	return &userRepository{db: db, keyring: keyring}
 }
 
 // Create 创建用户
-func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+func (r *userRepository) Create(ctx context.Context, user *model.User) error {
+	user.PhoneHash = r.keyring.BlindIndex(user.Phone)
+	return database.Conn(ctx, r.db).Create(user).Error
 }
 
 // GetByID 根据 ID 获取用户
-func (r *userRepository) GetByID(id int) (*model.User, error) {
+func (r *userRepository) GetByID(ctx context.Context, id int) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("id = ?", id).First(&user).Error
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +60,9 @@ func (r *userRepository) GetByID(id int) (*model.User, error) {
 }
 
 // GetByUsername 根据用户名获取用户
-func (r *userRepository) GetByUsername(username string) (*model.User, error) {
+func (r *userRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("username = ?", username).First(&user).Error
+	err := database.Conn(ctx, r.db).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,9 +70,12 @@ func (r *userRepository) GetByUsername(username string) (*model.User, error) {
 }
 
 // GetByPhone 根据手机号获取用户
-func (r *userRepository) GetByPhone(phone string) (*model.User, error) {
+// 迁移 000014 补齐历史数据的盲索引，滚动升级期间旧版本写入的数据没有盲索引，按明文手机号匹配
+func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*model.User, error) {
 	var user model.User
-	err := r.db.Where("phone = ?", phone).First(&user).Error
+	err := database.Conn(ctx, r.db).
+		Where("phone_hash = ? OR (phone_hash IS NULL AND phone = ?)", r.keyring.BlindIndex(phone), phone).
+		First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -62,29 +83,126 @@ func (r *userRepository) GetByPhone(phone string) (*model.User, error) {
 }
 
 // Update 更新用户
-func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+func (r *userRepository) Update(ctx context.Context, user *model.User) error {
+	user.PhoneHash = r.keyring.BlindIndex(user.Phone)
+	return database.Conn(ctx, r.db).Save(user).Error
 }
 
 // Delete 删除用户（软删除）
-func (r *userRepository) Delete(id int) error {
-	return r.db.Delete(&model.User{}, id).Error
+func (r *userRepository) Delete(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Delete(&model.User{}, id).Error
 }
 
-// List 获取用户列表（分页）
-func (r *userRepository) List(offset, limit int) ([]*model.User, int64, error) {
+// List 按查询规格获取用户列表（分页）
+func (r *userRepository) List(ctx context.Context, spec *query.Spec, offset, limit int) ([]*model.User, int64, error) {
 	var users []*model.User
 	var total int64
+	spec = r.resolveSpec(spec)
 
 	// 获取总数
-	if err := r.db.Model(&model.User{}).Count(&total).Error; err != nil {
+	if err := spec.ApplyFilters(database.Conn(ctx, r.db).Model(&model.User{})).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// 获取列表
-	if err := r.db.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	if err := spec.Apply(database.Conn(ctx, r.db)).Offset(offset).Limit(limit).Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
+
+// ListByCursor 按查询规格和游标获取用户列表
+func (r *userRepository) ListByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int) (*query.CursorPage[*model.User], error) {
+	return query.FindPage[model.User](database.Conn(ctx, r.db), r.resolveSpec(spec), cursor, limit)
+}
+
+// Count 按查询规格统计用户数量
+func (r *userRepository) Count(ctx context.Context, spec *query.Spec) (int64, error) {
+	var total int64
+	err := r.resolveSpec(spec).ApplyFilters(database.Conn(ctx, r.db).Model(&model.User{})).Count(&total).Error
+	return total, err
+}
+
+// resolveSpec 将手机号过滤值转换为盲索引，返回新的查询规格，不修改原规格（游标签名基于原规格）
+func (r *userRepository) resolveSpec(spec *query.Spec) *query.Spec {
+	resolved := *spec
+	resolved.Filters = make([]query.Filter, len(spec.Filters))
+	for i, f := range spec.Filters {
+		if f.Column == model.UserPhoneHashColumn {
+			f.Value = r.keyring.BlindIndex(f.Value.(string))
+		}
+		resolved.Filters[i] = f
+	}
+	return &resolved
+}
+
+// ListStatusExpired 获取冻结或封禁已到期的用户
+func (r *userRepository) ListStatusExpired(ctx context.Context, before time.Time, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := database.Conn(ctx, r.db).
+		Where("status IN ? AND status_expires_at <= ?", []int{model.UserStatusFrozen, model.UserStatusBanned}, before).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetDeletedByID 根据 ID 获取已软删除的用户
+func (r *userRepository) GetDeletedByID(ctx context.Context, id int) (*model.User, error) {
+	var user model.User
+	err := database.Conn(ctx, r.db).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Restore 恢复已软删除的用户，token 版本递增，删除前签发的 token 不再有效
+func (r *userRepository) Restore(ctx context.Context, id int) error {
+	return database.Conn(ctx, r.db).Unscoped().Model(&model.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]any{
+			"deleted_at":    nil,
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+}
+
+// ListDeletedBefore 按 ID 顺序获取 before 之前软删除的用户，afterID 用于分批遍历
+func (r *userRepository) ListDeletedBefore(ctx context.Context, before time.Time, afterID, limit int) ([]*model.User, error) {
+	var users []*model.User
+	err := database.Conn(ctx, r.db).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND id > ?", before, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// LockDeletedBefore 获取 before 之前软删除的用户并加行锁，需在事务中调用
+// 用户已恢复或已清除时返回 gorm.ErrRecordNotFound
+func (r *userRepository) LockDeletedBefore(ctx context.Context, id int, before time.Time) (*model.User, error) {
+	var user model.User
+	err := database.Conn(ctx, r.db).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NOT NULL AND deleted_at < ?", id, before).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Purge 物理删除 before 之前软删除的用户，用户已恢复或已清除时返回 false
+func (r *userRepository) Purge(ctx context.Context, id int, before time.Time) (bool, error) {
+	result := database.Conn(ctx, r.db).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL AND deleted_at < ?", id, before).
+		Delete(&model.User{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,14 +1,24 @@
 package router
 
 import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
 	_ "github.com/deantook/dove/api/swagger" // Swagger 文档
+	"github.com/deantook/dove/internal/config"
 	"github.com/deantook/dove/internal/handler"
 	"github.com/deantook/dove/internal/middleware"
-	"github.com/deantook/dove/pkg/response"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/phone"
+	"github.com/deantook/dove/pkg/tracing"
 	customValidator "github.com/deantook/dove/pkg/validator"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/prometheus/client_golang/prometheus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
@@ -18,26 +28,86 @@ type Router struct {
 	engine               *gin.Engine
 	userHandler          *handler.UserHandler
 	fieldTemplateHandler *handler.ProfileFieldTemplateHandler
+	fieldHandler         *handler.ProfileFieldHandler
+	mediaHandler         *handler.MediaHandler
+	exportHandler        *handler.DataExportHandler
+	auditLogHandler      *handler.AuditLogHandler
+	healthHandler        *handler.HealthHandler
+	metricsConfig        *config.MetricsConfig
+	storageConfig        *config.StorageConfig
+	metricsRegistry      *prometheus.Registry
+	tracer               *tracing.Provider
+	rateLimiter          *middleware.RateLimiter
+	authenticator        *middleware.Authenticator
 }
 
 // NewRouter 创建路由实例
-func NewRouter(userHandler *handler.UserHandler, fieldTemplateHandler *handler.ProfileFieldTemplateHandler) *Router {
+func NewRouter(
+	userHandler *handler.UserHandler,
+	fieldTemplateHandler *handler.ProfileFieldTemplateHandler,
+	fieldHandler *handler.ProfileFieldHandler,
+	mediaHandler *handler.MediaHandler,
+	exportHandler *handler.DataExportHandler,
+	auditLogHandler *handler.AuditLogHandler,
+	healthHandler *handler.HealthHandler,
+	configProvider *config.Provider,
+	serverConfig *config.ServerConfig,
+	metricsConfig *config.MetricsConfig,
+	storageConfig *config.StorageConfig,
+	metricsRegistry *prometheus.Registry,
+	tracer *tracing.Provider,
+	rateLimiter *middleware.RateLimiter,
+	authenticator *middleware.Authenticator,
+	phoneParser *phone.Parser,
+) (*Router, error) {
 	engine := gin.New()
 
-	// 注册自定义验证器
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		customValidator.RegisterPhoneValidator(v)
+	// 只信任配置的反向代理转发的 X-Forwarded-For，否则客户端可伪造 IP 绕过按 IP 限流
+	var trustedProxies []string
+	if len(serverConfig.TrustedProxies) > 0 {
+		trustedProxies = serverConfig.TrustedProxies
+	}
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("server.trusted_proxies 无效: %w", err)
+	}
+
+	// 注册自定义验证器，注册失败时 phone 标签不生效，拒绝启动
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil, fmt.Errorf("不支持的请求验证器: %T", binding.Validator.Engine())
+	}
+	if err := customValidator.RegisterPhoneValidator(v, phoneParser); err != nil {
+		return nil, fmt.Errorf("注册手机号验证器失败: %w", err)
 	}
 
 	// 注册中间件
+	if tracer.Enabled() {
+		engine.Use(middleware.Tracing(tracer))
+	}
+	engine.Use(middleware.RequestID())
+	engine.Use(middleware.RequestInfo())
 	engine.Use(middleware.Logger())
-	engine.Use(middleware.CORS())
+	engine.Use(middleware.CORS(configProvider))
+	if metricsConfig.Enabled {
+		engine.Use(middleware.Metrics())
+	}
 
 	return &Router{
 		engine:               engine,
 		userHandler:          userHandler,
 		fieldTemplateHandler: fieldTemplateHandler,
-	}
+		fieldHandler:         fieldHandler,
+		mediaHandler:         mediaHandler,
+		exportHandler:        exportHandler,
+		auditLogHandler:      auditLogHandler,
+		healthHandler:        healthHandler,
+		metricsConfig:        metricsConfig,
+		storageConfig:        storageConfig,
+		metricsRegistry:      metricsRegistry,
+		tracer:               tracer,
+		rateLimiter:          rateLimiter,
+		authenticator:        authenticator,
+	}, nil
 }
 
 // SetupRoutes 设置路由
@@ -45,47 +115,132 @@ func (r *Router) SetupRoutes() {
 	// Swagger 文档
 	r.engine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	// API v1 路由组
-	v1 := r.engine.Group("/api/v1")
+	// API v1 路由组，携带 token 时识别当前用户
+	v1 := r.engine.Group("/api/v1", r.authenticator.OptionalAuth())
 	{
 		// 认证相关路由
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/send-code", r.userHandler.SendCode)
-			auth.POST("/login", r.userHandler.LoginOrRegister)
+			auth.POST("/send-code", r.rateLimiter.Policy("send_code"), r.userHandler.SendCode)
+			auth.POST("/login", r.rateLimiter.Policy("login"), r.userHandler.LoginOrRegister)
 		}
 
 		// 用户相关路由
-		users := v1.Group("/users")
+		users := v1.Group("/users", r.rateLimiter.Policy("users"))
+		{
+			users.POST("", middleware.RequireAuth(), middleware.RequireAdmin(), r.userHandler.CreateUser)
+			users.GET("", middleware.RequireAuth(), r.userHandler.ListUsers)
+
+			// 非管理员只能访问自己的账号
+			owner := users.Group("/:id", middleware.RequireAuth(), middleware.RequireOwnerOrAdmin("id"))
+			{
+				owner.GET("", r.userHandler.GetUser)
+				owner.PUT("", r.userHandler.UpdateUser)
+				owner.DELETE("", r.userHandler.DeleteUser)
+			}
+			users.GET("/:id/profile-fields", r.fieldHandler.ListUserFields)
+
+			// 管理员操作
+			admin := users.Group("/:id", middleware.RequireAuth(), middleware.RequireAdmin())
+			{
+				admin.POST("/phone-change-ticket", r.userHandler.IssuePhoneChangeTicket)
+				admin.PUT("/status", r.userHandler.UpdateUserStatus)
+				admin.POST("/restore", r.userHandler.RestoreUser)
+			}
+		}
+
+		// 当前用户相关路由
+		me := v1.Group("/me", middleware.RequireAuth(), r.rateLimiter.Policy("users"))
+		{
+			me.GET("", r.userHandler.GetMe)
+			me.PATCH("", r.userHandler.UpdateMe)
+			me.DELETE("", r.userHandler.DeleteMe)
+			me.GET("/profile", r.fieldHandler.GetMyProfile)
+			me.GET("/fields", r.fieldHandler.ListMyFields)
+
+			// 更换手机号：验证原手机号（或管理员签发凭证）后验证新手机号
+			me.POST("/phone/old-code", r.rateLimiter.Policy("send_code"), r.userHandler.SendOldPhoneCode)
+			me.POST("/phone/verify-old", r.userHandler.VerifyOldPhone)
+			me.POST("/phone/new-code", r.rateLimiter.Policy("send_code"), r.userHandler.SendNewPhoneCode)
+			me.PUT("/phone", r.userHandler.ChangePhone)
+
+			// 个人数据导出，申请导出按用户限流
+			me.POST("/exports", r.rateLimiter.Policy("data_export"), r.exportHandler.CreateExport)
+			me.GET("/exports", r.exportHandler.ListExports)
+			me.GET("/exports/:id", r.exportHandler.GetExport)
+		}
+
+		// 本地存储的导出文件下载，由签名鉴权
+		v1.GET("/downloads/*key", r.exportHandler.Download)
+
+		// 媒体文件上传，本地存储的预签名上传地址由签名鉴权
+		v1.PUT("/media/uploads/*key", r.mediaHandler.ReceiveUpload)
+		media := v1.Group("/media", middleware.RequireAuth(), r.rateLimiter.Policy("media"))
 		{
-			users.POST("", r.userHandler.CreateUser)
-			users.GET("", r.userHandler.ListUsers)
-			users.GET("/:id", r.userHandler.GetUser)
-			users.PUT("/:id", r.userHandler.UpdateUser)
-			users.DELETE("/:id", r.userHandler.DeleteUser)
+			media.POST("", r.mediaHandler.Upload)
+			media.POST("/presign", r.mediaHandler.PresignUpload)
+			media.POST("/:id/complete", r.mediaHandler.CompleteUpload)
+			media.GET("/:id", r.mediaHandler.GetMedia)
+			media.DELETE("/:id", r.mediaHandler.DeleteMedia)
 		}
 
 		// 系统资料字段模板相关路由
-		fieldTemplates := v1.Group("/profile/field-templates")
+		fieldTemplates := v1.Group("/profile/field-templates", r.rateLimiter.Policy("field_templates"))
 		{
 			fieldTemplates.GET("", r.fieldTemplateHandler.ListTemplates)
 			fieldTemplates.GET("/key/:key", r.fieldTemplateHandler.GetTemplateByFieldKey)
 			fieldTemplates.GET("/category/:category", r.fieldTemplateHandler.GetTemplatesByCategory)
-			fieldTemplates.POST("", r.fieldTemplateHandler.CreateTemplate)
 			fieldTemplates.GET("/:id", r.fieldTemplateHandler.GetTemplate)
-			fieldTemplates.PUT("/:id", r.fieldTemplateHandler.UpdateTemplate)
-			fieldTemplates.DELETE("/:id", r.fieldTemplateHandler.DeleteTemplate)
 			fieldTemplates.POST("/:id/apply", r.fieldTemplateHandler.ApplyTemplateToUser)
 			fieldTemplates.POST("/apply", r.fieldTemplateHandler.ApplyTemplatesToUser)
+
+			// 管理员操作
+			templateAdmin := fieldTemplates.Group("", middleware.RequireAuth(), middleware.RequireAdmin())
+			{
+				templateAdmin.GET("/export", r.fieldTemplateHandler.ExportTemplates)
+				templateAdmin.POST("/import", r.fieldTemplateHandler.ImportTemplates)
+				templateAdmin.POST("", r.fieldTemplateHandler.CreateTemplate)
+				templateAdmin.PUT("/:id", r.fieldTemplateHandler.UpdateTemplate)
+				templateAdmin.DELETE("/:id", r.fieldTemplateHandler.DeleteTemplate)
+			}
+		}
+
+		// 审计日志（仅管理员）
+		auditLogs := v1.Group("/audit-logs", middleware.RequireAuth(), middleware.RequireAdmin())
+		{
+			auditLogs.GET("", r.auditLogHandler.ListAuditLogs)
 		}
 	}
 
-	// 健康检查
-	r.engine.GET("/health", func(c *gin.Context) {
-		response.Success(c, gin.H{
-			"status": "ok",
-		})
+	// 本地存储的上传文件
+	if r.storageConfig.Driver == "local" && strings.HasPrefix(r.storageConfig.Local.BaseURL, "/") {
+		r.setupUploads()
+	}
+
+	// 健康检查，/health 保留为 /livez 的别名
+	r.engine.GET("/livez", r.healthHandler.Livez)
+	r.engine.GET("/readyz", r.healthHandler.Readyz)
+	r.engine.GET("/health", r.healthHandler.Livez)
+
+	// Prometheus 指标，配置了独立监听地址时由 serve 命令单独暴露
+	if r.metricsConfig.Enabled && r.metricsConfig.Addr == "" {
+		r.engine.GET(r.metricsConfig.GetPath(), gin.WrapH(metrics.Handler(r.metricsRegistry)))
+	}
+}
+
+// setupUploads 提供本地存储上传文件的静态访问，禁止浏览器按内容猜测类型
+// 导出文件保存在 private_root 中，不在静态目录下；升级前写入静态目录的导出文件仍然拒绝访问，
+// 路径先规范化再判断，避免 //exports/、/./exports/、/a/../exports/ 等写法绕过
+func (r *Router) setupUploads() {
+	exportPath := "/" + model.DataExportKeyPrefix
+	uploads := r.engine.Group(r.storageConfig.Local.BaseURL, func(c *gin.Context) {
+		if strings.HasPrefix(path.Clean("/"+c.Param("filepath"))+"/", exportPath) {
+			c.AbortWithStatus(http.StatusNotFound)
+			return
+		}
+		c.Header("X-Content-Type-Options", "nosniff")
 	})
+	uploads.Static("/", r.storageConfig.Local.Root)
 }
 
 // GetEngine 获取 Gin 引擎
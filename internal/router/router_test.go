@@ -0,0 +1,63 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestUploadsRejectsExportPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	root := t.TempDir()
+	for name, content := range map[string]string{
+		"avatar/1/a.jpg":    "image",
+		"exports/1/x.zip":   "export",
+		"a/placeholder.txt": "",
+	} {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Router{
+		engine:        gin.New(),
+		storageConfig: &config.StorageConfig{Driver: "local", Local: config.LocalStorageConfig{Root: root, BaseURL: "/uploads"}},
+	}
+	r.setupUploads()
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/uploads/avatar/1/a.jpg", http.StatusOK},
+		{"/uploads/exports/1/x.zip", http.StatusNotFound},
+		{"/uploads//exports/1/x.zip", http.StatusNotFound},
+		{"/uploads/./exports/1/x.zip", http.StatusNotFound},
+		{"/uploads/a/../exports/1/x.zip", http.StatusNotFound},
+		{"/uploads/avatar/../exports/1/x.zip", http.StatusNotFound},
+		{"/uploads/exports/", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = tt.path // 不经过 URL 解析，保留原始的 . 和 .. 路径段
+			w := httptest.NewRecorder()
+			r.engine.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("GET %s = %d, want %d", tt.path, w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && w.Header().Get("X-Content-Type-Options") != "nosniff" {
+				t.Errorf("GET %s missing X-Content-Type-Options", tt.path)
+			}
+		})
+	}
+}
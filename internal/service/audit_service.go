@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/requestinfo"
+)
+
+// auditPurgeBatchSize 每批删除的过期审计日志数量
+const auditPurgeBatchSize = 1000
+
+// auditIgnoredFields 不记录变更的字段
+var auditIgnoredFields = map[string]bool{"update_time": true}
+
+// AuditEvent 审计事件
+// Before、After 为变更前后的对象，按 JSON 字段比较，创建时 Before 为 nil，删除时 After 为 nil
+// 包含手机号等个人信息的对象应先脱敏再传入
+type AuditEvent struct {
+	Action     string
+	TargetType string
+	TargetID   int
+	ActorID    int // 操作人 ID，为 0 时取 context 中的当前用户
+	Before     any
+	After      any
+}
+
+// Auditor 审计日志钩子，由 Service 层在敏感操作成功后调用
+// 在事务中调用时审计日志随事务提交或回滚；写入失败只记录错误日志，不影响业务操作
+type Auditor interface {
+	Record(ctx context.Context, event *AuditEvent)
+}
+
+// AuditService 审计日志服务接口
+type AuditService interface {
+	Auditor
+	ListLogsByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.AuditLogResponse], *int64, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+// auditService 审计日志服务实现
+type auditService struct {
+	auditRepo   repository.AuditLogRepository
+	auditConfig *config.AuditConfig
+}
+
+// NewAuditService 创建审计日志服务实例
+func NewAuditService(auditRepo repository.AuditLogRepository, auditConfig *config.AuditConfig) AuditService {
+	return &auditService{
+		auditRepo:   auditRepo,
+		auditConfig: auditConfig,
+	}
+}
+
+// Record 记录审计日志，操作来源取自 context 中的请求来源信息
+func (s *auditService) Record(ctx context.Context, event *AuditEvent) {
+	info := requestinfo.FromContext(ctx)
+	log := &model.AuditLog{
+		ActorID:    event.ActorID,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		IP:         info.IP,
+		UserAgent:  truncate(info.UserAgent, 500),
+		RequestID:  info.RequestID,
+	}
+	if log.ActorID == 0 {
+		log.ActorID = info.ActorID
+	}
+
+	changes, err := diffChanges(event.Before, event.After)
+	if err == nil && len(changes) > 0 {
+		var data []byte
+		data, err = json.Marshal(changes)
+		log.Changes = string(data)
+	}
+	if err == nil {
+		err = s.auditRepo.Create(ctx, log)
+	}
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "写入审计日志失败",
+			slog.String("action", event.Action),
+			slog.Int("target_id", event.TargetID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// ListLogsByCursor 按查询规格和游标获取审计日志列表，withTotal 为 true 时同时返回总数
+func (s *auditService) ListLogsByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.AuditLogResponse], *int64, error) {
+	page, err := s.auditRepo.ListByCursor(ctx, spec, cursor, limit)
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询审计日志失败", slog.Any("error", err))
+		return nil, nil, errors.New("查询审计日志失败")
+	}
+
+	var total *int64
+	if withTotal {
+		count, err := s.auditRepo.Count(ctx, spec)
+		if err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "统计审计日志数量失败", slog.Any("error", err))
+			return nil, nil, errors.New("查询审计日志失败")
+		}
+		total = &count
+	}
+
+	return query.MapPage(page, (*model.AuditLog).ToResponse), total, nil
+}
+
+// PurgeExpired 删除超过保留期的审计日志，返回删除数量
+func (s *auditService) PurgeExpired(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-s.auditConfig.GetRetention())
+	var purged int64
+	for {
+		n, err := s.auditRepo.DeleteBefore(ctx, before, auditPurgeBatchSize)
+		purged += n
+		if err != nil || n < auditPurgeBatchSize {
+			return purged, err
+		}
+	}
+}
+
+// diffChanges 比较变更前后对象的 JSON 字段，返回有变化的字段
+func diffChanges(before, after any) (map[string]*model.AuditChange, error) {
+	oldFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]*model.AuditChange)
+	for name, value := range oldFields {
+		if !reflect.DeepEqual(value, newFields[name]) {
+			changes[name] = &model.AuditChange{Old: value, New: newFields[name]}
+		}
+	}
+	for name, value := range newFields {
+		if _, ok := oldFields[name]; !ok && value != nil {
+			changes[name] = &model.AuditChange{New: value}
+		}
+	}
+	for name := range auditIgnoredFields {
+		delete(changes, name)
+	}
+	return changes, nil
+}
+
+// toFields 将对象转换为 JSON 字段表，nil 返回空表
+func toFields(v any) (map[string]any, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// truncate 按字节截断字符串，去掉被截断的不完整字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return strings.ToValidUTF8(s[:n], "")
+}
@@ -0,0 +1,418 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/database"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	// exportBatchSize 每批处理的导出任务数量
+	exportBatchSize = 20
+	// exportListLimit 导出任务列表返回的最近任务数量
+	exportListLimit = 10
+	// exportStaleAfter 生成中的任务超过该时间未完成时视为中断，重新生成
+	exportStaleAfter = 30 * time.Minute
+	// exportErrorMaxLen 保存的失败原因最大长度
+	exportErrorMaxLen = 500
+)
+
+// DataExportService 个人数据导出服务接口
+type DataExportService interface {
+	RequestExport(ctx context.Context, userID int) (*model.DataExportResponse, error)
+	ListExports(ctx context.Context, userID int) ([]*model.DataExportResponse, error)
+	GetExport(ctx context.Context, userID, id int) (*model.DataExportResponse, error)
+	OpenDownload(ctx context.Context, key string, query url.Values) (io.ReadCloser, string, error)
+	ProcessPending(ctx context.Context) (int, error)
+	CleanupExpired(ctx context.Context) (int, error)
+}
+
+// dataExportService 个人数据导出服务实现
+type dataExportService struct {
+	exportRepo      repository.DataExportRepository
+	userRepo        repository.UserRepository
+	fieldRepo       repository.ProfileFieldRepository
+	mediaRepo       repository.MediaRepository
+	phoneChangeRepo repository.PhoneChangeRepository
+	auditLogRepo    repository.AuditLogRepository
+	storage         storage.Storage
+	exportStorage   storage.Private
+	exportConfig    *config.DataExportConfig
+	auditor         Auditor
+}
+
+// NewDataExportService 创建个人数据导出服务实例
+func NewDataExportService(
+	exportRepo repository.DataExportRepository,
+	userRepo repository.UserRepository,
+	fieldRepo repository.ProfileFieldRepository,
+	mediaRepo repository.MediaRepository,
+	phoneChangeRepo repository.PhoneChangeRepository,
+	auditLogRepo repository.AuditLogRepository,
+	store storage.Storage,
+	exportStore storage.Private,
+	exportConfig *config.DataExportConfig,
+	auditor Auditor,
+) DataExportService {
+	return &dataExportService{
+		exportRepo:      exportRepo,
+		userRepo:        userRepo,
+		fieldRepo:       fieldRepo,
+		mediaRepo:       mediaRepo,
+		phoneChangeRepo: phoneChangeRepo,
+		auditLogRepo:    auditLogRepo,
+		storage:         store,
+		exportStorage:   exportStore,
+		exportConfig:    exportConfig,
+		auditor:         auditor,
+	}
+}
+
+// RequestExport 创建数据导出任务，由 data-export 任务异步生成
+// 同一用户同时只能有一个未完成的任务，由数据库唯一约束保证，并发申请时只有一个成功
+func (s *dataExportService) RequestExport(ctx context.Context, userID int) (*model.DataExportResponse, error) {
+	export := &model.DataExport{UserID: userID, Status: model.DataExportStatusPending}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, appErrors.Conflict("已有正在生成的导出任务，请等待完成后再试")
+		}
+		return nil, fmt.Errorf("创建导出任务失败: %w", err)
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "创建数据导出任务", slog.Int("user_id", userID), slog.Int("export_id", export.ID))
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserDataExport,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userID,
+		ActorID:    userID,
+	})
+	return export.ToResponse(), nil
+}
+
+// ListExports 获取当前用户最近的数据导出任务
+func (s *dataExportService) ListExports(ctx context.Context, userID int) ([]*model.DataExportResponse, error) {
+	exports, err := s.exportRepo.ListRecentByUserID(ctx, userID, exportListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("查询导出任务失败: %w", err)
+	}
+
+	items := make([]*model.DataExportResponse, 0, len(exports))
+	for _, export := range exports {
+		resp, err := s.toResponse(ctx, export)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp)
+	}
+	return items, nil
+}
+
+// GetExport 获取当前用户的数据导出任务，已生成时返回限时下载地址
+func (s *dataExportService) GetExport(ctx context.Context, userID, id int) (*model.DataExportResponse, error) {
+	export, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NotFound("导出任务不存在")
+		}
+		return nil, fmt.Errorf("查询导出任务失败: %w", err)
+	}
+	// 不区分不存在和无权访问，避免泄露其他用户的任务
+	if export.UserID != userID {
+		return nil, appErrors.NotFound("导出任务不存在")
+	}
+	return s.toResponse(ctx, export)
+}
+
+// OpenDownload 校验本地存储的预签名下载地址并打开文件，返回下载时保存的文件名
+// 仅本地存储可用，S3 存储的下载地址直接指向存储桶
+func (s *dataExportService) OpenDownload(ctx context.Context, key string, query url.Values) (io.ReadCloser, string, error) {
+	local, ok := s.exportStorage.(*storage.Local)
+	if !ok || !strings.HasPrefix(key, model.DataExportKeyPrefix) {
+		return nil, "", appErrors.NotFound("下载地址不存在")
+	}
+	filename, err := local.VerifyPresignedGet(key, query)
+	if err != nil {
+		return nil, "", appErrors.Forbidden("下载地址无效或已过期")
+	}
+
+	rc, err := local.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, "", appErrors.NotFound("文件不存在或已过期")
+		}
+		return nil, "", fmt.Errorf("读取文件失败: %w", err)
+	}
+	return rc, filename, nil
+}
+
+// ProcessPending 生成等待中的导出任务，返回生成成功的数量
+// 任务通过条件更新领取，多个副本同时执行时每个任务只会被生成一次
+func (s *dataExportService) ProcessPending(ctx context.Context) (int, error) {
+	log := logger.FromContext(ctx)
+	staleBefore := time.Now().Add(-exportStaleAfter)
+
+	processed := 0
+	for {
+		exports, err := s.exportRepo.ListPending(ctx, staleBefore, exportBatchSize)
+		if err != nil {
+			return processed, fmt.Errorf("查询导出任务失败: %w", err)
+		}
+
+		claimed := 0
+		for _, export := range exports {
+			ok, err := s.exportRepo.Claim(ctx, export.ID, staleBefore)
+			if err != nil {
+				return processed, fmt.Errorf("领取导出任务失败: %w", err)
+			}
+			if !ok {
+				continue
+			}
+			claimed++
+
+			if err := s.process(ctx, export); err != nil {
+				log.ErrorContext(ctx, "生成数据导出失败", slog.Int("export_id", export.ID), slog.Any("error", err))
+				s.fail(ctx, export, err)
+				continue
+			}
+			processed++
+			log.InfoContext(ctx, "数据导出已生成", slog.Int("export_id", export.ID), slog.Int64("size", export.Size))
+		}
+
+		if len(exports) < exportBatchSize || claimed == 0 {
+			return processed, nil
+		}
+	}
+}
+
+// CleanupExpired 删除超过保留时间的导出文件，返回清理数量
+func (s *dataExportService) CleanupExpired(ctx context.Context) (int, error) {
+	log := logger.FromContext(ctx)
+	now := time.Now()
+
+	cleaned, afterID := 0, 0
+	for {
+		exports, err := s.exportRepo.ListExpired(ctx, now, afterID, exportBatchSize)
+		if err != nil {
+			return cleaned, fmt.Errorf("查询导出任务失败: %w", err)
+		}
+		for _, export := range exports {
+			afterID = export.ID
+			if err := s.exportStorage.Delete(ctx, export.StorageKey); err != nil {
+				log.WarnContext(ctx, "删除导出文件失败", slog.Int("export_id", export.ID), slog.Any("error", err))
+				continue
+			}
+			export.Status = model.DataExportStatusExpired
+			if err := s.exportRepo.Update(ctx, export); err != nil {
+				log.WarnContext(ctx, "更新导出任务失败", slog.Int("export_id", export.ID), slog.Any("error", err))
+				continue
+			}
+			cleaned++
+		}
+		if len(exports) < exportBatchSize {
+			return cleaned, nil
+		}
+	}
+}
+
+// process 收集用户数据，打包为 ZIP 文件保存到对象存储
+func (s *dataExportService) process(ctx context.Context, export *model.DataExport) error {
+	data, err := s.build(ctx, export.UserID)
+	if err != nil {
+		return err
+	}
+
+	key := newExportKey(export.UserID)
+	if err := s.exportStorage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/zip"); err != nil {
+		return fmt.Errorf("保存导出文件失败: %w", err)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.exportConfig.GetRetention())
+	export.Status = model.DataExportStatusReady
+	export.StorageKey = key
+	export.Size = int64(len(data))
+	export.Error = ""
+	export.CompletedAt = &now
+	export.ExpiresAt = &expiresAt
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		if delErr := s.exportStorage.Delete(ctx, key); delErr != nil {
+			logger.FromContext(ctx).WarnContext(ctx, "删除导出文件失败", slog.String("key", key), slog.Any("error", delErr))
+		}
+		return fmt.Errorf("更新导出任务失败: %w", err)
+	}
+	return nil
+}
+
+// build 生成 ZIP 文件内容，每类数据一个 JSON 文件
+// 媒体文件只导出记录和访问地址，不打包文件本身；登录记录和本人的账号操作记录来自审计日志（保留期内），不包含其他操作人的记录
+func (s *dataExportService) build(ctx context.Context, userID int) ([]byte, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("用户不存在")
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+	fields, err := s.fieldRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询资料字段失败: %w", err)
+	}
+	items, err := s.mediaRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询媒体文件失败: %w", err)
+	}
+	changes, err := s.phoneChangeRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询手机号变更记录失败: %w", err)
+	}
+	logs, err := s.auditLogRepo.ListSelfActions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询登录记录失败: %w", err)
+	}
+
+	media := make([]*exportMedia, 0, len(items))
+	for _, m := range items {
+		em := &exportMedia{MediaObject: m, URL: s.storage.URL(m.StorageKey)}
+		if m.ThumbnailKey != "" {
+			em.ThumbnailURL = s.storage.URL(m.ThumbnailKey)
+		}
+		media = append(media, em)
+	}
+	history := make([]*exportActivity, 0, len(logs))
+	for _, l := range logs {
+		resp := l.ToResponse()
+		history = append(history, &exportActivity{
+			Action:     resp.Action,
+			Changes:    resp.Changes,
+			IP:         resp.IP,
+			UserAgent:  resp.UserAgent,
+			CreateTime: resp.CreateTime,
+		})
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"user.json", user.ToResponse()},
+		{"profile_fields.json", fields},
+		{"media.json", media},
+		{"phone_changes.json", changes},
+		{"login_history.json", history},
+	}
+
+	manifest := &exportManifest{UserID: userID, GeneratedAt: time.Now()}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.name)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := writeJSON(zw, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		if err := writeJSON(zw, f.name, f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("生成 ZIP 文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fail 将任务标记为生成失败
+func (s *dataExportService) fail(ctx context.Context, export *model.DataExport, cause error) {
+	export.Status = model.DataExportStatusFailed
+	export.Error = truncate(cause.Error(), exportErrorMaxLen)
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "更新导出任务失败", slog.Int("export_id", export.ID), slog.Any("error", err))
+	}
+}
+
+// toResponse 转换为响应结构，已生成的任务附带限时下载地址，有效期不超过文件保留时间
+func (s *dataExportService) toResponse(ctx context.Context, export *model.DataExport) (*model.DataExportResponse, error) {
+	resp := export.ToResponse()
+	if export.Status != model.DataExportStatusReady || export.ExpiresAt == nil {
+		return resp, nil
+	}
+
+	ttl := min(s.exportConfig.GetLinkTTL(), time.Until(*export.ExpiresAt))
+	if ttl <= 0 {
+		resp.Status = model.DataExportStatusExpired
+		return resp, nil
+	}
+	filename := fmt.Sprintf("dove-export-%d-%s.zip", export.UserID, export.CreateTime.Format("20060102"))
+	u, err := s.exportStorage.PresignGet(ctx, export.StorageKey, filename, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("生成下载地址失败: %w", err)
+	}
+	expiresAt := time.Now().Add(ttl)
+	resp.DownloadURL = u
+	resp.DownloadExpiry = &expiresAt
+	return resp, nil
+}
+
+// exportManifest 导出文件清单
+type exportManifest struct {
+	UserID      int       `json:"user_id"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Files       []string  `json:"files"`
+}
+
+// exportMedia 导出的媒体文件记录，附带访问地址
+type exportMedia struct {
+	*model.MediaObject
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// exportActivity 导出的本人登录和账号操作记录
+// 只包含本人作为操作人的记录，不导出管理员等其他操作人的身份、IP 和设备信息
+type exportActivity struct {
+	Action     string                        `json:"action"`
+	Changes    map[string]*model.AuditChange `json:"changes,omitempty"`
+	IP         string                        `json:"ip"`
+	UserAgent  string                        `json:"user_agent"`
+	CreateTime time.Time                     `json:"create_time"`
+}
+
+// writeJSON 将数据以缩进格式的 JSON 写入 ZIP 文件
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("生成 ZIP 文件失败: %w", err)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("写入 %s 失败: %w", name, err)
+	}
+	return nil
+}
+
+// newExportKey 生成导出文件的对象存储路径，随机文件名避免被猜测
+func newExportKey(userID int) string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s%d/%s.zip", model.DataExportKeyPrefix, userID, hex.EncodeToString(b))
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/logger"
+)
+
+// reencryptBatchSize 每批重新加密的行数
+const reencryptBatchSize = 500
+
+// ReencryptResult 单个加密列的重新加密结果
+type ReencryptResult struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Scanned int    `json:"scanned"` // 检查的行数
+	Updated int    `json:"updated"` // 改写的行数
+}
+
+// EncryptionService 敏感字段加密服务接口
+type EncryptionService interface {
+	Reencrypt(ctx context.Context, decrypt bool) ([]*ReencryptResult, error)
+}
+
+// encryptionService 敏感字段加密服务实现
+type encryptionService struct {
+	columnRepo repository.EncryptedColumnRepository
+}
+
+// NewEncryptionService 创建敏感字段加密服务实例
+func NewEncryptionService(columnRepo repository.EncryptedColumnRepository) EncryptionService {
+	return &encryptionService{columnRepo: columnRepo}
+}
+
+// Reencrypt 使用 active 密钥重新加密全部加密列，迁移历史明文数据并补齐盲索引，
+// 再将以本人手机号作为用户名的用户改为随机生成的默认用户名
+// decrypt 为 true 时将全部加密列还原为明文，用于回滚字段加密迁移，不处理用户名；返回已处理的结果，出错时中断
+func (s *encryptionService) Reencrypt(ctx context.Context, decrypt bool) ([]*ReencryptResult, error) {
+	results := make([]*ReencryptResult, 0, len(repository.EncryptedColumns)+1)
+	for _, column := range repository.EncryptedColumns {
+		result := &ReencryptResult{Table: column.Table, Column: column.Column}
+		results = append(results, result)
+		err := s.process(ctx, result, decrypt, func(afterID int) (*repository.ReencryptBatch, error) {
+			return s.columnRepo.Reencrypt(ctx, column, afterID, reencryptBatchSize, decrypt)
+		})
+		if err != nil {
+			return results, err
+		}
+	}
+	if decrypt {
+		return results, nil
+	}
+
+	result := &ReencryptResult{Table: model.User{}.TableName(), Column: "username"}
+	results = append(results, result)
+	err := s.process(ctx, result, decrypt, func(afterID int) (*repository.ReencryptBatch, error) {
+		return s.columnRepo.RenamePhoneUsernames(ctx, afterID, reencryptBatchSize)
+	})
+	return results, err
+}
+
+// process 分批处理单个列直到全部处理完成，结果累加到 result
+func (s *encryptionService) process(ctx context.Context, result *ReencryptResult, decrypt bool, next func(afterID int) (*repository.ReencryptBatch, error)) error {
+	afterID := 0
+	for {
+		batch, err := next(afterID)
+		if batch != nil {
+			result.Scanned += batch.Scanned
+			result.Updated += batch.Updated
+		}
+		if err != nil {
+			return err
+		}
+		if batch.Scanned < reencryptBatchSize {
+			break
+		}
+		afterID = batch.LastID
+	}
+
+	logger.FromContext(ctx).InfoContext(ctx, "加密列处理完成",
+		slog.String("table", result.Table),
+		slog.String("column", result.Column),
+		slog.Int("scanned", result.Scanned),
+		slog.Int("updated", result.Updated),
+		slog.Bool("decrypt", decrypt),
+	)
+	return nil
+}
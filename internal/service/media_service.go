@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/database"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/media"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// gcBatchSize 垃圾回收每批检查的媒体文件数量
+const gcBatchSize = 100
+
+// MediaService 媒体文件服务接口
+type MediaService interface {
+	Upload(ctx context.Context, userID int, purpose string, r io.Reader, size int64) (*model.MediaResponse, error)
+	PresignUpload(ctx context.Context, userID int, req *model.PresignUploadRequest) (*model.PresignUploadResponse, error)
+	ReceiveUpload(ctx context.Context, key, contentType string, query url.Values, r io.Reader) error
+	CompleteUpload(ctx context.Context, userID, id int) (*model.MediaResponse, error)
+	GetMedia(ctx context.Context, userID, id int) (*model.MediaResponse, error)
+	DeleteMedia(ctx context.Context, userID, id int) error
+	CollectGarbage(ctx context.Context) (int, error)
+}
+
+// mediaService 媒体文件服务实现
+type mediaService struct {
+	mediaRepo     repository.MediaRepository
+	userRepo      repository.UserRepository
+	txManager     *database.TxManager
+	storage       storage.Storage
+	storageConfig *config.StorageConfig
+	uploadConfig  *config.UploadConfig
+}
+
+// NewMediaService 创建媒体文件服务实例
+func NewMediaService(
+	mediaRepo repository.MediaRepository,
+	userRepo repository.UserRepository,
+	txManager *database.TxManager,
+	store storage.Storage,
+	storageConfig *config.StorageConfig,
+	uploadConfig *config.UploadConfig,
+) MediaService {
+	return &mediaService{
+		mediaRepo:     mediaRepo,
+		userRepo:      userRepo,
+		txManager:     txManager,
+		storage:       store,
+		storageConfig: storageConfig,
+		uploadConfig:  uploadConfig,
+	}
+}
+
+// Upload 直接上传文件（multipart）
+// 文件类型按内容识别，图片会生成缩略图；用途为头像时上传完成后设置为用户头像
+func (s *mediaService) Upload(ctx context.Context, userID int, purpose string, r io.Reader, size int64) (*model.MediaResponse, error) {
+	resp, err := s.upload(ctx, userID, purpose, r, size)
+	s.recordUpload(purpose, err)
+	return resp, err
+}
+
+// upload 直接上传文件
+func (s *mediaService) upload(ctx context.Context, userID int, purpose string, r io.Reader, size int64) (*model.MediaResponse, error) {
+	head, err := readHead(r)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	contentType, err := s.detectType(purpose, head)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSize(contentType, size); err != nil {
+		return nil, err
+	}
+
+	m := &model.MediaObject{
+		UserID:      userID,
+		Purpose:     purpose,
+		StorageKey:  newStorageKey(purpose, userID, media.Extension(contentType)),
+		ContentType: contentType,
+		Size:        size,
+		Status:      model.MediaStatusReady,
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if media.IsImage(contentType) {
+		// 图片需要完整读入以生成缩略图，大小上限较小
+		data, err := media.ReadAll(body, s.uploadConfig.MaxImageSize)
+		if err != nil {
+			return nil, s.tooLarge(contentType)
+		}
+		m.Size = int64(len(data))
+		if err := s.storage.Put(ctx, m.StorageKey, bytes.NewReader(data), m.Size, contentType); err != nil {
+			return nil, fmt.Errorf("保存文件失败: %w", err)
+		}
+		if err := s.makeThumbnail(ctx, m, data); err != nil {
+			s.removeObjects(ctx, m)
+			return nil, err
+		}
+	} else {
+		counter := &countingReader{r: io.LimitReader(body, s.uploadConfig.MaxVideoSize+1)}
+		if err := s.storage.Put(ctx, m.StorageKey, counter, -1, contentType); err != nil {
+			return nil, fmt.Errorf("保存文件失败: %w", err)
+		}
+		if counter.n > s.uploadConfig.MaxVideoSize {
+			s.removeObjects(ctx, m)
+			return nil, s.tooLarge(contentType)
+		}
+		m.Size = counter.n
+	}
+
+	if err := s.save(ctx, m); err != nil {
+		s.removeObjects(ctx, m)
+		return nil, err
+	}
+	return s.toResponse(m), nil
+}
+
+// PresignUpload 生成预签名上传地址
+// 客户端按声明的类型和大小直接上传到对象存储，再调用 CompleteUpload 校验
+func (s *mediaService) PresignUpload(ctx context.Context, userID int, req *model.PresignUploadRequest) (*model.PresignUploadResponse, error) {
+	contentType, _, _ := strings.Cut(req.ContentType, ";")
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+	if err := s.checkType(req.Purpose, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.checkSize(contentType, req.Size); err != nil {
+		return nil, err
+	}
+
+	m := &model.MediaObject{
+		UserID:      userID,
+		Purpose:     req.Purpose,
+		StorageKey:  newStorageKey(req.Purpose, userID, media.Extension(contentType)),
+		ContentType: contentType,
+		Size:        req.Size,
+		Status:      model.MediaStatusPending,
+	}
+	presigned, err := s.storage.PresignPut(ctx, m.StorageKey, contentType, s.storageConfig.GetPresignTTL())
+	if err != nil {
+		return nil, fmt.Errorf("生成上传地址失败: %w", err)
+	}
+	if err := s.mediaRepo.Create(ctx, m); err != nil {
+		return nil, fmt.Errorf("创建媒体文件记录失败: %w", err)
+	}
+
+	headers := make(map[string]string, len(presigned.Headers))
+	for name := range presigned.Headers {
+		headers[name] = presigned.Headers.Get(name)
+	}
+	return &model.PresignUploadResponse{
+		Media: s.toResponse(m),
+		Upload: &model.UploadTarget{
+			Method:    presigned.Method,
+			URL:       presigned.URL,
+			Headers:   headers,
+			ExpiresAt: presigned.ExpiresAt,
+		},
+	}, nil
+}
+
+// ReceiveUpload 接收本地存储的预签名上传
+// 仅本地存储可用，S3 存储由客户端直接上传到存储桶
+func (s *mediaService) ReceiveUpload(ctx context.Context, key, contentType string, query url.Values, r io.Reader) error {
+	local, ok := s.storage.(*storage.Local)
+	if !ok {
+		return appErrors.NotFound("上传地址不存在")
+	}
+	if err := local.VerifyPresigned(key, contentType, query); err != nil {
+		return appErrors.Forbidden("上传地址无效或已过期")
+	}
+
+	m, err := s.mediaRepo.GetByStorageKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return appErrors.NotFound("上传地址不存在")
+		}
+		return fmt.Errorf("查询媒体文件失败: %w", err)
+	}
+	if m.Status != model.MediaStatusPending {
+		return appErrors.Conflict("文件已上传")
+	}
+
+	// 只接收声明的大小，超出部分在完成上传时按大小不一致拒绝
+	if err := local.Put(ctx, key, io.LimitReader(r, m.Size+1), -1, m.ContentType); err != nil {
+		return fmt.Errorf("保存文件失败: %w", err)
+	}
+	return nil
+}
+
+// CompleteUpload 完成预签名上传
+// 校验对象已上传、大小和内容类型与声明一致，图片生成缩略图；校验失败时删除已上传的对象
+func (s *mediaService) CompleteUpload(ctx context.Context, userID, id int) (*model.MediaResponse, error) {
+	m, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.Status != model.MediaStatusPending {
+		return s.toResponse(m), nil
+	}
+
+	resp, err := s.completeUpload(ctx, m)
+	s.recordUpload(m.Purpose, err)
+	return resp, err
+}
+
+// completeUpload 校验预签名上传的对象
+func (s *mediaService) completeUpload(ctx context.Context, m *model.MediaObject) (*model.MediaResponse, error) {
+	info, err := s.storage.Stat(ctx, m.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, appErrors.BadRequest("文件尚未上传")
+		}
+		return nil, fmt.Errorf("查询文件失败: %w", err)
+	}
+
+	reject := func(appErr *appErrors.AppError) (*model.MediaResponse, error) {
+		s.removeObjects(ctx, m)
+		if err := s.mediaRepo.Delete(ctx, m.ID); err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "删除媒体文件记录失败", slog.Int("media_id", m.ID), slog.Any("error", err))
+		}
+		return nil, appErr
+	}
+
+	if info.Size != m.Size {
+		return reject(appErrors.New(http.StatusBadRequest, appErrors.CodeMediaInvalid, "文件大小与声明不一致").
+			WithDetail(fmt.Sprintf("声明 %d 字节，实际 %d 字节", m.Size, info.Size)))
+	}
+	head, err := storage.ReadHead(ctx, s.storage, m.StorageKey, media.SniffLen)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %w", err)
+	}
+	contentType, err := media.DetectContentType(head)
+	if err != nil || contentType != m.ContentType {
+		return reject(appErrors.New(http.StatusBadRequest, appErrors.CodeMediaInvalid, "文件内容与声明的类型不一致").
+			WithDetail(fmt.Sprintf("声明 %s", m.ContentType)))
+	}
+
+	if media.IsImage(m.ContentType) {
+		rc, err := s.storage.Get(ctx, m.StorageKey)
+		if err != nil {
+			return nil, fmt.Errorf("读取文件失败: %w", err)
+		}
+		data, err := media.ReadAll(rc, s.uploadConfig.MaxImageSize)
+		rc.Close()
+		if err != nil {
+			return reject(s.tooLarge(m.ContentType))
+		}
+		if err := s.makeThumbnail(ctx, m, data); err != nil {
+			var appErr *appErrors.AppError
+			if errors.As(err, &appErr) {
+				return reject(appErr)
+			}
+			return nil, err
+		}
+	}
+
+	m.Status = model.MediaStatusReady
+	if err := s.save(ctx, m); err != nil {
+		return nil, err
+	}
+	return s.toResponse(m), nil
+}
+
+// GetMedia 获取当前用户的媒体文件
+func (s *mediaService) GetMedia(ctx context.Context, userID, id int) (*model.MediaResponse, error) {
+	m, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(m), nil
+}
+
+// DeleteMedia 删除当前用户的媒体文件，正在被头像或资料字段引用的文件不能删除
+func (s *mediaService) DeleteMedia(ctx context.Context, userID, id int) error {
+	m, err := s.getOwned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	referenced, err := s.mediaRepo.IsReferenced(ctx, m, s.urls(m)...)
+	if err != nil {
+		return fmt.Errorf("查询文件引用失败: %w", err)
+	}
+	if referenced {
+		return appErrors.Conflict("文件正在使用中，不能删除")
+	}
+
+	return s.remove(ctx, m)
+}
+
+// CollectGarbage 清理孤立的媒体文件，返回清理数量
+// 超过 upload.orphan_ttl 仍未完成上传，或已上传但未被任何头像、资料字段引用的文件会被删除
+func (s *mediaService) CollectGarbage(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.uploadConfig.GetOrphanTTL())
+	log := logger.FromContext(ctx)
+
+	collected, afterID := 0, 0
+	for {
+		items, err := s.mediaRepo.ListStale(ctx, before, afterID, gcBatchSize)
+		if err != nil {
+			return collected, fmt.Errorf("查询媒体文件失败: %w", err)
+		}
+		for _, m := range items {
+			afterID = m.ID
+			if m.Status == model.MediaStatusReady {
+				referenced, err := s.mediaRepo.IsReferenced(ctx, m, s.urls(m)...)
+				if err != nil {
+					return collected, fmt.Errorf("查询文件引用失败: %w", err)
+				}
+				if referenced {
+					continue
+				}
+			}
+			if err := s.remove(ctx, m); err != nil {
+				log.WarnContext(ctx, "清理媒体文件失败", slog.Int("media_id", m.ID), slog.Any("error", err))
+				continue
+			}
+			collected++
+			metrics.MediaCollected.Inc()
+		}
+		if len(items) < gcBatchSize {
+			return collected, nil
+		}
+	}
+}
+
+// save 保存媒体文件记录，用途为头像时同时更新用户头像
+func (s *mediaService) save(ctx context.Context, m *model.MediaObject) error {
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if m.ID == 0 {
+			err = s.mediaRepo.Create(ctx, m)
+		} else {
+			err = s.mediaRepo.Update(ctx, m)
+		}
+		if err != nil {
+			return fmt.Errorf("保存媒体文件记录失败: %w", err)
+		}
+
+		if m.Purpose != model.MediaPurposeAvatar {
+			return nil
+		}
+		user, err := s.userRepo.GetByID(ctx, m.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return appErrors.NotFound("用户不存在")
+			}
+			return fmt.Errorf("查询用户失败: %w", err)
+		}
+		user.Avatar = s.storage.URL(m.StorageKey)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return fmt.Errorf("更新用户头像失败: %w", err)
+		}
+		return nil
+	})
+}
+
+// remove 删除媒体文件的对象和记录
+func (s *mediaService) remove(ctx context.Context, m *model.MediaObject) error {
+	for _, key := range []string{m.StorageKey, m.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			return fmt.Errorf("删除文件失败: %w", err)
+		}
+	}
+	if err := s.mediaRepo.Delete(ctx, m.ID); err != nil {
+		return fmt.Errorf("删除媒体文件记录失败: %w", err)
+	}
+	return nil
+}
+
+// removeObjects 尽力删除已写入的对象，用于上传失败时清理
+func (s *mediaService) removeObjects(ctx context.Context, m *model.MediaObject) {
+	for _, key := range []string{m.StorageKey, m.ThumbnailKey} {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).WarnContext(ctx, "删除文件失败", slog.String("key", key), slog.Any("error", err))
+		}
+	}
+}
+
+// makeThumbnail 生成并保存缩略图，记录图片尺寸
+// 未启用缩略图时只解析图片尺寸
+func (s *mediaService) makeThumbnail(ctx context.Context, m *model.MediaObject, data []byte) error {
+	invalid := appErrors.New(http.StatusBadRequest, appErrors.CodeMediaInvalid, "图片无法解析")
+
+	if s.uploadConfig.ThumbnailSize <= 0 {
+		width, height, err := media.ImageSize(data)
+		if err != nil {
+			return invalid.WithDetail(err.Error())
+		}
+		m.Width, m.Height = width, height
+		return nil
+	}
+
+	thumb, err := media.MakeThumbnail(data, s.uploadConfig.ThumbnailSize)
+	if err != nil {
+		return invalid.WithDetail(err.Error())
+	}
+	m.Width, m.Height = thumb.Width, thumb.Height
+
+	key := strings.TrimSuffix(m.StorageKey, media.Extension(m.ContentType)) + "_thumb" + media.Extension(thumb.ContentType)
+	if err := s.storage.Put(ctx, key, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+		return fmt.Errorf("保存缩略图失败: %w", err)
+	}
+	m.ThumbnailKey = key
+	return nil
+}
+
+// getOwned 获取当前用户的媒体文件，不属于当前用户时按不存在处理
+func (s *mediaService) getOwned(ctx context.Context, userID, id int) (*model.MediaObject, error) {
+	m, err := s.mediaRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NotFound("媒体文件不存在")
+		}
+		return nil, fmt.Errorf("查询媒体文件失败: %w", err)
+	}
+	if m.UserID != userID {
+		return nil, appErrors.NotFound("媒体文件不存在")
+	}
+	return m, nil
+}
+
+// detectType 按文件内容识别类型并检查用途是否允许
+func (s *mediaService) detectType(purpose string, head []byte) (string, error) {
+	contentType, err := media.DetectContentType(head)
+	if err != nil {
+		return "", appErrors.New(http.StatusUnsupportedMediaType, appErrors.CodeMediaTypeUnsupported, "文件类型不支持").WithDetail(err.Error())
+	}
+	if err := s.checkType(purpose, contentType); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// checkType 检查用途是否允许该文件类型，头像只允许图片
+func (s *mediaService) checkType(purpose, contentType string) error {
+	unsupported := appErrors.New(http.StatusUnsupportedMediaType, appErrors.CodeMediaTypeUnsupported, "文件类型不支持")
+	switch {
+	case purpose != model.MediaPurposeAvatar && purpose != model.MediaPurposeProfileField:
+		return appErrors.BadRequest("文件用途无效").WithDetail("purpose 可选 avatar、profile_field")
+	case media.Extension(contentType) == "":
+		return unsupported.WithDetail("支持 JPEG、PNG、GIF、WebP 图片和 MP4、WebM 视频")
+	case purpose == model.MediaPurposeAvatar && !media.IsImage(contentType):
+		return unsupported.WithDetail("头像只支持图片")
+	}
+	return nil
+}
+
+// checkSize 检查文件大小是否超过上限
+func (s *mediaService) checkSize(contentType string, size int64) error {
+	if size > s.maxSize(contentType) {
+		return s.tooLarge(contentType)
+	}
+	return nil
+}
+
+// maxSize 获取文件类型的大小上限
+func (s *mediaService) maxSize(contentType string) int64 {
+	if media.IsVideo(contentType) {
+		return s.uploadConfig.MaxVideoSize
+	}
+	return s.uploadConfig.MaxImageSize
+}
+
+// tooLarge 创建文件过大错误
+func (s *mediaService) tooLarge(contentType string) *appErrors.AppError {
+	return appErrors.New(http.StatusRequestEntityTooLarge, appErrors.CodeMediaTooLarge, "文件过大").
+		WithDetail(fmt.Sprintf("上限 %d 字节", s.maxSize(contentType)))
+}
+
+// urls 返回媒体文件及缩略图的访问地址
+func (s *mediaService) urls(m *model.MediaObject) []string {
+	urls := []string{s.storage.URL(m.StorageKey)}
+	if m.ThumbnailKey != "" {
+		urls = append(urls, s.storage.URL(m.ThumbnailKey))
+	}
+	return urls
+}
+
+// toResponse 转换为响应格式
+func (s *mediaService) toResponse(m *model.MediaObject) *model.MediaResponse {
+	resp := &model.MediaResponse{
+		ID:          m.ID,
+		Purpose:     m.Purpose,
+		URL:         s.storage.URL(m.StorageKey),
+		ContentType: m.ContentType,
+		Size:        m.Size,
+		Width:       m.Width,
+		Height:      m.Height,
+		Status:      m.Status,
+		CreateTime:  m.CreateTime,
+	}
+	if m.ThumbnailKey != "" {
+		resp.ThumbnailURL = s.storage.URL(m.ThumbnailKey)
+	}
+	return resp
+}
+
+// recordUpload 记录上传指标
+func (s *mediaService) recordUpload(purpose string, err error) {
+	result := metrics.ResultSuccess
+	if err != nil {
+		result = metrics.ResultFailure
+	}
+	metrics.MediaUploads.WithLabelValues(purpose, result).Inc()
+}
+
+// newStorageKey 生成对象存储路径，如 avatar/1/2024/01/9f86d081884c7d65.jpg
+func newStorageKey(purpose string, userID int, ext string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s/%d/%s/%s%s", purpose, userID, time.Now().Format("2006/01"), hex.EncodeToString(b), ext)
+}
+
+// readHead 读取识别文件类型所需的开头字节
+func readHead(r io.Reader) ([]byte, error) {
+	head := make([]byte, media.SniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return head[:n], nil
+}
+
+// countingReader 统计读取字节数
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read 读取并累计字节数
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/query"
+	"gorm.io/gorm"
+)
+
+// ProfileFieldService 资料字段服务接口
+type ProfileFieldService interface {
+	GetUserProfile(ctx context.Context, userID int) (*model.UserProfileResponse, error)
+	ListUserFields(ctx context.Context, userID int, spec *query.Spec, page, pageSize int) ([]*model.ProfileField, int64, error)
+	ListUserFieldsByCursor(ctx context.Context, userID int, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.ProfileField], *int64, error)
+}
+
+// profileFieldService 资料字段服务实现
+type profileFieldService struct {
+	userRepo  repository.UserRepository
+	fieldRepo repository.ProfileFieldRepository
+}
+
+// NewProfileFieldService 创建资料字段服务实例
+func NewProfileFieldService(userRepo repository.UserRepository, fieldRepo repository.ProfileFieldRepository) ProfileFieldService {
+	return &profileFieldService{
+		userRepo:  userRepo,
+		fieldRepo: fieldRepo,
+	}
+}
+
+// GetUserProfile 获取用户资料（用户信息和全部资料字段）
+func (s *profileFieldService) GetUserProfile(ctx context.Context, userID int) (*model.UserProfileResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NotFound("用户不存在")
+		}
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	fields, err := s.fieldRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("查询资料字段失败: %w", err)
+	}
+
+	return &model.UserProfileResponse{
+		User:   user.ToResponse(),
+		Fields: fields,
+	}, nil
+}
+
+// ListUserFields 按查询规格获取用户的资料字段列表（分页）
+func (s *profileFieldService) ListUserFields(ctx context.Context, userID int, spec *query.Spec, page, pageSize int) ([]*model.ProfileField, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+	fields, total, err := s.fieldRepo.List(ctx, userID, spec, offset, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("查询资料字段列表失败: %w", err)
+	}
+	return fields, total, nil
+}
+
+// ListUserFieldsByCursor 按查询规格和游标获取用户的资料字段列表，withTotal 为 true 时同时返回总数
+func (s *profileFieldService) ListUserFieldsByCursor(ctx context.Context, userID int, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.ProfileField], *int64, error) {
+	page, err := s.fieldRepo.ListByCursor(ctx, userID, spec, cursor, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询资料字段列表失败: %w", err)
+	}
+
+	var total *int64
+	if withTotal {
+		count, err := s.fieldRepo.Count(ctx, userID, spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("统计资料字段数量失败: %w", err)
+		}
+		total = &count
+	}
+
+	return page, total, nil
+}
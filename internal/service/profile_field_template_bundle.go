@@ -0,0 +1,483 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/deantook/dove/internal/model"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/logger"
+	"go.yaml.in/yaml/v3"
+)
+
+// 字段模板导入导出文件格式
+const (
+	BundleFormatJSON = "json"
+	BundleFormatYAML = "yaml"
+)
+
+// 字段模板导入变更类型
+const (
+	TemplateChangeCreate     = "create"     // 新建模板
+	TemplateChangeUpdate     = "update"     // 更新模板
+	TemplateChangeRestore    = "restore"    // 恢复已删除的模板
+	TemplateChangeUnchanged  = "unchanged"  // 无变化
+	TemplateChangeDeactivate = "deactivate" // 停用文件中不存在的模板
+)
+
+// ImportTemplatesOptions 字段模板导入选项
+type ImportTemplatesOptions struct {
+	DryRun            bool // 仅生成变更报告，不写入数据库
+	DeactivateMissing bool // 停用文件中不存在的模板
+	CreateOnly        bool // 只新建不存在的模板，已存在的模板保持不变
+}
+
+// TemplateChange 单个字段模板的变更
+type TemplateChange struct {
+	FieldKey      string   `json:"field_key"`
+	Action        string   `json:"action"`
+	ChangedFields []string `json:"changed_fields,omitempty"`
+}
+
+// ImportTemplatesResult 字段模板导入结果
+type ImportTemplatesResult struct {
+	DryRun           bool             `json:"dry_run"`
+	CreatedCount     int              `json:"created_count"`
+	UpdatedCount     int              `json:"updated_count"`
+	RestoredCount    int              `json:"restored_count"`
+	UnchangedCount   int              `json:"unchanged_count"`
+	DeactivatedCount int              `json:"deactivated_count"`
+	Changes          []TemplateChange `json:"changes"`
+	Message          string           `json:"message"`
+}
+
+// ParseBundleFormat 解析导入导出文件格式，支持 json、yaml、yml
+func ParseBundleFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", BundleFormatJSON:
+		return BundleFormatJSON, nil
+	case BundleFormatYAML, "yml":
+		return BundleFormatYAML, nil
+	default:
+		return "", appErrors.BadRequest("不支持的文件格式").WithDetail(format)
+	}
+}
+
+// EncodeTemplateBundle 将字段模板文件编码为指定格式
+func EncodeTemplateBundle(bundle *model.ProfileFieldTemplateBundle, format string) ([]byte, error) {
+	switch format {
+	case BundleFormatYAML:
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(bundle); err != nil {
+			return nil, fmt.Errorf("编码字段模板文件失败: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("编码字段模板文件失败: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		data, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("编码字段模板文件失败: %w", err)
+		}
+		return append(data, '\n'), nil
+	}
+}
+
+// DecodeTemplateBundle 解析字段模板文件，未知字段视为错误
+func DecodeTemplateBundle(data []byte, format string) (*model.ProfileFieldTemplateBundle, error) {
+	var bundle model.ProfileFieldTemplateBundle
+	switch format {
+	case BundleFormatYAML:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&bundle); err != nil {
+			return nil, bundleInvalidError([]string{err.Error()})
+		}
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&bundle); err != nil {
+			return nil, bundleInvalidError([]string{err.Error()})
+		}
+	}
+	return &bundle, nil
+}
+
+// ExportTemplates 导出字段模板
+func (s *profileFieldTemplateService) ExportTemplates(ctx context.Context, category string, fieldType string, isActive *bool) (*model.ProfileFieldTemplateBundle, error) {
+	templates, err := s.templateRepo.ListAll(ctx, category, fieldType, isActive)
+	if err != nil {
+		return nil, fmt.Errorf("查询字段模板列表失败: %w", err)
+	}
+
+	now := time.Now()
+	bundle := &model.ProfileFieldTemplateBundle{
+		Version:    model.ProfileFieldTemplateBundleVersion,
+		ExportedAt: &now,
+		Templates:  make([]model.ProfileFieldTemplateSpec, 0, len(templates)),
+	}
+	for _, template := range templates {
+		spec, err := templateToSpec(template)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Templates = append(bundle.Templates, *spec)
+	}
+
+	return bundle, nil
+}
+
+// ImportTemplates 导入字段模板
+// 先校验整个文件，再按 field_key 新建、更新或恢复模板；DryRun 时只返回变更报告
+// 全部变更在同一事务中执行，任一模板失败时整批回滚
+func (s *profileFieldTemplateService) ImportTemplates(ctx context.Context, bundle *model.ProfileFieldTemplateBundle, opts ImportTemplatesOptions) (*ImportTemplatesResult, error) {
+	if problems := validateTemplateBundle(bundle); len(problems) > 0 {
+		return nil, bundleInvalidError(problems)
+	}
+
+	if opts.DryRun {
+		return s.importTemplates(ctx, bundle, opts)
+	}
+
+	var result *ImportTemplatesResult
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.importTemplates(ctx, bundle, opts)
+		if err != nil {
+			return err
+		}
+
+		s.auditor.Record(ctx, &AuditEvent{
+			Action:     model.AuditActionTemplateImport,
+			TargetType: model.AuditTargetTemplate,
+			After: map[string]any{
+				"created":     result.CreatedCount,
+				"updated":     result.UpdatedCount,
+				"restored":    result.RestoredCount,
+				"deactivated": result.DeactivatedCount,
+				"changes":     result.Changes,
+			},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).InfoContext(ctx, "字段模板导入完成",
+		slog.Int("created", result.CreatedCount),
+		slog.Int("updated", result.UpdatedCount),
+		slog.Int("restored", result.RestoredCount),
+		slog.Int("deactivated", result.DeactivatedCount),
+	)
+	return result, nil
+}
+
+// importTemplates 计算并应用导入变更
+func (s *profileFieldTemplateService) importTemplates(ctx context.Context, bundle *model.ProfileFieldTemplateBundle, opts ImportTemplatesOptions) (*ImportTemplatesResult, error) {
+	existing, err := s.templateRepo.ListWithDeleted(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询字段模板列表失败: %w", err)
+	}
+	existingByKey := make(map[string]*model.ProfileFieldTemplate, len(existing))
+	for _, template := range existing {
+		existingByKey[template.FieldKey] = template
+	}
+
+	result := &ImportTemplatesResult{
+		DryRun:  opts.DryRun,
+		Changes: make([]TemplateChange, 0, len(bundle.Templates)),
+	}
+
+	inFile := make(map[string]bool, len(bundle.Templates))
+	for i := range bundle.Templates {
+		spec := &bundle.Templates[i]
+		inFile[spec.FieldKey] = true
+		desired := specToTemplate(spec)
+
+		current, ok := existingByKey[spec.FieldKey]
+		switch {
+		case !ok:
+			if !opts.DryRun {
+				if err := s.templateRepo.Create(ctx, desired); err != nil {
+					return nil, fmt.Errorf("创建字段模板 %s 失败: %w", spec.FieldKey, err)
+				}
+			}
+			result.CreatedCount++
+			result.Changes = append(result.Changes, TemplateChange{FieldKey: spec.FieldKey, Action: TemplateChangeCreate})
+
+		case opts.CreateOnly:
+			result.UnchangedCount++
+			result.Changes = append(result.Changes, TemplateChange{FieldKey: spec.FieldKey, Action: TemplateChangeUnchanged})
+
+		case current.DeletedAt.Valid:
+			changed := diffTemplate(current, desired)
+			if !opts.DryRun {
+				if err := s.templateRepo.Restore(ctx, current.ID); err != nil {
+					return nil, fmt.Errorf("恢复字段模板 %s 失败: %w", spec.FieldKey, err)
+				}
+				desired.ID = current.ID
+				desired.CreateTime = current.CreateTime
+				if err := s.templateRepo.Update(ctx, desired); err != nil {
+					return nil, fmt.Errorf("更新字段模板 %s 失败: %w", spec.FieldKey, err)
+				}
+			}
+			result.RestoredCount++
+			result.Changes = append(result.Changes, TemplateChange{FieldKey: spec.FieldKey, Action: TemplateChangeRestore, ChangedFields: changed})
+
+		default:
+			changed := diffTemplate(current, desired)
+			if len(changed) == 0 {
+				result.UnchangedCount++
+				result.Changes = append(result.Changes, TemplateChange{FieldKey: spec.FieldKey, Action: TemplateChangeUnchanged})
+				continue
+			}
+			if !opts.DryRun {
+				desired.ID = current.ID
+				desired.CreateTime = current.CreateTime
+				if err := s.templateRepo.Update(ctx, desired); err != nil {
+					return nil, fmt.Errorf("更新字段模板 %s 失败: %w", spec.FieldKey, err)
+				}
+			}
+			result.UpdatedCount++
+			result.Changes = append(result.Changes, TemplateChange{FieldKey: spec.FieldKey, Action: TemplateChangeUpdate, ChangedFields: changed})
+		}
+	}
+
+	// 停用文件中不存在的模板
+	if opts.DeactivateMissing {
+		missing := make([]*model.ProfileFieldTemplate, 0)
+		for _, template := range existing {
+			if !inFile[template.FieldKey] && !template.DeletedAt.Valid && template.IsActive {
+				missing = append(missing, template)
+			}
+		}
+		sort.Slice(missing, func(i, j int) bool { return missing[i].FieldKey < missing[j].FieldKey })
+
+		for _, template := range missing {
+			if !opts.DryRun {
+				template.IsActive = false
+				if err := s.templateRepo.Update(ctx, template); err != nil {
+					return nil, fmt.Errorf("停用字段模板 %s 失败: %w", template.FieldKey, err)
+				}
+			}
+			result.DeactivatedCount++
+			result.Changes = append(result.Changes, TemplateChange{FieldKey: template.FieldKey, Action: TemplateChangeDeactivate, ChangedFields: []string{"is_active"}})
+		}
+	}
+
+	summary := fmt.Sprintf("新建 %d 个，更新 %d 个，恢复 %d 个，未变化 %d 个，停用 %d 个",
+		result.CreatedCount, result.UpdatedCount, result.RestoredCount, result.UnchangedCount, result.DeactivatedCount)
+	if opts.DryRun {
+		result.Message = "预览：" + summary
+	} else {
+		result.Message = "导入完成：" + summary
+	}
+
+	return result, nil
+}
+
+// bundleInvalidError 构造导入文件校验失败错误
+func bundleInvalidError(problems []string) error {
+	return appErrors.New(http.StatusBadRequest, appErrors.CodeTemplateBundleInvalid, "字段模板文件校验失败").
+		WithDetail(strings.Join(problems, "; "))
+}
+
+// validateTemplateBundle 校验整个导入文件，返回全部问题
+func validateTemplateBundle(bundle *model.ProfileFieldTemplateBundle) []string {
+	var problems []string
+
+	if bundle.Version < 1 || bundle.Version > model.ProfileFieldTemplateBundleVersion {
+		problems = append(problems, fmt.Sprintf("不支持的文件版本 %d（当前版本 %d）", bundle.Version, model.ProfileFieldTemplateBundleVersion))
+	}
+
+	seen := make(map[string]int, len(bundle.Templates))
+	for i := range bundle.Templates {
+		spec := &bundle.Templates[i]
+		prefix := fmt.Sprintf("templates[%d]", i)
+		if spec.FieldKey != "" {
+			prefix = fmt.Sprintf("templates[%d](%s)", i, spec.FieldKey)
+		}
+		addProblem := func(format string, args ...interface{}) {
+			problems = append(problems, prefix+": "+fmt.Sprintf(format, args...))
+		}
+
+		switch {
+		case spec.FieldKey == "":
+			addProblem("field_key 不能为空")
+		case utf8.RuneCountInString(spec.FieldKey) > 100:
+			addProblem("field_key 长度不能超过 100")
+		default:
+			if first, ok := seen[spec.FieldKey]; ok {
+				addProblem("field_key 与 templates[%d] 重复", first)
+			} else {
+				seen[spec.FieldKey] = i
+			}
+		}
+
+		if spec.FieldName == "" {
+			addProblem("field_name 不能为空")
+		} else if utf8.RuneCountInString(spec.FieldName) > 100 {
+			addProblem("field_name 长度不能超过 100")
+		}
+		if spec.FieldType == "" {
+			addProblem("field_type 不能为空")
+		} else if utf8.RuneCountInString(spec.FieldType) > 50 {
+			addProblem("field_type 长度不能超过 50")
+		}
+		if utf8.RuneCountInString(spec.Category) > 50 {
+			addProblem("category 长度不能超过 50")
+		}
+		if utf8.RuneCountInString(spec.Icon) > 500 {
+			addProblem("icon 长度不能超过 500")
+		}
+		if utf8.RuneCountInString(spec.Description) > 500 {
+			addProblem("description 长度不能超过 500")
+		}
+
+		for name, value := range map[string]map[string]interface{}{
+			"options":              spec.Options,
+			"validation":           spec.Validation,
+			"default_unlock_rules": spec.DefaultUnlockRules,
+		} {
+			if _, err := json.Marshal(value); err != nil {
+				addProblem("%s 无法转换为 JSON: %v", name, err)
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems
+}
+
+// templateToSpec 将字段模板转换为文件中的定义
+func templateToSpec(t *model.ProfileFieldTemplate) (*model.ProfileFieldTemplateSpec, error) {
+	isActive := t.IsActive
+	spec := &model.ProfileFieldTemplateSpec{
+		FieldKey:     t.FieldKey,
+		FieldName:    t.FieldName,
+		FieldType:    t.FieldType,
+		Category:     t.Category,
+		IsRequired:   t.IsRequired,
+		IsSearchable: t.IsSearchable,
+		IsPublic:     t.IsPublic,
+		IsSensitive:  t.IsSensitive,
+		IsActive:     &isActive,
+		DisplayOrder: t.DisplayOrder,
+		DefaultValue: t.DefaultValue,
+		Icon:         t.Icon,
+		Description:  t.Description,
+	}
+
+	var err error
+	if spec.Options, err = decodeJSONObject(t.Options); err != nil {
+		return nil, fmt.Errorf("字段模板 %s 的选项配置格式错误: %w", t.FieldKey, err)
+	}
+	if spec.Validation, err = decodeJSONObject(t.Validation); err != nil {
+		return nil, fmt.Errorf("字段模板 %s 的验证规则格式错误: %w", t.FieldKey, err)
+	}
+	if spec.DefaultUnlockRules, err = decodeJSONObject(t.DefaultUnlockRules); err != nil {
+		return nil, fmt.Errorf("字段模板 %s 的解锁规则格式错误: %w", t.FieldKey, err)
+	}
+
+	return spec, nil
+}
+
+// specToTemplate 将文件中的定义转换为字段模板
+func specToTemplate(spec *model.ProfileFieldTemplateSpec) *model.ProfileFieldTemplate {
+	isActive := true
+	if spec.IsActive != nil {
+		isActive = *spec.IsActive
+	}
+	return &model.ProfileFieldTemplate{
+		FieldKey:           spec.FieldKey,
+		FieldName:          spec.FieldName,
+		FieldType:          spec.FieldType,
+		Category:           spec.Category,
+		IsRequired:         spec.IsRequired,
+		IsSearchable:       spec.IsSearchable,
+		IsPublic:           spec.IsPublic,
+		IsSensitive:        spec.IsSensitive,
+		IsActive:           isActive,
+		DisplayOrder:       spec.DisplayOrder,
+		DefaultValue:       spec.DefaultValue,
+		Options:            encodeJSONObject(spec.Options),
+		Validation:         encodeJSONObject(spec.Validation),
+		Icon:               spec.Icon,
+		Description:        spec.Description,
+		DefaultUnlockRules: encodeJSONObject(spec.DefaultUnlockRules),
+	}
+}
+
+// diffTemplate 比较字段模板，返回发生变化的字段名
+func diffTemplate(current, desired *model.ProfileFieldTemplate) []string {
+	var changed []string
+	compare := func(name string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changed = append(changed, name)
+		}
+	}
+
+	compare("field_name", current.FieldName, desired.FieldName)
+	compare("field_type", current.FieldType, desired.FieldType)
+	compare("category", current.Category, desired.Category)
+	compare("is_required", current.IsRequired, desired.IsRequired)
+	compare("is_searchable", current.IsSearchable, desired.IsSearchable)
+	compare("is_public", current.IsPublic, desired.IsPublic)
+	compare("is_sensitive", current.IsSensitive, desired.IsSensitive)
+	compare("is_active", current.IsActive, desired.IsActive)
+	compare("display_order", current.DisplayOrder, desired.DisplayOrder)
+	compare("default_value", current.DefaultValue, desired.DefaultValue)
+	compare("options", normalizeJSONObject(current.Options), normalizeJSONObject(desired.Options))
+	compare("validation", normalizeJSONObject(current.Validation), normalizeJSONObject(desired.Validation))
+	compare("icon", current.Icon, desired.Icon)
+	compare("description", current.Description, desired.Description)
+	compare("default_unlock_rules", normalizeJSONObject(current.DefaultUnlockRules), normalizeJSONObject(desired.DefaultUnlockRules))
+
+	return changed
+}
+
+// decodeJSONObject 解析 JSON 对象字符串，空字符串返回 nil
+func decodeJSONObject(s string) (map[string]interface{}, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// encodeJSONObject 将对象编码为 JSON 字符串，空对象返回空字符串
+func encodeJSONObject(obj map[string]interface{}) string {
+	if len(obj) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// normalizeJSONObject 规范化 JSON 对象字符串，用于忽略键顺序和空白差异
+func normalizeJSONObject(s string) string {
+	obj, err := decodeJSONObject(s)
+	if err != nil {
+		return s
+	}
+	return encodeJSONObject(obj)
+}
@@ -8,6 +8,11 @@ import (
 
 	"github.com/deantook/dove/internal/model"
 	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -18,10 +23,13 @@ type ProfileFieldTemplateService interface {
 	GetTemplateByFieldKey(ctx context.Context, fieldKey string) (*model.ProfileFieldTemplateResponse, error)
 	UpdateTemplate(ctx context.Context, id int, req *model.UpdateProfileFieldTemplateRequest) (*model.ProfileFieldTemplateResponse, error)
 	DeleteTemplate(ctx context.Context, id int) error
-	ListTemplates(ctx context.Context, category string, fieldType string, isActive *bool, page, pageSize int) ([]*model.ProfileFieldTemplateResponse, int64, error)
+	ListTemplates(ctx context.Context, spec *query.Spec, page, pageSize int) ([]*model.ProfileFieldTemplateResponse, int64, error)
+	ListTemplatesByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.ProfileFieldTemplateResponse], *int64, error)
 	GetTemplatesByCategory(ctx context.Context, category string) ([]*model.ProfileFieldTemplateResponse, error)
 	ApplyTemplateToUser(ctx context.Context, templateID, userID int) (*ApplyTemplateResult, error)
 	ApplyTemplatesToUser(ctx context.Context, templateIDs []int, userID int) (*ApplyTemplatesResult, error)
+	ExportTemplates(ctx context.Context, category string, fieldType string, isActive *bool) (*model.ProfileFieldTemplateBundle, error)
+	ImportTemplates(ctx context.Context, bundle *model.ProfileFieldTemplateBundle, opts ImportTemplatesOptions) (*ImportTemplatesResult, error)
 }
 
 // ApplyTemplateResult 应用模板结果
@@ -45,23 +53,29 @@ type ApplyTemplatesResult struct {
 type profileFieldTemplateService struct {
 	templateRepo repository.ProfileFieldTemplateRepository
 	fieldRepo    repository.ProfileFieldRepository // 需要创建 ProfileFieldRepository
+	txManager    *database.TxManager
+	auditor      Auditor
 }
 
 // NewProfileFieldTemplateService 创建系统资料字段模板服务实例
 func NewProfileFieldTemplateService(
 	templateRepo repository.ProfileFieldTemplateRepository,
 	fieldRepo repository.ProfileFieldRepository,
+	txManager *database.TxManager,
+	auditor Auditor,
 ) ProfileFieldTemplateService {
 	return &profileFieldTemplateService{
 		templateRepo: templateRepo,
 		fieldRepo:    fieldRepo,
+		txManager:    txManager,
+		auditor:      auditor,
 	}
 }
 
 // CreateTemplate 创建字段模板
 func (s *profileFieldTemplateService) CreateTemplate(ctx context.Context, req *model.CreateProfileFieldTemplateRequest) (*model.ProfileFieldTemplateResponse, error) {
 	// 检查字段标识是否已存在
-	if _, err := s.templateRepo.GetByFieldKey(req.FieldKey); err == nil {
+	if _, err := s.templateRepo.GetByFieldKey(ctx, req.FieldKey); err == nil {
 		return nil, errors.New("字段标识已存在")
 	} else if err != gorm.ErrRecordNotFound {
 		return nil, fmt.Errorf("查询字段模板失败: %w", err)
@@ -98,6 +112,7 @@ func (s *profileFieldTemplateService) CreateTemplate(ctx context.Context, req *m
 		IsRequired:         req.IsRequired,
 		IsSearchable:       req.IsSearchable,
 		IsPublic:           req.IsPublic,
+		IsSensitive:        req.IsSensitive,
 		DefaultValue:       req.DefaultValue,
 		Options:            req.Options,
 		Validation:         req.Validation,
@@ -109,16 +124,22 @@ func (s *profileFieldTemplateService) CreateTemplate(ctx context.Context, req *m
 		IsActive:           true,
 	}
 
-	if err := s.templateRepo.Create(template); err != nil {
+	if err := s.templateRepo.Create(ctx, template); err != nil {
 		return nil, fmt.Errorf("创建字段模板失败: %w", err)
 	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionTemplateCreate,
+		TargetType: model.AuditTargetTemplate,
+		TargetID:   template.ID,
+		After:      template.ToResponse(),
+	})
 
 	return template.ToResponse(), nil
 }
 
 // GetTemplateByID 根据 ID 获取字段模板
 func (s *profileFieldTemplateService) GetTemplateByID(ctx context.Context, id int) (*model.ProfileFieldTemplateResponse, error) {
-	template, err := s.templateRepo.GetByID(id)
+	template, err := s.templateRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("字段模板不存在")
@@ -131,7 +152,7 @@ func (s *profileFieldTemplateService) GetTemplateByID(ctx context.Context, id in
 
 // GetTemplateByFieldKey 根据字段标识获取字段模板
 func (s *profileFieldTemplateService) GetTemplateByFieldKey(ctx context.Context, fieldKey string) (*model.ProfileFieldTemplateResponse, error) {
-	template, err := s.templateRepo.GetByFieldKey(fieldKey)
+	template, err := s.templateRepo.GetByFieldKey(ctx, fieldKey)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("字段模板不存在")
@@ -144,13 +165,14 @@ func (s *profileFieldTemplateService) GetTemplateByFieldKey(ctx context.Context,
 
 // UpdateTemplate 更新字段模板
 func (s *profileFieldTemplateService) UpdateTemplate(ctx context.Context, id int, req *model.UpdateProfileFieldTemplateRequest) (*model.ProfileFieldTemplateResponse, error) {
-	template, err := s.templateRepo.GetByID(id)
+	template, err := s.templateRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("字段模板不存在")
 		}
 		return nil, fmt.Errorf("查询字段模板失败: %w", err)
 	}
+	before := template.ToResponse()
 
 	// 更新字段
 	if req.FieldName != "" {
@@ -168,6 +190,9 @@ func (s *profileFieldTemplateService) UpdateTemplate(ctx context.Context, id int
 	if req.IsPublic != nil {
 		template.IsPublic = *req.IsPublic
 	}
+	if req.IsSensitive != nil {
+		template.IsSensitive = *req.IsSensitive
+	}
 	if req.DefaultValue != "" {
 		template.DefaultValue = req.DefaultValue
 	}
@@ -211,16 +236,23 @@ func (s *profileFieldTemplateService) UpdateTemplate(ctx context.Context, id int
 		template.IsActive = *req.IsActive
 	}
 
-	if err := s.templateRepo.Update(template); err != nil {
+	if err := s.templateRepo.Update(ctx, template); err != nil {
 		return nil, fmt.Errorf("更新字段模板失败: %w", err)
 	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionTemplateUpdate,
+		TargetType: model.AuditTargetTemplate,
+		TargetID:   template.ID,
+		Before:     before,
+		After:      template.ToResponse(),
+	})
 
 	return template.ToResponse(), nil
 }
 
 // DeleteTemplate 删除字段模板
 func (s *profileFieldTemplateService) DeleteTemplate(ctx context.Context, id int) error {
-	_, err := s.templateRepo.GetByID(id)
+	template, err := s.templateRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.New("字段模板不存在")
@@ -228,13 +260,22 @@ func (s *profileFieldTemplateService) DeleteTemplate(ctx context.Context, id int
 		return fmt.Errorf("查询字段模板失败: %w", err)
 	}
 
-	return s.templateRepo.Delete(id)
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionTemplateDelete,
+		TargetType: model.AuditTargetTemplate,
+		TargetID:   id,
+		Before:     template.ToResponse(),
+	})
+	return nil
 }
 
-// ListTemplates 获取字段模板列表
-func (s *profileFieldTemplateService) ListTemplates(ctx context.Context, category string, fieldType string, isActive *bool, page, pageSize int) ([]*model.ProfileFieldTemplateResponse, int64, error) {
+// ListTemplates 按查询规格获取字段模板列表
+func (s *profileFieldTemplateService) ListTemplates(ctx context.Context, spec *query.Spec, page, pageSize int) ([]*model.ProfileFieldTemplateResponse, int64, error) {
 	offset := (page - 1) * pageSize
-	templates, total, err := s.templateRepo.List(category, fieldType, isActive, offset, pageSize)
+	templates, total, err := s.templateRepo.List(ctx, spec, offset, pageSize)
 	if err != nil {
 		return nil, 0, fmt.Errorf("查询字段模板列表失败: %w", err)
 	}
@@ -247,9 +288,28 @@ func (s *profileFieldTemplateService) ListTemplates(ctx context.Context, categor
 	return responses, total, nil
 }
 
+// ListTemplatesByCursor 按查询规格和游标获取字段模板列表，withTotal 为 true 时同时返回总数
+func (s *profileFieldTemplateService) ListTemplatesByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.ProfileFieldTemplateResponse], *int64, error) {
+	page, err := s.templateRepo.ListByCursor(ctx, spec, cursor, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("查询字段模板列表失败: %w", err)
+	}
+
+	var total *int64
+	if withTotal {
+		count, err := s.templateRepo.Count(ctx, spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("统计字段模板数量失败: %w", err)
+		}
+		total = &count
+	}
+
+	return query.MapPage(page, (*model.ProfileFieldTemplate).ToResponse), total, nil
+}
+
 // GetTemplatesByCategory 根据分类获取字段模板列表
 func (s *profileFieldTemplateService) GetTemplatesByCategory(ctx context.Context, category string) ([]*model.ProfileFieldTemplateResponse, error) {
-	templates, err := s.templateRepo.GetByCategory(category)
+	templates, err := s.templateRepo.GetByCategory(ctx, category)
 	if err != nil {
 		return nil, fmt.Errorf("查询字段模板列表失败: %w", err)
 	}
@@ -265,7 +325,40 @@ func (s *profileFieldTemplateService) GetTemplatesByCategory(ctx context.Context
 // ApplyTemplateToUser 将字段模板应用到用户
 // 在 profile_fields 表中创建一条记录
 func (s *profileFieldTemplateService) ApplyTemplateToUser(ctx context.Context, templateID, userID int) (*ApplyTemplateResult, error) {
-	template, err := s.templateRepo.GetByID(templateID)
+	ctx, span := tracing.Start(ctx, "ProfileFieldTemplateService.ApplyTemplateToUser",
+		attribute.Int("template.id", templateID),
+		attribute.Int("user.id", userID),
+	)
+	defer span.End()
+
+	result, err := s.applyTemplateToUser(ctx, templateID, userID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		metrics.TemplatesApplied.WithLabelValues(metrics.ResultFailure).Inc()
+		return nil, err
+	}
+	metrics.TemplatesApplied.WithLabelValues(metrics.ResultSuccess).Inc()
+	return result, nil
+}
+
+// applyTemplateToUser 将字段模板应用到用户
+// 检查和创建在同一事务中执行，在批量应用的事务中调用时使用保存点
+func (s *profileFieldTemplateService) applyTemplateToUser(ctx context.Context, templateID, userID int) (*ApplyTemplateResult, error) {
+	var result *ApplyTemplateResult
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.applyTemplateToUserTx(ctx, templateID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyTemplateToUserTx 在事务中将字段模板应用到用户
+func (s *profileFieldTemplateService) applyTemplateToUserTx(ctx context.Context, templateID, userID int) (*ApplyTemplateResult, error) {
+	template, err := s.templateRepo.GetByID(ctx, templateID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("字段模板不存在")
@@ -278,7 +371,7 @@ func (s *profileFieldTemplateService) ApplyTemplateToUser(ctx context.Context, t
 	}
 
 	// 检查用户是否已经应用过该字段模板
-	existingField, err := s.fieldRepo.GetByUserIDAndFieldKey(userID, template.FieldKey)
+	existingField, err := s.fieldRepo.GetByUserIDAndFieldKey(ctx, userID, template.FieldKey)
 	if err == nil && existingField != nil {
 		return nil, fmt.Errorf("用户已应用该字段模板，字段ID: %d", existingField.ID)
 	} else if err != nil && err != gorm.ErrRecordNotFound {
@@ -287,7 +380,7 @@ func (s *profileFieldTemplateService) ApplyTemplateToUser(ctx context.Context, t
 
 	// 将模板应用到用户，创建 profile_field 记录
 	field := template.ApplyToUser(userID)
-	if err := s.fieldRepo.Create(field); err != nil {
+	if err := s.fieldRepo.Create(ctx, field); err != nil {
 		return nil, fmt.Errorf("创建用户字段失败: %w", err)
 	}
 
@@ -303,23 +396,48 @@ func (s *profileFieldTemplateService) ApplyTemplateToUser(ctx context.Context, t
 
 // ApplyTemplatesToUser 批量将字段模板应用到用户
 func (s *profileFieldTemplateService) ApplyTemplatesToUser(ctx context.Context, templateIDs []int, userID int) (*ApplyTemplatesResult, error) {
-	result := &ApplyTemplatesResult{
-		AppliedFields: make([]ApplyTemplateResult, 0),
-		TotalCount:    len(templateIDs),
-		SuccessCount:  0,
-		FailedCount:   0,
-	}
+	ctx, span := tracing.Start(ctx, "ProfileFieldTemplateService.ApplyTemplatesToUser",
+		attribute.Int("template.count", len(templateIDs)),
+		attribute.Int("user.id", userID),
+	)
+	defer span.End()
+
+	var result *ApplyTemplatesResult
+
+	// 整批在一个事务中执行，单个模板失败只回滚到该模板的保存点；
+	// 死锁会使整个事务回滚，此时返回错误由事务管理器重试整批
+	err := s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		result = &ApplyTemplatesResult{
+			AppliedFields: make([]ApplyTemplateResult, 0),
+			TotalCount:    len(templateIDs),
+			SuccessCount:  0,
+			FailedCount:   0,
+		}
 
-	for _, templateID := range templateIDs {
-		applyResult, err := s.ApplyTemplateToUser(ctx, templateID, userID)
-		if err != nil {
-			result.FailedCount++
-			continue
+		for _, templateID := range templateIDs {
+			applyResult, err := s.ApplyTemplateToUser(ctx, templateID, userID)
+			if err != nil {
+				if database.IsRetryable(err) {
+					return err
+				}
+				result.FailedCount++
+				continue
+			}
+			result.SuccessCount++
+			result.AppliedFields = append(result.AppliedFields, *applyResult)
 		}
-		result.SuccessCount++
-		result.AppliedFields = append(result.AppliedFields, *applyResult)
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, fmt.Errorf("批量应用字段模板失败: %w", err)
 	}
 
+	span.SetAttributes(
+		attribute.Int("template.success_count", result.SuccessCount),
+		attribute.Int("template.failed_count", result.FailedCount),
+	)
+
 	if result.SuccessCount > 0 {
 		result.Message = fmt.Sprintf("成功应用 %d 个字段模板", result.SuccessCount)
 	} else {
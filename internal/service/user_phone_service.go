@@ -0,0 +1,402 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/pkg/database"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/requestinfo"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 验证码场景，登录验证码沿用 sms:code:{phone}，其余场景按场景区分，避免互相冒用
+const (
+	codeSceneChangePhoneOld = "change_phone_old" // 更换手机号时验证原手机号
+	codeSceneChangePhoneNew = "change_phone_new" // 更换手机号时验证新手机号
+)
+
+// 验证码和更换手机号凭证的有效期
+const (
+	codeTTL              = 5 * time.Minute
+	phoneChangeTicketTTL = 15 * time.Minute
+)
+
+// phoneChangeTicket 更换手机号凭证
+// 验证原手机号或由管理员签发后生成，发送新手机号验证码时绑定新手机号，更换成功后删除
+type phoneChangeTicket struct {
+	Ticket     string `json:"ticket"`
+	Method     string `json:"method"`
+	OperatorID int    `json:"operator_id"`
+	NewPhone   string `json:"new_phone,omitempty"`
+}
+
+// 验证码校验错误，登录时据此区分指标
+var (
+	errCodeExpired   = appErrors.BadRequest("验证码已过期或不存在")
+	errCodeMismatch  = appErrors.BadRequest("验证码错误")
+	errCodeExhausted = appErrors.BadRequest("验证码错误次数过多，请重新获取")
+)
+
+// codeKey 验证码的 Redis 键
+func codeKey(scene, phone string) string {
+	return fmt.Sprintf("sms:code:%s:%s", scene, phone)
+}
+
+// loginCodeKey 登录验证码的 Redis 键
+func loginCodeKey(phone string) string {
+	return fmt.Sprintf("sms:code:%s", phone)
+}
+
+// codeAttemptsKey 验证码错误次数的 Redis 键
+func codeAttemptsKey(key string) string {
+	return key + ":attempts"
+}
+
+// phoneChangeTicketKey 更换手机号凭证的 Redis 键，每个用户同时只有一个有效凭证
+func phoneChangeTicketKey(userID int) string {
+	return fmt.Sprintf("phone_change:ticket:%d", userID)
+}
+
+// SendOldPhoneCode 向当前手机号发送验证码，用于确认本人更换手机号
+func (s *userService) SendOldPhoneCode(ctx context.Context, userID int) (*model.SendCodeResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sendCode(ctx, codeKey(codeSceneChangePhoneOld, user.Phone))
+}
+
+// VerifyOldPhone 校验原手机号验证码，通过后签发更换手机号凭证
+func (s *userService) VerifyOldPhone(ctx context.Context, userID int, req *model.VerifyOldPhoneRequest) (*model.PhoneChangeTicketResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verifyCode(ctx, codeKey(codeSceneChangePhoneOld, user.Phone), req.Code); err != nil {
+		return nil, err
+	}
+	return s.issuePhoneChangeTicket(ctx, userID, model.PhoneChangeMethodVerify, userID)
+}
+
+// IssuePhoneChangeTicket 管理员为无法接收原手机号验证码的用户签发更换手机号凭证
+// 管理员应在核实用户身份后调用，凭证交由用户本人完成新手机号验证
+func (s *userService) IssuePhoneChangeTicket(ctx context.Context, operatorID, userID int) (*model.PhoneChangeTicketResponse, error) {
+	if _, err := s.getUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	resp, err := s.issuePhoneChangeTicket(ctx, userID, model.PhoneChangeMethodRecovery, operatorID)
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "管理员签发更换手机号凭证",
+		slog.Int("user_id", userID),
+		slog.Int("operator_id", operatorID),
+	)
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserPhoneTicket,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userID,
+		ActorID:    operatorID,
+	})
+	return resp, nil
+}
+
+// SendNewPhoneCode 校验更换手机号凭证，向新手机号发送验证码并将凭证绑定到新手机号
+func (s *userService) SendNewPhoneCode(ctx context.Context, userID int, req *model.SendNewPhoneCodeRequest) (*model.SendCodeResponse, error) {
+	phoneNumber, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := s.loadPhoneChangeTicket(ctx, userID, req.Ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if phoneNumber == user.Phone {
+		return nil, appErrors.BadRequest("新手机号不能与原手机号相同")
+	}
+	if _, err := s.userRepo.GetByPhone(ctx, phoneNumber); err == nil {
+		return nil, appErrors.Conflict("手机号已存在")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
+		return nil, errors.New("查询用户失败")
+	}
+
+	ticket.NewPhone = phoneNumber
+	if err := s.savePhoneChangeTicket(ctx, userID, ticket, redis.KeepTTL); err != nil {
+		return nil, err
+	}
+	return s.sendCode(ctx, codeKey(codeSceneChangePhoneNew, phoneNumber))
+}
+
+// ChangePhone 校验凭证和新手机号验证码后更换手机号
+// 更换后 token 版本递增，已签发的 token 全部失效，返回使用新版本签发的 token
+func (s *userService) ChangePhone(ctx context.Context, userID int, req *model.ChangePhoneRequest) (*model.LoginResponse, error) {
+	phoneNumber, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := s.loadPhoneChangeTicket(ctx, userID, req.Ticket)
+	if err != nil {
+		return nil, err
+	}
+	if ticket.NewPhone == "" || ticket.NewPhone != phoneNumber {
+		return nil, appErrors.BadRequest("请先向新手机号发送验证码")
+	}
+	if err := s.verifyCode(ctx, codeKey(codeSceneChangePhoneNew, phoneNumber), req.Code); err != nil {
+		return nil, err
+	}
+
+	var user *model.User
+	err = s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		oldPhone := user.Phone
+		user.Phone = phoneNumber
+		user.TokenVersion++
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		info := requestinfo.FromContext(ctx)
+		err = s.phoneChangeRepo.Create(ctx, &model.UserPhoneChange{
+			UserID:     userID,
+			OldPhone:   oldPhone,
+			NewPhone:   phoneNumber,
+			Method:     ticket.Method,
+			OperatorID: ticket.OperatorID,
+			IP:         info.IP,
+			UserAgent:  info.UserAgent,
+			RequestID:  info.RequestID,
+		})
+		if err != nil {
+			return err
+		}
+
+		s.auditor.Record(ctx, &AuditEvent{
+			Action:     model.AuditActionUserPhoneChange,
+			TargetType: model.AuditTargetUser,
+			TargetID:   userID,
+			ActorID:    userID,
+			Before:     map[string]any{"phone": model.MaskPhone(oldPhone)},
+			After:      map[string]any{"phone": model.MaskPhone(phoneNumber), "method": ticket.Method},
+		})
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, appErrors.NotFound("用户不存在")
+		case database.IsDuplicateKey(err):
+			return nil, appErrors.Conflict("手机号已存在")
+		}
+		logger.FromContext(ctx).ErrorContext(ctx, "更换手机号失败", slog.Any("error", err))
+		return nil, errors.New("更换手机号失败")
+	}
+
+	if err := s.redis.Del(ctx, phoneChangeTicketKey(userID)).Err(); err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "删除更换手机号凭证失败", slog.Any("error", err))
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "用户更换手机号",
+		slog.Int("user_id", userID),
+		slog.String("method", ticket.Method),
+	)
+
+	token, err := s.jwt.GenerateToken(user.ID, user.Role, user.TokenVersion)
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "生成token失败", slog.Any("error", err))
+		return nil, errors.New("生成token失败")
+	}
+
+	return &model.LoginResponse{
+		User:  user.ToResponse(),
+		Token: token,
+	}, nil
+}
+
+// getUser 获取用户，不存在时返回 404
+func (s *userService) getUser(ctx context.Context, userID int) (*model.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NotFound("用户不存在")
+		}
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
+		return nil, errors.New("查询用户失败")
+	}
+	return user, nil
+}
+
+// sendCode 生成验证码并存储到 Redis
+func (s *userService) sendCode(ctx context.Context, key string) (*model.SendCodeResponse, error) {
+	if s.redis == nil {
+		return nil, errors.New("验证码服务不可用")
+	}
+
+	code, err := newCode()
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "生成验证码失败", slog.Any("error", err))
+		return nil, errors.New("生成验证码失败")
+	}
+	// 重新发送时清零错误次数
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, code, codeTTL)
+		pipe.Del(ctx, codeAttemptsKey(key))
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "存储验证码失败", slog.Any("error", err))
+		return nil, errors.New("存储验证码失败")
+	}
+	metrics.SMSCodesSent.Inc()
+
+	return s.codeResponse(code), nil
+}
+
+// codeResponse 发送验证码响应，仅开启 sms.expose_code 时返回验证码
+func (s *userService) codeResponse(code string) *model.SendCodeResponse {
+	if !s.smsConfig.ExposeCode {
+		return &model.SendCodeResponse{}
+	}
+	return &model.SendCodeResponse{Code: code}
+}
+
+// verifyCode 校验验证码，通过后删除，每个验证码只能使用一次
+// 错误次数达到 sms.max_attempts 后删除验证码，需要重新发送
+func (s *userService) verifyCode(ctx context.Context, key, code string) error {
+	if s.redis == nil {
+		return errors.New("验证码服务不可用")
+	}
+
+	storedCode, err := s.redis.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return errCodeExpired
+	} else if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "验证验证码失败", slog.Any("error", err))
+		return errors.New("验证验证码失败")
+	}
+	if subtle.ConstantTimeCompare([]byte(storedCode), []byte(code)) != 1 {
+		return s.recordCodeFailure(ctx, key)
+	}
+
+	s.redis.Del(ctx, key, codeAttemptsKey(key))
+	return nil
+}
+
+// recordCodeFailure 记录一次验证码错误，达到次数上限时删除验证码
+func (s *userService) recordCodeFailure(ctx context.Context, key string) error {
+	attemptsKey := codeAttemptsKey(key)
+	var incr *redis.IntCmd
+	_, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, attemptsKey)
+		pipe.Expire(ctx, attemptsKey, codeTTL)
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "记录验证码错误次数失败", slog.Any("error", err))
+		return errors.New("验证验证码失败")
+	}
+	if incr.Val() < int64(s.smsConfig.MaxAttempts) {
+		return errCodeMismatch
+	}
+
+	if err := s.redis.Del(ctx, key, attemptsKey).Err(); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "删除验证码失败", slog.Any("error", err))
+		return errors.New("验证验证码失败")
+	}
+	logger.FromContext(ctx).WarnContext(ctx, "验证码错误次数过多，已失效", slog.Int64("attempts", incr.Val()))
+	return errCodeExhausted
+}
+
+// issuePhoneChangeTicket 签发更换手机号凭证，覆盖该用户之前的凭证
+func (s *userService) issuePhoneChangeTicket(ctx context.Context, userID int, method string, operatorID int) (*model.PhoneChangeTicketResponse, error) {
+	if s.redis == nil {
+		return nil, errors.New("验证码服务不可用")
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "生成更换手机号凭证失败", slog.Any("error", err))
+		return nil, errors.New("生成更换手机号凭证失败")
+	}
+	ticket := &phoneChangeTicket{
+		Ticket:     hex.EncodeToString(b),
+		Method:     method,
+		OperatorID: operatorID,
+	}
+	if err := s.savePhoneChangeTicket(ctx, userID, ticket, phoneChangeTicketTTL); err != nil {
+		return nil, err
+	}
+
+	return &model.PhoneChangeTicketResponse{
+		Ticket:    ticket.Ticket,
+		ExpiresAt: time.Now().Add(phoneChangeTicketTTL),
+	}, nil
+}
+
+// savePhoneChangeTicket 存储更换手机号凭证，ttl 为 redis.KeepTTL 时保留原有效期
+func (s *userService) savePhoneChangeTicket(ctx context.Context, userID int, ticket *phoneChangeTicket, ttl time.Duration) error {
+	data, err := json.Marshal(ticket)
+	if err != nil {
+		return fmt.Errorf("序列化更换手机号凭证失败: %w", err)
+	}
+	if err := s.redis.Set(ctx, phoneChangeTicketKey(userID), data, ttl).Err(); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "存储更换手机号凭证失败", slog.Any("error", err))
+		return errors.New("存储更换手机号凭证失败")
+	}
+	return nil
+}
+
+// loadPhoneChangeTicket 读取并校验更换手机号凭证
+func (s *userService) loadPhoneChangeTicket(ctx context.Context, userID int, value string) (*phoneChangeTicket, error) {
+	if s.redis == nil {
+		return nil, errors.New("验证码服务不可用")
+	}
+
+	data, err := s.redis.Get(ctx, phoneChangeTicketKey(userID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, appErrors.BadRequest("更换手机号凭证无效或已过期")
+	} else if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "读取更换手机号凭证失败", slog.Any("error", err))
+		return nil, errors.New("读取更换手机号凭证失败")
+	}
+
+	var ticket phoneChangeTicket
+	if err := json.Unmarshal(data, &ticket); err != nil {
+		return nil, fmt.Errorf("解析更换手机号凭证失败: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(ticket.Ticket), []byte(value)) != 1 {
+		return nil, appErrors.BadRequest("更换手机号凭证无效或已过期")
+	}
+	return &ticket, nil
+}
+
+// newCode 生成 6 位数字验证码
+func newCode() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	n := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	return fmt.Sprintf("%06d", n%1000000), nil
+}
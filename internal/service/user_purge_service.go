@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/internal/model"
+	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/storage"
+	"gorm.io/gorm"
+)
+
+// purgeBatchSize 每批清除的用户数量
+const purgeBatchSize = 100
+
+// UserPurgeService 注销用户数据清除服务接口
+type UserPurgeService interface {
+	PurgeDeletedUsers(ctx context.Context) (int, error)
+}
+
+// userPurgeService 注销用户数据清除服务实现
+type userPurgeService struct {
+	userRepo        repository.UserRepository
+	fieldRepo       repository.ProfileFieldRepository
+	mediaRepo       repository.MediaRepository
+	phoneChangeRepo repository.PhoneChangeRepository
+	exportRepo      repository.DataExportRepository
+	storage         storage.Storage
+	exportStorage   storage.Private
+	txManager       *database.TxManager
+	userConfig      *config.UserConfig
+	auditor         Auditor
+}
+
+// NewUserPurgeService 创建注销用户数据清除服务实例
+func NewUserPurgeService(
+	userRepo repository.UserRepository,
+	fieldRepo repository.ProfileFieldRepository,
+	mediaRepo repository.MediaRepository,
+	phoneChangeRepo repository.PhoneChangeRepository,
+	exportRepo repository.DataExportRepository,
+	storage storage.Storage,
+	exportStorage storage.Private,
+	txManager *database.TxManager,
+	userConfig *config.UserConfig,
+	auditor Auditor,
+) UserPurgeService {
+	return &userPurgeService{
+		userRepo:        userRepo,
+		fieldRepo:       fieldRepo,
+		mediaRepo:       mediaRepo,
+		phoneChangeRepo: phoneChangeRepo,
+		exportRepo:      exportRepo,
+		storage:         storage,
+		exportStorage:   exportStorage,
+		txManager:       txManager,
+		userConfig:      userConfig,
+		auditor:         auditor,
+	}
+}
+
+// errPurgeSkipped 用户在清除前已被恢复或已由其他副本清除
+var errPurgeSkipped = errors.New("用户已恢复或已清除")
+
+// PurgeDeletedUsers 物理删除超过保留期的注销用户及其资料字段、媒体文件、手机号变更记录和数据导出文件，返回清除的用户数量
+// 单个用户清除失败时记录日志并跳过，下次执行时重试
+func (s *userPurgeService) PurgeDeletedUsers(ctx context.Context) (int, error) {
+	before := time.Now().Add(-s.userConfig.GetPurgeAfter())
+	purged, failed, afterID := 0, 0, 0
+	for {
+		users, err := s.userRepo.ListDeletedBefore(ctx, before, afterID, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+
+		for _, user := range users {
+			afterID = user.ID
+			if err := s.purge(ctx, user.ID, before); err != nil {
+				if errors.Is(err, errPurgeSkipped) {
+					logger.FromContext(ctx).InfoContext(ctx, "用户已恢复或已清除，跳过", slog.Int("user_id", user.ID))
+					continue
+				}
+				failed++
+				logger.FromContext(ctx).ErrorContext(ctx, "清除用户数据失败", slog.Int("user_id", user.ID), slog.Any("error", err))
+				continue
+			}
+			purged++
+			logger.FromContext(ctx).InfoContext(ctx, "用户数据已清除", slog.Int("user_id", user.ID))
+		}
+
+		if len(users) < purgeBatchSize {
+			break
+		}
+	}
+
+	if failed > 0 {
+		return purged, fmt.Errorf("%d 个用户的数据清除失败", failed)
+	}
+	return purged, nil
+}
+
+// purge 清除单个用户的数据
+// 在事务中锁定用户行并确认仍处于注销状态，避免与管理员恢复并发时误删；对象存储中的文件在事务提交后删除，
+// 删除失败的文件只记录日志，数据库记录已不存在，不会再被引用
+func (s *userPurgeService) purge(ctx context.Context, userID int, before time.Time) error {
+	return s.txManager.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.userRepo.LockDeletedBefore(ctx, userID, before); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errPurgeSkipped
+			}
+			return fmt.Errorf("查询用户失败: %w", err)
+		}
+
+		items, err := s.mediaRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("查询媒体文件失败: %w", err)
+		}
+		exports, err := s.exportRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("查询数据导出任务失败: %w", err)
+		}
+		var keys, exportKeys []string
+		for _, m := range items {
+			keys = append(keys, m.StorageKey, m.ThumbnailKey)
+		}
+		for _, export := range exports {
+			exportKeys = append(exportKeys, export.StorageKey)
+		}
+
+		if err := s.fieldRepo.DeleteByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("删除资料字段失败: %w", err)
+		}
+		if err := s.mediaRepo.DeleteByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("删除媒体文件记录失败: %w", err)
+		}
+		if err := s.phoneChangeRepo.DeleteByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("删除手机号变更记录失败: %w", err)
+		}
+		if err := s.exportRepo.DeleteByUserID(ctx, userID); err != nil {
+			return fmt.Errorf("删除数据导出任务失败: %w", err)
+		}
+		deleted, err := s.userRepo.Purge(ctx, userID, before)
+		if err != nil {
+			return fmt.Errorf("删除用户失败: %w", err)
+		}
+		if !deleted {
+			return errPurgeSkipped
+		}
+
+		database.AfterCommit(ctx, func(ctx context.Context) {
+			s.deleteObjects(ctx, s.storage, userID, keys)
+			s.deleteObjects(ctx, s.exportStorage, userID, exportKeys)
+		})
+		s.auditor.Record(ctx, &AuditEvent{
+			Action:     model.AuditActionUserPurge,
+			TargetType: model.AuditTargetUser,
+			TargetID:   userID,
+		})
+		return nil
+	})
+}
+
+// deleteObjects 删除对象存储中的文件，失败时记录日志
+func (s *userPurgeService) deleteObjects(ctx context.Context, store storage.Storage, userID int, keys []string) {
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := store.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			logger.FromContext(ctx).ErrorContext(ctx, "删除文件失败",
+				slog.Int("user_id", userID),
+				slog.String("key", key),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
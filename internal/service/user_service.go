@@ -4,12 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math/rand"
+	"log/slog"
 	"time"
 
+	"github.com/deantook/dove/internal/config"
 	"github.com/deantook/dove/internal/model"
 	"github.com/deantook/dove/internal/repository"
+	"github.com/deantook/dove/pkg/database"
+	appErrors "github.com/deantook/dove/pkg/errors"
 	"github.com/deantook/dove/pkg/jwt"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/phone"
+	"github.com/deantook/dove/pkg/query"
+	"github.com/deantook/dove/pkg/tracing"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
@@ -20,70 +28,110 @@ type UserService interface {
 	GetUserByID(ctx context.Context, id int) (*model.UserResponse, error)
 	UpdateUser(ctx context.Context, id int, req *model.UpdateUserRequest) (*model.UserResponse, error)
 	DeleteUser(ctx context.Context, id int) error
-	ListUsers(ctx context.Context, page, pageSize int) ([]*model.UserResponse, int64, error)
+	ListUsers(ctx context.Context, spec *query.Spec, page, pageSize int) ([]*model.UserResponse, int64, error)
+	ListUsersByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.UserResponse], *int64, error)
 	SendCode(ctx context.Context, req *model.SendCodeRequest) (*model.SendCodeResponse, error)
 	LoginOrRegister(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error)
+	CreateAdmin(ctx context.Context, phone, username string) (*model.UserResponse, error)
+	SendOldPhoneCode(ctx context.Context, userID int) (*model.SendCodeResponse, error)
+	VerifyOldPhone(ctx context.Context, userID int, req *model.VerifyOldPhoneRequest) (*model.PhoneChangeTicketResponse, error)
+	IssuePhoneChangeTicket(ctx context.Context, operatorID, userID int) (*model.PhoneChangeTicketResponse, error)
+	SendNewPhoneCode(ctx context.Context, userID int, req *model.SendNewPhoneCodeRequest) (*model.SendCodeResponse, error)
+	ChangePhone(ctx context.Context, userID int, req *model.ChangePhoneRequest) (*model.LoginResponse, error)
+	UpdateUserStatus(ctx context.Context, operatorID, userID int, req *model.UpdateUserStatusRequest) (*model.UserResponse, error)
+	ReleaseExpiredStatuses(ctx context.Context) (int, error)
+	RestoreUser(ctx context.Context, id int) (*model.UserResponse, error)
 }
 
 // userService 用户服务实现
 type userService struct {
-	userRepo repository.UserRepository
-	redis    *redis.Client
+	userRepo        repository.UserRepository
+	phoneChangeRepo repository.PhoneChangeRepository
+	redis           *redis.Client
+	txManager       *database.TxManager
+	phoneParser     *phone.Parser
+	auditor         Auditor
+	smsConfig       *config.SMSConfig
+	jwt             *jwt.Manager
 }
 
 // NewUserService 创建用户服务实例
-func NewUserService(userRepo repository.UserRepository, redis *redis.Client) UserService {
+func NewUserService(
+	userRepo repository.UserRepository,
+	phoneChangeRepo repository.PhoneChangeRepository,
+	redis *redis.Client,
+	txManager *database.TxManager,
+	phoneParser *phone.Parser,
+	auditor Auditor,
+	smsConfig *config.SMSConfig,
+	jwtManager *jwt.Manager,
+) UserService {
 	return &userService{
-		userRepo: userRepo,
-		redis:    redis,
+		userRepo:        userRepo,
+		phoneChangeRepo: phoneChangeRepo,
+		redis:           redis,
+		txManager:       txManager,
+		phoneParser:     phoneParser,
+		auditor:         auditor,
+		smsConfig:       smsConfig,
+		jwt:             jwtManager,
 	}
 }
 
 // CreateUser 创建用户
 func (s *userService) CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.UserResponse, error) {
+	phoneNumber, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
+
 	// 检查用户名是否已存在
-	if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
+	if _, err := s.userRepo.GetByUsername(ctx, req.Username); err == nil {
 		return nil, errors.New("用户名已存在")
 	} else if err != gorm.ErrRecordNotFound {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 		return nil, errors.New("查询用户失败")
 	}
 
 	// 检查手机号是否已存在
-	if _, err := s.userRepo.GetByPhone(req.Phone); err == nil {
+	if _, err := s.userRepo.GetByPhone(ctx, phoneNumber); err == nil {
 		return nil, errors.New("手机号已存在")
 	} else if err != gorm.ErrRecordNotFound {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 		return nil, errors.New("查询用户失败")
 	}
 
 	now := time.Now()
 	user := &model.User{
 		Username:   req.Username,
-		Phone:      req.Phone,
+		Phone:      phoneNumber,
+		Role:       model.UserRoleUser,
 		CreateTime: now,
 		UpdateTime: now,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "创建用户失败", slog.Any("error", err))
 		return nil, errors.New("创建用户失败")
 	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserCreate,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.ID,
+		After:      auditUser(user),
+	})
 
 	return user.ToResponse(), nil
 }
 
 // GetUserByID 根据 ID 获取用户
 func (s *userService) GetUserByID(ctx context.Context, id int) (*model.UserResponse, error) {
-	// 尝试从缓存获取
-	cacheKey := fmt.Sprintf("user:%d", id)
-	if s.redis != nil {
-		// 这里可以添加缓存逻辑
-		_ = cacheKey
-	}
-
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.New("用户不存在")
 		}
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 		return nil, errors.New("查询用户失败")
 	}
 
@@ -92,43 +140,38 @@ func (s *userService) GetUserByID(ctx context.Context, id int) (*model.UserRespo
 
 // UpdateUser 更新用户
 func (s *userService) UpdateUser(ctx context.Context, id int, req *model.UpdateUserRequest) (*model.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(gorm.ErrRecordNotFound, err) {
 			return nil, errors.New("用户不存在")
 		}
+		logger.FromContext(ctx).ErrorContext(ctx, "更新用户失败", slog.Any("error", err))
 		return nil, errors.New("更新用户失败")
 	}
+	before := auditUser(user)
 
 	// 如果更新用户名，检查是否重复
 	if req.Username != "" && req.Username != user.Username {
-		if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
+		if _, err := s.userRepo.GetByUsername(ctx, req.Username); err == nil {
 			return nil, errors.New("用户名已存在")
 		} else if !errors.Is(gorm.ErrRecordNotFound, err) {
+			logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 			return nil, errors.New("查询用户失败")
 		}
 		user.Username = req.Username
 	}
 
-	// 如果更新手机号，检查是否重复
-	if req.Phone != "" && req.Phone != user.Phone {
-		if _, err := s.userRepo.GetByPhone(req.Phone); err == nil {
-			return nil, errors.New("手机号已存在")
-		} else if !errors.Is(gorm.ErrRecordNotFound, err) {
-			return nil, errors.New("查询用户失败")
-		}
-		user.Phone = req.Phone
-	}
-
-	if err := s.userRepo.Update(user); err != nil {
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "更新用户失败", slog.Any("error", err))
 		return nil, errors.New("更新用户失败")
 	}
-
-	// 清除缓存
-	if s.redis != nil {
-		cacheKey := fmt.Sprintf("user:%d", id)
-		s.redis.Del(ctx, cacheKey)
-	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserUpdate,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.ID,
+		Before:     before,
+		After:      auditUser(user),
+	})
 
 	return user.ToResponse(), nil
 }
@@ -136,28 +179,71 @@ func (s *userService) UpdateUser(ctx context.Context, id int, req *model.UpdateU
 // DeleteUser 删除用户
 func (s *userService) DeleteUser(ctx context.Context, id int) error {
 	// 检查用户是否存在
-	if _, err := s.userRepo.GetByID(id); err != nil {
+	user, err := s.userRepo.GetByID(ctx, id)
+	if err != nil {
 		if errors.Is(gorm.ErrRecordNotFound, err) {
 			return errors.New("用户不存在")
 		}
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 		return errors.New("查询用户失败")
 	}
 
-	if err := s.userRepo.Delete(id); err != nil {
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "删除用户失败", slog.Any("error", err))
 		return errors.New("删除用户失败")
 	}
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserDelete,
+		TargetType: model.AuditTargetUser,
+		TargetID:   id,
+		Before:     auditUser(user),
+	})
 
-	// 清除缓存
-	if s.redis != nil {
-		cacheKey := fmt.Sprintf("user:%d", id)
-		s.redis.Del(ctx, cacheKey)
+	return nil
+}
+
+// RestoreUser 恢复已删除的用户，仅在保留期内、数据尚未清除时可以恢复
+// 手机号已被其他用户注册时不能恢复
+func (s *userService) RestoreUser(ctx context.Context, id int) (*model.UserResponse, error) {
+	user, err := s.userRepo.GetDeletedByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, appErrors.NotFound("用户不存在或未被删除")
+		}
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
+		return nil, errors.New("查询用户失败")
 	}
 
-	return nil
+	if _, err := s.userRepo.GetByPhone(ctx, user.Phone); err == nil {
+		return nil, appErrors.Conflict("手机号已被其他用户注册，无法恢复")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
+		return nil, errors.New("查询用户失败")
+	}
+
+	if err := s.userRepo.Restore(ctx, id); err != nil {
+		if database.IsDuplicateKey(err) {
+			return nil, appErrors.Conflict("手机号已被其他用户注册，无法恢复")
+		}
+		logger.FromContext(ctx).ErrorContext(ctx, "恢复用户失败", slog.Any("error", err))
+		return nil, errors.New("恢复用户失败")
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "用户已恢复", slog.Int("user_id", id))
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserRestore,
+		TargetType: model.AuditTargetUser,
+		TargetID:   id,
+	})
+
+	restored, err := s.getUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return restored.ToResponse(), nil
 }
 
-// ListUsers 获取用户列表（分页）
-func (s *userService) ListUsers(ctx context.Context, page, pageSize int) ([]*model.UserResponse, int64, error) {
+// ListUsers 按查询规格获取用户列表（分页）
+func (s *userService) ListUsers(ctx context.Context, spec *query.Spec, page, pageSize int) ([]*model.UserResponse, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -169,8 +255,9 @@ func (s *userService) ListUsers(ctx context.Context, page, pageSize int) ([]*mod
 	}
 
 	offset := (page - 1) * pageSize
-	users, total, err := s.userRepo.List(offset, pageSize)
+	users, total, err := s.userRepo.List(ctx, spec, offset, pageSize)
 	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户列表失败", slog.Any("error", err))
 		return nil, 0, errors.New("查询用户列表失败")
 	}
 
@@ -182,74 +269,238 @@ func (s *userService) ListUsers(ctx context.Context, page, pageSize int) ([]*mod
 	return responses, total, nil
 }
 
+// ListUsersByCursor 按查询规格和游标获取用户列表，withTotal 为 true 时同时返回总数
+func (s *userService) ListUsersByCursor(ctx context.Context, spec *query.Spec, cursor *query.Cursor, limit int, withTotal bool) (*query.CursorPage[*model.UserResponse], *int64, error) {
+	page, err := s.userRepo.ListByCursor(ctx, spec, cursor, limit)
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户列表失败", slog.Any("error", err))
+		return nil, nil, errors.New("查询用户列表失败")
+	}
+
+	var total *int64
+	if withTotal {
+		count, err := s.userRepo.Count(ctx, spec)
+		if err != nil {
+			logger.FromContext(ctx).ErrorContext(ctx, "统计用户数量失败", slog.Any("error", err))
+			return nil, nil, errors.New("查询用户列表失败")
+		}
+		total = &count
+	}
+
+	return query.MapPage(page, (*model.User).ToResponse), total, nil
+}
+
 // SendCode 发送验证码
 func (s *userService) SendCode(ctx context.Context, req *model.SendCodeRequest) (*model.SendCodeResponse, error) {
-	// 生成6位随机验证码
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	code := fmt.Sprintf("%06d", r.Intn(1000000))
+	phoneNumber, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		return nil, err
+	}
 
-	// 将验证码存储到 Redis，有效期5分钟
+	// 未配置 Redis 时不存储验证码，登录时也不校验
 	if s.redis != nil {
-		codeKey := fmt.Sprintf("sms:code:%s", req.Phone)
-		if err := s.redis.Set(ctx, codeKey, code, 5*time.Minute).Err(); err != nil {
-			return nil, errors.New("存储验证码失败")
-		}
+		return s.sendCode(ctx, loginCodeKey(phoneNumber))
 	}
 
-	// 开发阶段返回验证码，生产环境不返回
-	return &model.SendCodeResponse{
-		Code: code,
-	}, nil
+	code, err := newCode()
+	if err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "生成验证码失败", slog.Any("error", err))
+		return nil, errors.New("生成验证码失败")
+	}
+	metrics.SMSCodesSent.Inc()
+	return s.codeResponse(code), nil
 }
 
 // LoginOrRegister 登录或注册
 func (s *userService) LoginOrRegister(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error) {
+	ctx, span := tracing.Start(ctx, "UserService.LoginOrRegister")
+	defer span.End()
+
+	resp, err := s.loginOrRegister(ctx, req)
+	tracing.RecordError(span, err)
+	return resp, err
+}
+
+// loginOrRegister 校验验证码，用户不存在时自动注册，然后签发 token
+func (s *userService) loginOrRegister(ctx context.Context, req *model.LoginRequest) (*model.LoginResponse, error) {
+	phoneNumber, err := s.normalizePhone(req.Phone)
+	if err != nil {
+		metrics.Logins.WithLabelValues(metrics.LoginError).Inc()
+		return nil, err
+	}
+
 	// 验证验证码
 	if s.redis != nil {
-		codeKey := fmt.Sprintf("sms:code:%s", req.Phone)
-		storedCode, err := s.redis.Get(ctx, codeKey).Result()
-		if err == redis.Nil {
-			return nil, errors.New("验证码已过期或不存在")
-		} else if err != nil {
-			return nil, errors.New("验证验证码失败")
-		}
-
-		if storedCode != req.Code {
-			return nil, errors.New("验证码错误")
+		if err := s.verifyCode(ctx, loginCodeKey(phoneNumber), req.Code); err != nil {
+			switch {
+			case errors.Is(err, errCodeExpired):
+				metrics.Logins.WithLabelValues(metrics.LoginCodeExpired).Inc()
+			case errors.Is(err, errCodeMismatch), errors.Is(err, errCodeExhausted):
+				metrics.Logins.WithLabelValues(metrics.LoginCodeMismatch).Inc()
+			default:
+				metrics.Logins.WithLabelValues(metrics.LoginError).Inc()
+			}
+			return nil, err
 		}
-
-		// 验证成功后删除验证码
-		s.redis.Del(ctx, codeKey)
 	}
 
 	// 查找用户是否存在
-	user, err := s.userRepo.GetByPhone(req.Phone)
+	user, err := s.userRepo.GetByPhone(ctx, phoneNumber)
 	if err != nil && err != gorm.ErrRecordNotFound {
+		metrics.Logins.WithLabelValues(metrics.LoginError).Inc()
+		logger.FromContext(ctx).ErrorContext(ctx, "查询用户失败", slog.Any("error", err))
 		return nil, errors.New("查询用户失败")
 	}
 
+	// 冻结、封禁、停用的账号拒绝登录
+	if err == nil {
+		if appErr := user.StatusError(time.Now()); appErr != nil {
+			metrics.Logins.WithLabelValues(metrics.LoginRefused).Inc()
+			logger.FromContext(ctx).InfoContext(ctx, "拒绝登录", slog.Int("user_id", user.ID), slog.Int("status", user.Status))
+			s.auditor.Record(ctx, &AuditEvent{
+				Action:     model.AuditActionAuthLoginRefused,
+				TargetType: model.AuditTargetUser,
+				TargetID:   user.ID,
+				ActorID:    user.ID,
+				After:      map[string]any{"status": user.Status, "status_reason": user.StatusReason},
+			})
+			return nil, appErr
+		}
+	}
+
 	// 如果用户不存在，创建新用户
 	if err == gorm.ErrRecordNotFound {
 		now := time.Now()
 		user = &model.User{
-			Phone:      req.Phone,
-			Username:   req.Phone, // 默认用户名为手机号
+			Phone:      phoneNumber,
+			Username:   model.NewDefaultUsername(),
+			Role:       model.UserRoleUser,
 			CreateTime: now,
 			UpdateTime: now,
 		}
-		if err := s.userRepo.Create(user); err != nil {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			metrics.Logins.WithLabelValues(metrics.LoginError).Inc()
+			logger.FromContext(ctx).ErrorContext(ctx, "创建用户失败", slog.Any("error", err))
 			return nil, errors.New("创建用户失败")
 		}
+		metrics.Registrations.Inc()
+		logger.FromContext(ctx).InfoContext(ctx, "新用户注册", slog.Int("user_id", user.ID))
+		s.auditor.Record(ctx, &AuditEvent{
+			Action:     model.AuditActionUserCreate,
+			TargetType: model.AuditTargetUser,
+			TargetID:   user.ID,
+			ActorID:    user.ID,
+			After:      auditUser(user),
+		})
 	}
 
 	// 生成 JWT token
-	token, err := jwt.GenerateToken(user.ID)
+	_, signSpan := tracing.Start(ctx, "jwt.GenerateToken")
+	token, err := s.jwt.GenerateToken(user.ID, user.Role, user.TokenVersion)
+	tracing.RecordError(signSpan, err)
+	signSpan.End()
 	if err != nil {
+		metrics.Logins.WithLabelValues(metrics.LoginError).Inc()
+		logger.FromContext(ctx).ErrorContext(ctx, "生成token失败", slog.Any("error", err))
 		return nil, errors.New("生成token失败")
 	}
 
+	metrics.Logins.WithLabelValues(metrics.LoginSuccess).Inc()
+	logger.FromContext(ctx).InfoContext(ctx, "用户登录", slog.Int("user_id", user.ID))
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionAuthLogin,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.ID,
+		ActorID:    user.ID,
+	})
+
 	return &model.LoginResponse{
 		User:  user.ToResponse(),
 		Token: token,
 	}, nil
 }
+
+// CreateAdmin 创建管理员
+// 手机号已注册时将该用户提升为管理员，否则创建新的管理员账号
+func (s *userService) CreateAdmin(ctx context.Context, rawPhone, username string) (*model.UserResponse, error) {
+	phoneNumber, err := s.normalizePhone(rawPhone)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByPhone(ctx, phoneNumber)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	if user != nil {
+		before := auditUser(user)
+		user.Role = model.UserRoleAdmin
+		if username != "" && username != user.Username {
+			if existing, err := s.userRepo.GetByUsername(ctx, username); err == nil && existing.ID != user.ID {
+				return nil, errors.New("用户名已存在")
+			} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("查询用户失败: %w", err)
+			}
+			user.Username = username
+		}
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, fmt.Errorf("更新用户失败: %w", err)
+		}
+		logger.FromContext(ctx).InfoContext(ctx, "用户已提升为管理员", slog.Int("user_id", user.ID))
+		s.auditor.Record(ctx, &AuditEvent{
+			Action:     model.AuditActionUserPromote,
+			TargetType: model.AuditTargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      auditUser(user),
+		})
+		return user.ToResponse(), nil
+	}
+
+	if username == "" {
+		username = model.NewDefaultUsername()
+	}
+	if _, err := s.userRepo.GetByUsername(ctx, username); err == nil {
+		return nil, errors.New("用户名已存在")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询用户失败: %w", err)
+	}
+
+	now := time.Now()
+	user = &model.User{
+		Username:   username,
+		Phone:      phoneNumber,
+		Role:       model.UserRoleAdmin,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, fmt.Errorf("创建用户失败: %w", err)
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "管理员已创建", slog.Int("user_id", user.ID))
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserCreate,
+		TargetType: model.AuditTargetUser,
+		TargetID:   user.ID,
+		After:      auditUser(user),
+	})
+
+	return user.ToResponse(), nil
+}
+
+// normalizePhone 将手机号规范化为 E.164 格式，存储和查询统一使用该格式
+func (s *userService) normalizePhone(raw string) (string, error) {
+	phoneNumber, err := s.phoneParser.Normalize(raw)
+	if err != nil {
+		return "", appErrors.BadRequest(err.Error())
+	}
+	return phoneNumber, nil
+}
+
+// auditUser 审计日志中的用户快照，手机号脱敏
+func auditUser(user *model.User) *model.UserResponse {
+	resp := user.ToResponse()
+	resp.MaskPhone()
+	return resp
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/deantook/dove/internal/model"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"github.com/deantook/dove/pkg/logger"
+)
+
+// statusExpireBatchSize 每批恢复的到期用户数量
+const statusExpireBatchSize = 100
+
+// UpdateUserStatus 管理员修改用户状态
+// 冻结、封禁、停用时 token 版本递增，用户已签发的 token 全部失效；恢复正常时清除原因和到期时间
+func (s *userService) UpdateUserStatus(ctx context.Context, operatorID, userID int, req *model.UpdateUserStatusRequest) (*model.UserResponse, error) {
+	if operatorID == userID {
+		return nil, appErrors.BadRequest("不能修改自己的账号状态")
+	}
+	if req.ExpiresAt != nil {
+		if req.Status != model.UserStatusFrozen && req.Status != model.UserStatusBanned {
+			return nil, appErrors.BadRequest("只有冻结和封禁可以设置到期时间")
+		}
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, appErrors.BadRequest("到期时间必须晚于当前时间")
+		}
+	}
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	oldStatus := user.Status
+	before := auditUser(user)
+	if req.Status == model.UserStatusActive {
+		user.StatusReason = ""
+		user.StatusExpiresAt = nil
+	} else {
+		user.StatusReason = req.Reason
+		user.StatusExpiresAt = req.ExpiresAt
+		user.TokenVersion++
+	}
+	user.Status = req.Status
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).ErrorContext(ctx, "修改用户状态失败", slog.Any("error", err))
+		return nil, errors.New("修改用户状态失败")
+	}
+	logger.FromContext(ctx).InfoContext(ctx, "修改用户状态",
+		slog.Int("user_id", userID),
+		slog.Int("operator_id", operatorID),
+		slog.Int("old_status", oldStatus),
+		slog.Int("status", req.Status),
+		slog.String("reason", req.Reason),
+	)
+	s.auditor.Record(ctx, &AuditEvent{
+		Action:     model.AuditActionUserStatus,
+		TargetType: model.AuditTargetUser,
+		TargetID:   userID,
+		ActorID:    operatorID,
+		Before:     before,
+		After:      auditUser(user),
+	})
+
+	return user.ToResponse(), nil
+}
+
+// ReleaseExpiredStatuses 恢复冻结或封禁已到期的用户，返回恢复的数量
+func (s *userService) ReleaseExpiredStatuses(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		users, err := s.userRepo.ListStatusExpired(ctx, time.Now(), statusExpireBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		for _, user := range users {
+			before := auditUser(user)
+			user.Status = model.UserStatusActive
+			user.StatusReason = ""
+			user.StatusExpiresAt = nil
+			if err := s.userRepo.Update(ctx, user); err != nil {
+				return released, err
+			}
+			released++
+			logger.FromContext(ctx).InfoContext(ctx, "用户状态到期恢复", slog.Int("user_id", user.ID))
+			s.auditor.Record(ctx, &AuditEvent{
+				Action:     model.AuditActionUserStatus,
+				TargetType: model.AuditTargetUser,
+				TargetID:   user.ID,
+				Before:     before,
+				After:      auditUser(user),
+			})
+		}
+
+		if len(users) < statusExpireBatchSize {
+			return released, nil
+		}
+	}
+}
@@ -0,0 +1,13 @@
+-- 创建用户表
+CREATE TABLE IF NOT EXISTS `u_user` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `username` VARCHAR(255) COMMENT '用户名',
+    `phone` VARCHAR(20) COMMENT '手机号（登录标识）',
+    `avatar` VARCHAR(500) COMMENT '头像URL',
+    `status` TINYINT DEFAULT 1 COMMENT '用户状态',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deleted_at` DATETIME(3) NULL,
+    UNIQUE KEY `idx_u_user_phone` (`phone`),
+    INDEX `idx_u_user_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户表';
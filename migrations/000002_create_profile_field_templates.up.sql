@@ -0,0 +1,39 @@
+-- 创建系统资料字段模板表（存储系统预设的单个字段类型定义）
+CREATE TABLE IF NOT EXISTS `profile_field_templates` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `field_key` VARCHAR(100) NOT NULL COMMENT '字段唯一标识（如：name, education, school）',
+    `field_name` VARCHAR(100) NOT NULL COMMENT '字段显示名称（如：姓名、学历、毕业学校）',
+    `field_type` VARCHAR(50) NOT NULL COMMENT '字段类型（TEXT_SINGLE, SELECT_SINGLE, NUMBER等）',
+    `is_required` TINYINT(1) DEFAULT 0 COMMENT '是否必填',
+    `is_searchable` TINYINT(1) DEFAULT 0 COMMENT '是否可搜索',
+    `is_public` TINYINT(1) DEFAULT 0 COMMENT '是否公开（默认解锁状态）',
+    `default_value` TEXT COMMENT '默认值（JSON格式）',
+    `options` TEXT COMMENT '选项配置（JSON格式，用于SELECT类型）',
+    `validation` TEXT COMMENT '验证规则（JSON格式）',
+    `display_order` INT DEFAULT 0 COMMENT '显示顺序',
+    `icon` VARCHAR(500) COMMENT '图标URL',
+    `description` VARCHAR(500) COMMENT '字段描述',
+    `default_unlock_rules` TEXT COMMENT '默认解锁规则（JSON格式）',
+    `category` VARCHAR(50) COMMENT '字段分类（如：基本信息、教育背景、工作经历等）',
+    `is_active` TINYINT(1) DEFAULT 1 COMMENT '是否启用',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deleted_at` DATETIME(3) NULL,
+    UNIQUE KEY `uk_field_key` (`field_key`),
+    INDEX `idx_field_type` (`field_type`),
+    INDEX `idx_category` (`category`),
+    INDEX `idx_is_active` (`is_active`),
+    INDEX `idx_display_order` (`display_order`),
+    INDEX `idx_profile_field_templates_deleted_at` (`deleted_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='系统资料字段模板表';
+
+-- 兼容手动执行旧脚本创建的表（缺少软删除字段）
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'profile_field_templates' AND COLUMN_NAME = 'deleted_at') = 0,
+    'ALTER TABLE `profile_field_templates` ADD COLUMN `deleted_at` DATETIME(3) NULL, ADD INDEX `idx_profile_field_templates_deleted_at` (`deleted_at`)',
+    'SELECT 1'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
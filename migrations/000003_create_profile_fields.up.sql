@@ -0,0 +1,22 @@
+-- 创建用户资料字段表（用户引用字段模板或自定义字段后生成的记录）
+CREATE TABLE IF NOT EXISTS `profile_fields` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `user_id` INT NOT NULL DEFAULT 0 COMMENT '用户ID',
+    `field_key` VARCHAR(100) NOT NULL COMMENT '字段唯一标识',
+    `field_name` VARCHAR(100) NOT NULL COMMENT '字段显示名称',
+    `field_type` VARCHAR(50) NOT NULL COMMENT '字段类型',
+    `is_system` TINYINT(1) DEFAULT 0 COMMENT '是否来自系统模板',
+    `is_required` TINYINT(1) DEFAULT 0 COMMENT '是否必填',
+    `is_searchable` TINYINT(1) DEFAULT 0 COMMENT '是否可搜索',
+    `is_public` TINYINT(1) DEFAULT 0 COMMENT '是否公开',
+    `default_value` TEXT COMMENT '默认值（JSON格式）',
+    `options` TEXT COMMENT '选项配置（JSON格式）',
+    `validation` TEXT COMMENT '验证规则（JSON格式）',
+    `display_order` INT DEFAULT 0 COMMENT '显示顺序',
+    `icon` VARCHAR(500) COMMENT '图标URL',
+    `description` VARCHAR(500) COMMENT '字段描述',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY `uk_user_field_key` (`user_id`, `field_key`),
+    INDEX `idx_display_order` (`display_order`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='用户资料字段表';
@@ -0,0 +1 @@
+ALTER TABLE `u_user` DROP COLUMN `role`;
@@ -0,0 +1,2 @@
+-- 用户角色（user: 普通用户, admin: 管理员）
+ALTER TABLE `u_user` ADD COLUMN `role` VARCHAR(20) NOT NULL DEFAULT 'user' COMMENT '用户角色' AFTER `status`;
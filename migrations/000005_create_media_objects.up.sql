@@ -0,0 +1,18 @@
+-- 创建媒体文件表（头像和资料字段上传的图片、视频）
+CREATE TABLE IF NOT EXISTS `media_objects` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `user_id` INT NOT NULL DEFAULT 0 COMMENT '上传用户ID',
+    `purpose` VARCHAR(20) NOT NULL COMMENT '用途（avatar: 头像, profile_field: 资料字段）',
+    `storage_key` VARCHAR(500) NOT NULL COMMENT '对象存储路径',
+    `thumbnail_key` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '缩略图对象存储路径',
+    `content_type` VARCHAR(100) NOT NULL COMMENT '文件类型',
+    `size` BIGINT NOT NULL DEFAULT 0 COMMENT '文件大小（字节）',
+    `width` INT NOT NULL DEFAULT 0 COMMENT '图片宽度',
+    `height` INT NOT NULL DEFAULT 0 COMMENT '图片高度',
+    `status` VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态（pending: 等待上传, ready: 已上传）',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY `uk_storage_key` (`storage_key`),
+    INDEX `idx_user_id` (`user_id`),
+    INDEX `idx_update_time` (`update_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='媒体文件表';
@@ -0,0 +1,2 @@
+-- 用户 token 版本，递增后已签发的 token 全部失效（更换手机号、封禁等场景吊销会话）
+ALTER TABLE `u_user` ADD COLUMN `token_version` INT NOT NULL DEFAULT 0 COMMENT 'token 版本' AFTER `role`;
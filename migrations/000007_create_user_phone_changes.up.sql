@@ -0,0 +1,14 @@
+-- 创建手机号变更记录表
+CREATE TABLE IF NOT EXISTS `user_phone_changes` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `user_id` INT NOT NULL COMMENT '用户ID',
+    `old_phone` VARCHAR(20) NOT NULL COMMENT '原手机号',
+    `new_phone` VARCHAR(20) NOT NULL COMMENT '新手机号',
+    `method` VARCHAR(20) NOT NULL COMMENT '更换方式（verify: 验证原手机号, recovery: 管理员签发凭证）',
+    `operator_id` INT NOT NULL DEFAULT 0 COMMENT '操作人ID',
+    `ip` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '客户端IP',
+    `user_agent` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+    `request_id` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX `idx_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='手机号变更记录表';
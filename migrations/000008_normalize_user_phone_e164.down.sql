@@ -0,0 +1,4 @@
+-- 还原中国大陆手机号的本地格式，其他地区的号码无法还原，保持不变
+UPDATE `u_user` SET `phone` = SUBSTRING(`phone`, 4) WHERE `phone` REGEXP '^\\+861[3-9][0-9]{9}$';
+UPDATE `user_phone_changes` SET `old_phone` = SUBSTRING(`old_phone`, 4) WHERE `old_phone` REGEXP '^\\+861[3-9][0-9]{9}$';
+UPDATE `user_phone_changes` SET `new_phone` = SUBSTRING(`new_phone`, 4) WHERE `new_phone` REGEXP '^\\+861[3-9][0-9]{9}$';
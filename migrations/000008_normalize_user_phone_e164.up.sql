@@ -0,0 +1,4 @@
+-- 手机号统一为 E.164 格式，此前只支持中国大陆手机号，补充 +86 国家码
+UPDATE `u_user` SET `phone` = CONCAT('+86', `phone`) WHERE `phone` REGEXP '^1[3-9][0-9]{9}$';
+UPDATE `user_phone_changes` SET `old_phone` = CONCAT('+86', `old_phone`) WHERE `old_phone` REGEXP '^1[3-9][0-9]{9}$';
+UPDATE `user_phone_changes` SET `new_phone` = CONCAT('+86', `new_phone`) WHERE `new_phone` REGEXP '^1[3-9][0-9]{9}$';
@@ -0,0 +1,4 @@
+ALTER TABLE `u_user`
+    DROP INDEX `idx_status_expires_at`,
+    DROP COLUMN `status_expires_at`,
+    DROP COLUMN `status_reason`;
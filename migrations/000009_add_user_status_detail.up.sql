@@ -0,0 +1,5 @@
+-- 用户状态原因和到期时间，status: 1 正常，2 冻结，3 封禁，4 停用
+ALTER TABLE `u_user`
+    ADD COLUMN `status_reason` VARCHAR(255) NOT NULL DEFAULT '' COMMENT '状态原因' AFTER `status`,
+    ADD COLUMN `status_expires_at` DATETIME NULL COMMENT '冻结或封禁的到期时间' AFTER `status_reason`,
+    ADD INDEX `idx_status_expires_at` (`status`, `status_expires_at`);
@@ -0,0 +1,6 @@
+-- 存在同一手机号的已删除用户和未删除用户时无法回滚，需要先清除已删除用户
+ALTER TABLE `u_user`
+    DROP INDEX `uk_u_user_active_phone`,
+    DROP COLUMN `active_phone`,
+    DROP INDEX `idx_u_user_phone`,
+    ADD UNIQUE KEY `idx_u_user_phone` (`phone`);
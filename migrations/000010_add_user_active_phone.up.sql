@@ -0,0 +1,7 @@
+-- 手机号唯一约束只作用于未删除的用户，注销用户的手机号可以重新注册
+-- active_phone 为未删除用户的手机号，已删除用户为 NULL，唯一索引允许多个 NULL
+ALTER TABLE `u_user`
+    ADD COLUMN `active_phone` VARCHAR(20) AS (IF(`deleted_at` IS NULL, `phone`, NULL)) STORED COMMENT '未删除用户的手机号' AFTER `phone`,
+    DROP INDEX `idx_u_user_phone`,
+    ADD INDEX `idx_u_user_phone` (`phone`),
+    ADD UNIQUE KEY `uk_u_user_active_phone` (`active_phone`);
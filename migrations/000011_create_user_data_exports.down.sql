@@ -0,0 +1,2 @@
+-- 删除个人数据导出任务表
+DROP TABLE IF EXISTS `user_data_exports`;
@@ -0,0 +1,15 @@
+-- 创建个人数据导出任务表
+CREATE TABLE IF NOT EXISTS `user_data_exports` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `user_id` INT NOT NULL COMMENT '用户ID',
+    `status` VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT '状态（pending: 等待生成, processing: 生成中, ready: 已生成, failed: 生成失败, expired: 已过期）',
+    `storage_key` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '导出文件对象存储路径',
+    `size` BIGINT NOT NULL DEFAULT 0 COMMENT '导出文件大小（字节）',
+    `error` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '生成失败的原因',
+    `expires_at` DATETIME NULL COMMENT '导出文件保留到期时间',
+    `completed_at` DATETIME NULL COMMENT '生成完成时间',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    `update_time` DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX `idx_user_id` (`user_id`),
+    INDEX `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='个人数据导出任务表';
@@ -0,0 +1,17 @@
+-- 创建审计日志表
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+    `id` INT PRIMARY KEY AUTO_INCREMENT,
+    `actor_id` INT NOT NULL DEFAULT 0 COMMENT '操作人ID，后台任务和命令行为 0',
+    `action` VARCHAR(50) NOT NULL COMMENT '操作，如 user.update、auth.login',
+    `target_type` VARCHAR(50) NOT NULL DEFAULT '' COMMENT '对象类型（user, profile_field_template）',
+    `target_id` INT NOT NULL DEFAULT 0 COMMENT '对象ID',
+    `changes` TEXT NULL COMMENT '变更前后的字段值（JSON）',
+    `ip` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '客户端 IP',
+    `user_agent` VARCHAR(500) NOT NULL DEFAULT '' COMMENT '客户端 User-Agent',
+    `request_id` VARCHAR(64) NOT NULL DEFAULT '' COMMENT '请求ID',
+    `create_time` DATETIME DEFAULT CURRENT_TIMESTAMP,
+    INDEX `idx_actor_id` (`actor_id`),
+    INDEX `idx_action` (`action`),
+    INDEX `idx_target` (`target_type`, `target_id`),
+    INDEX `idx_create_time` (`create_time`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='审计日志表';
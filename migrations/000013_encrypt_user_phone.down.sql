@@ -0,0 +1,9 @@
+-- 回滚前需先执行 encryption reencrypt --decrypt 将手机号还原为明文，否则密文超出列长度
+ALTER TABLE `user_phone_changes`
+    MODIFY COLUMN `old_phone` VARCHAR(20) NOT NULL COMMENT '原手机号',
+    MODIFY COLUMN `new_phone` VARCHAR(20) NOT NULL COMMENT '新手机号';
+
+ALTER TABLE `u_user`
+    DROP INDEX `idx_u_user_phone_hash`,
+    DROP COLUMN `phone_hash`,
+    MODIFY COLUMN `phone` VARCHAR(20) COMMENT '手机号（登录标识）';
@@ -0,0 +1,10 @@
+-- 手机号改为加密存储，密文每次加密结果不同，按手机号查询和唯一约束改用盲索引 phone_hash
+-- 此处只新增盲索引列，历史数据的盲索引由 000014 补齐，补齐后由 000015 将唯一约束切换到盲索引，期间原唯一约束保持生效
+ALTER TABLE `u_user`
+    MODIFY COLUMN `phone` VARCHAR(255) COMMENT '手机号（加密）',
+    ADD COLUMN `phone_hash` CHAR(64) NULL COMMENT '手机号盲索引（HMAC-SHA256）' AFTER `phone`,
+    ADD INDEX `idx_u_user_phone_hash` (`phone_hash`);
+
+ALTER TABLE `user_phone_changes`
+    MODIFY COLUMN `old_phone` VARCHAR(255) NOT NULL COMMENT '原手机号（加密）',
+    MODIFY COLUMN `new_phone` VARCHAR(255) NOT NULL COMMENT '新手机号（加密）';
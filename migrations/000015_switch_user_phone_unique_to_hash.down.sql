@@ -0,0 +1,9 @@
+-- 回滚前需先执行 encryption reencrypt --decrypt 将手机号还原为明文，否则密文超出 active_phone 的长度
+ALTER TABLE `u_user`
+    DROP INDEX `uk_u_user_active_phone_hash`,
+    DROP COLUMN `active_phone_hash`,
+    ADD INDEX `idx_u_user_phone` (`phone`);
+
+ALTER TABLE `u_user`
+    ADD COLUMN `active_phone` VARCHAR(20) AS (IF(`deleted_at` IS NULL, `phone`, NULL)) STORED COMMENT '未删除用户的手机号' AFTER `phone`,
+    ADD UNIQUE KEY `uk_u_user_active_phone` (`active_phone`);
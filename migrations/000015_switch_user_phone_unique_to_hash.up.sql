@@ -0,0 +1,10 @@
+-- 000014 已为全部历史手机号补齐盲索引，手机号唯一约束由明文手机号切换到盲索引
+-- 手机号加密后明文唯一约束不再有效，此后写入的手机号由 active_phone_hash 保证未删除用户之间不重复
+ALTER TABLE `u_user`
+    DROP INDEX `uk_u_user_active_phone`,
+    DROP COLUMN `active_phone`;
+
+ALTER TABLE `u_user`
+    DROP INDEX `idx_u_user_phone`,
+    ADD COLUMN `active_phone_hash` CHAR(64) AS (IF(`deleted_at` IS NULL, `phone_hash`, NULL)) STORED COMMENT '未删除用户的手机号盲索引' AFTER `phone_hash`,
+    ADD UNIQUE KEY `uk_u_user_active_phone_hash` (`active_phone_hash`);
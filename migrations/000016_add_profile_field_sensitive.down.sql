@@ -0,0 +1,7 @@
+-- 回滚前需先执行 encryption reencrypt --decrypt 将敏感字段的值还原为明文
+ALTER TABLE `profile_fields`
+    DROP COLUMN `is_sensitive`,
+    MODIFY COLUMN `default_value` TEXT COMMENT '默认值（JSON格式）';
+
+ALTER TABLE `profile_field_templates`
+    DROP COLUMN `is_sensitive`;
@@ -0,0 +1,15 @@
+-- 字段模板新增敏感标记，引用敏感模板的用户资料字段的值加密存储
+-- 预设的联系方式和真实姓名模板标记为敏感，已有的资料字段按模板同步标记，执行后需运行 encryption reencrypt 加密已有的值
+ALTER TABLE `profile_field_templates`
+    ADD COLUMN `is_sensitive` TINYINT(1) DEFAULT 0 COMMENT '是否敏感（用户资料字段的值加密存储）' AFTER `is_public`;
+
+ALTER TABLE `profile_fields`
+    ADD COLUMN `is_sensitive` TINYINT(1) DEFAULT 0 COMMENT '是否敏感（值加密存储）' AFTER `is_public`,
+    MODIFY COLUMN `default_value` TEXT COMMENT '字段的值（JSON格式，敏感字段加密存储）';
+
+UPDATE `profile_field_templates` SET `is_sensitive` = 1 WHERE `field_key` IN ('real_name', 'phone', 'wechat', 'email');
+
+UPDATE `profile_fields` `f`
+    JOIN `profile_field_templates` `t` ON `t`.`field_key` = `f`.`field_key`
+SET `f`.`is_sensitive` = `t`.`is_sensitive`
+WHERE `f`.`is_system` = 1;
@@ -0,0 +1,3 @@
+ALTER TABLE `user_data_exports`
+    DROP INDEX `uk_user_data_exports_in_progress`,
+    DROP COLUMN `in_progress_user_id`;
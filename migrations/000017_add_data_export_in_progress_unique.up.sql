@@ -0,0 +1,15 @@
+-- 同一用户同时只能有一个未完成的导出任务，由唯一约束保证，并发申请时只有一个能写入
+-- 加约束前将同一用户多余的未完成任务（保留最早的一个）标记为失败
+UPDATE `user_data_exports` e
+    JOIN (
+        SELECT `user_id`, MIN(`id`) AS `keep_id`
+        FROM `user_data_exports`
+        WHERE `status` IN ('pending', 'processing')
+        GROUP BY `user_id`
+    ) k ON e.`user_id` = k.`user_id`
+SET e.`status` = 'failed', e.`error` = '存在重复的未完成导出任务'
+WHERE e.`status` IN ('pending', 'processing') AND e.`id` > k.`keep_id`;
+
+ALTER TABLE `user_data_exports`
+    ADD COLUMN `in_progress_user_id` INT AS (IF(`status` IN ('pending', 'processing'), `user_id`, NULL)) STORED COMMENT '未完成任务的用户ID' AFTER `status`,
+    ADD UNIQUE KEY `uk_user_data_exports_in_progress` (`in_progress_user_id`);
@@ -0,0 +1,69 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/deantook/dove/pkg/migrate"
+)
+
+// backfillBatchSize 每批补齐盲索引的行数
+const backfillBatchSize = 500
+
+// backfillUserPhoneHash 为 000013 之前写入的手机号补齐盲索引（000014）
+// 盲索引需要配置中的 encryption.index_key，无法在 SQL 中计算；补齐后 000015 才能将唯一约束切换到盲索引，
+// 否则历史用户的手机号在 encryption reencrypt 执行前不受唯一约束保护
+func backfillUserPhoneHash(keyring *encryption.Keyring) migrate.Func {
+	aad := encryption.ColumnAAD("u_user", "phone")
+	return func(ctx context.Context, conn *sql.Conn) error {
+		afterID := 0
+		for {
+			rows, err := conn.QueryContext(ctx,
+				"SELECT `id`, `phone` FROM `u_user` WHERE `id` > ? AND `phone_hash` IS NULL AND `phone` IS NOT NULL AND `phone` <> '' ORDER BY `id` LIMIT ?",
+				afterID, backfillBatchSize,
+			)
+			if err != nil {
+				return fmt.Errorf("查询手机号失败: %w", err)
+			}
+
+			type phoneRow struct {
+				id    int
+				phone string
+			}
+			var batch []phoneRow
+			for rows.Next() {
+				var row phoneRow
+				if err := rows.Scan(&row.id, &row.phone); err != nil {
+					rows.Close()
+					return fmt.Errorf("读取手机号失败: %w", err)
+				}
+				batch = append(batch, row)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("读取手机号失败: %w", err)
+			}
+
+			for _, row := range batch {
+				afterID = row.id
+				// 历史数据为明文，Decrypt 原样返回
+				phone, err := keyring.Decrypt(row.phone, aad)
+				if err != nil {
+					return fmt.Errorf("解密 u_user.phone（id=%d）失败: %w", row.id, err)
+				}
+				if _, err := conn.ExecContext(ctx,
+					"UPDATE `u_user` SET `phone_hash` = ? WHERE `id` = ?",
+					keyring.BlindIndex(phone), row.id,
+				); err != nil {
+					return fmt.Errorf("更新 u_user.phone_hash（id=%d）失败: %w", row.id, err)
+				}
+			}
+
+			if len(batch) < backfillBatchSize {
+				return nil
+			}
+		}
+	}
+}
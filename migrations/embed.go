@@ -0,0 +1,39 @@
+// Package migrations 内嵌数据库迁移脚本
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/deantook/dove/pkg/migrate"
+	"gorm.io/gorm"
+)
+
+// FS 迁移脚本，文件命名格式：{版本号}_{名称}.up.sql / {版本号}_{名称}.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
+
+// SeedFS 种子数据
+//
+//go:embed seeds/*.yaml
+var SeedFS embed.FS
+
+// TemplateSeedFile 系统预设字段模板种子文件
+const TemplateSeedFile = "seeds/profile_field_templates.yaml"
+
+// NewMigrator 基于数据库连接创建迁移器
+// 需要应用密钥的数据迁移以 Go 实现，与 SQL 脚本共用版本序列
+func NewMigrator(db *gorm.DB, keyring *encryption.Keyring) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库实例失败: %w", err)
+	}
+	return migrate.New(sqlDB, FS,
+		// 盲索引列由 000013 回滚时删除，无需回滚
+		migrate.WithFunc(14, "backfill_user_phone_hash", backfillUserPhoneHash(keyring), func(context.Context, *sql.Conn) error { return nil }),
+	)
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound 记录不存在
+// 加载函数返回该错误时写入空值缓存，命中空值缓存时同样返回该错误
+var ErrNotFound = errors.New("缓存记录不存在")
+
+// negativeValue 空值缓存的占位值，JSON 编码结果不会以该字符开头
+const negativeValue = "!"
+
+// Cache 基于 Redis 的 cache-aside 缓存
+// 值以 JSON 编码存储，有效期带随机浮动；并发未命中时通过 singleflight 合并为一次加载
+// Redis 不可用时直接回源，不影响业务
+type Cache struct {
+	client      *redis.Client
+	prefix      string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	jitter      float64
+	group       singleflight.Group
+}
+
+// Options 缓存选项
+type Options struct {
+	KeyPrefix   string        // Redis 键前缀
+	TTL         time.Duration // 缓存有效期
+	NegativeTTL time.Duration // 不存在记录的缓存有效期，0 表示不缓存
+	Jitter      float64       // 有效期随机浮动比例，避免同时失效
+}
+
+// New 创建缓存，未提供 Redis 客户端时返回 nil
+func New(opts Options, client *redis.Client) *Cache {
+	if client == nil {
+		return nil
+	}
+	return &Cache{
+		client:      client,
+		prefix:      opts.KeyPrefix,
+		ttl:         opts.TTL,
+		negativeTTL: opts.NegativeTTL,
+		jitter:      opts.Jitter,
+	}
+}
+
+// Fetch 读取缓存，未命中时调用 load 加载并写入缓存
+// name 为缓存名称，用于指标统计；key 不含全局前缀
+func Fetch[T any](ctx context.Context, c *Cache, name, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	fullKey := c.prefix + key
+
+	data, err := c.client.Get(ctx, fullKey).Result()
+	switch {
+	case err == nil && data == negativeValue:
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheNegativeHit).Inc()
+		return zero, ErrNotFound
+	case err == nil:
+		var v T
+		if err := json.Unmarshal([]byte(data), &v); err == nil {
+			metrics.CacheRequests.WithLabelValues(name, metrics.CacheHit).Inc()
+			return v, nil
+		}
+		// 结构变更导致无法解码时按未命中处理，加载后覆盖旧值
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheMiss).Inc()
+	case errors.Is(err, redis.Nil):
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheMiss).Inc()
+	default:
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheError).Inc()
+		logger.FromContext(ctx).WarnContext(ctx, "读取缓存失败，直接回源",
+			slog.String("key", fullKey),
+			slog.Any("error", err),
+		)
+		return load(ctx)
+	}
+
+	// 合并后的加载由多个调用方共享，不随首个调用方取消
+	v, err, _ := c.group.Do(fullKey, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		v, err := load(ctx)
+		switch {
+		case errors.Is(err, ErrNotFound):
+			if c.negativeTTL > 0 {
+				c.set(ctx, fullKey, negativeValue, c.negativeTTL)
+			}
+		case err == nil:
+			if data, err := json.Marshal(v); err == nil {
+				c.set(ctx, fullKey, string(data), c.ttl)
+			}
+		}
+		return v, err
+	})
+	if err != nil {
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// Delete 删除缓存，key 不含全局前缀
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.prefix + key
+	}
+	if err := c.client.Del(ctx, fullKeys...).Err(); err != nil {
+		return fmt.Errorf("删除缓存失败: %w", err)
+	}
+	return nil
+}
+
+// set 写入缓存，失败时仅记录日志
+func (c *Cache) set(ctx context.Context, key, value string, ttl time.Duration) {
+	if err := c.client.Set(ctx, key, value, c.withJitter(ttl)).Err(); err != nil {
+		logger.FromContext(ctx).WarnContext(ctx, "写入缓存失败",
+			slog.String("key", key),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// withJitter 在有效期基础上增加随机浮动
+func (c *Cache) withJitter(ttl time.Duration) time.Duration {
+	if c.jitter <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Float64()*c.jitter*float64(ttl))
+}
@@ -0,0 +1,206 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+type item struct {
+	Name string `json:"name"`
+}
+
+func newTestCache(t *testing.T, opts Options) (*Cache, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return New(opts, client), mr
+}
+
+func TestNewWithoutClient(t *testing.T) {
+	if c := New(Options{TTL: time.Minute}, nil); c != nil {
+		t.Errorf("New() without client = %v, want nil", c)
+	}
+}
+
+func TestFetchHitAndMiss(t *testing.T) {
+	c, mr := newTestCache(t, Options{KeyPrefix: "dove:", TTL: time.Minute})
+	ctx := context.Background()
+
+	var loads int
+	load := func(ctx context.Context) (*item, error) {
+		loads++
+		return &item{Name: "a"}, nil
+	}
+	for range 3 {
+		got, err := Fetch(ctx, c, "item", "item:1", load)
+		if err != nil || got.Name != "a" {
+			t.Fatalf("Fetch() = %+v, %v, want a", got, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("load called %d times, want 1", loads)
+	}
+	if got, _ := mr.Get("dove:item:1"); got != `{"name":"a"}` {
+		t.Errorf("cached value = %q, want JSON with key prefix", got)
+	}
+	if ttl := mr.TTL("dove:item:1"); ttl != time.Minute {
+		t.Errorf("TTL = %v, want %v", ttl, time.Minute)
+	}
+
+	if err := c.Delete(ctx, "item:1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if mr.Exists("dove:item:1") {
+		t.Error("key still exists after Delete()")
+	}
+	if _, err := Fetch(ctx, c, "item", "item:1", load); err != nil || loads != 2 {
+		t.Errorf("Fetch() after Delete() loads = %d, %v, want reload", loads, err)
+	}
+}
+
+func TestFetchNegativeCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		wantLoads   int
+	}{
+		{"缓存不存在的记录", 10 * time.Second, 1},
+		{"未配置空值缓存有效期时不缓存", 0, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, mr := newTestCache(t, Options{TTL: time.Minute, NegativeTTL: tt.negativeTTL})
+			var loads int
+			load := func(ctx context.Context) (*item, error) {
+				loads++
+				return nil, ErrNotFound
+			}
+			for range 3 {
+				if _, err := Fetch(context.Background(), c, "item", "item:404", load); !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Fetch() error = %v, want ErrNotFound", err)
+				}
+			}
+			if loads != tt.wantLoads {
+				t.Errorf("load called %d times, want %d", loads, tt.wantLoads)
+			}
+			if tt.negativeTTL > 0 {
+				if ttl := mr.TTL("item:404"); ttl != tt.negativeTTL {
+					t.Errorf("negative TTL = %v, want %v", ttl, tt.negativeTTL)
+				}
+			} else if mr.Exists("item:404") {
+				t.Error("negative value cached without negative TTL")
+			}
+		})
+	}
+}
+
+func TestFetchDoesNotCacheErrors(t *testing.T) {
+	c, mr := newTestCache(t, Options{TTL: time.Minute, NegativeTTL: time.Minute})
+	loadErr := errors.New("db down")
+	if _, err := Fetch(context.Background(), c, "item", "item:1", func(ctx context.Context) (*item, error) {
+		return nil, loadErr
+	}); !errors.Is(err, loadErr) {
+		t.Fatalf("Fetch() error = %v, want %v", err, loadErr)
+	}
+	if mr.Exists("item:1") {
+		t.Error("load error was cached")
+	}
+}
+
+func TestFetchCollapsesConcurrentMisses(t *testing.T) {
+	c, _ := newTestCache(t, Options{TTL: time.Minute})
+	const n = 20
+
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (*item, error) {
+		loads.Add(1)
+		<-release
+		return &item{Name: "a"}, nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := Fetch(context.Background(), c, "item", "item:1", load)
+			if err == nil && got.Name != "a" {
+				err = errors.New("unexpected value " + got.Name)
+			}
+			errs <- err
+		}()
+	}
+	// 等待首个加载开始，其余调用方在 singleflight 中等待
+	for loads.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Fetch() error = %v", err)
+		}
+	}
+	if got := loads.Load(); got != 1 {
+		t.Errorf("load called %d times, want 1", got)
+	}
+}
+
+func TestFetchFallsBackWhenRedisDown(t *testing.T) {
+	c, mr := newTestCache(t, Options{TTL: time.Minute})
+	mr.Close()
+
+	var loads int
+	for range 2 {
+		got, err := Fetch(context.Background(), c, "item", "item:1", func(ctx context.Context) (*item, error) {
+			loads++
+			return &item{Name: "a"}, nil
+		})
+		if err != nil || got.Name != "a" {
+			t.Fatalf("Fetch() = %+v, %v, want a", got, err)
+		}
+	}
+	if loads != 2 {
+		t.Errorf("load called %d times, want 2", loads)
+	}
+	if err := c.Delete(context.Background(), "item:1"); err == nil {
+		t.Error("Delete() error = nil, want error when redis is down")
+	}
+}
+
+func TestFetchReloadsUndecodableValue(t *testing.T) {
+	c, mr := newTestCache(t, Options{TTL: time.Minute})
+	mr.Set("item:1", `{"name":`)
+
+	got, err := Fetch(context.Background(), c, "item", "item:1", func(ctx context.Context) (*item, error) {
+		return &item{Name: "a"}, nil
+	})
+	if err != nil || got.Name != "a" {
+		t.Fatalf("Fetch() = %+v, %v, want a", got, err)
+	}
+	if v, _ := mr.Get("item:1"); v != `{"name":"a"}` {
+		t.Errorf("cached value = %q, want overwritten", v)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	c := &Cache{jitter: 0.1}
+	for range 100 {
+		if got := c.withJitter(time.Minute); got < time.Minute || got > time.Minute+6*time.Second {
+			t.Fatalf("withJitter() = %v, want within [1m, 1m6s]", got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Conn 返回绑定上下文的数据库连接
+// 上下文中存在事务时返回事务，否则返回 db.WithContext(ctx)
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// queryTimeoutKey 语句设置中超时状态的键
+const queryTimeoutKey = "dove:query_timeout"
+
+// queryTimeout 语句执行前的上下文和超时取消函数
+type queryTimeout struct {
+	parent context.Context
+	cancel context.CancelFunc
+}
+
+// registerQueryTimeout 为每条语句设置超时，上下文已有更早的截止时间时以上下文为准
+// 超时覆盖整个回调链（包括关联保存和自动事务），结束后恢复原上下文，避免 Save 等链式调用继承已取消的上下文
+// 不处理 Row/Rows，调用方需要在回调结束后继续读取结果集
+func registerQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	before := func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(queryTimeoutKey, &queryTimeout{parent: parent, cancel: cancel})
+	}
+	after := func(tx *gorm.DB) {
+		if v, ok := tx.InstanceGet(queryTimeoutKey); ok {
+			qt := v.(*queryTimeout)
+			qt.cancel()
+			tx.Statement.Context = qt.parent
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("dove:timeout_before_create", before),
+		cb.Create().After("*").Register("dove:timeout_after_create", after),
+		cb.Query().Before("*").Register("dove:timeout_before_query", before),
+		cb.Query().After("*").Register("dove:timeout_after_query", after),
+		cb.Update().Before("*").Register("dove:timeout_before_update", before),
+		cb.Update().After("*").Register("dove:timeout_after_update", after),
+		cb.Delete().Before("*").Register("dove:timeout_before_delete", before),
+		cb.Delete().After("*").Register("dove:timeout_after_delete", after),
+		cb.Raw().Before("*").Register("dove:timeout_before_raw", before),
+		cb.Raw().After("*").Register("dove:timeout_after_raw", after),
+	)
+}
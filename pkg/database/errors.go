@@ -0,0 +1,20 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// errDupEntry 唯一索引冲突的 MySQL 错误码
+const errDupEntry = 1062 // ER_DUP_ENTRY
+
+// IsDuplicateKey 判断错误是否为唯一索引冲突
+func IsDuplicateKey(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errDupEntry
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/deantook/dove/pkg/logger"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// slogLogger GORM 日志适配器
+// 通过 slog 输出结构化日志，自动附加请求 ID，记录慢查询，默认不输出 SQL 参数值
+type slogLogger struct {
+	level         gormLogger.LogLevel
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// NewLogger 创建 GORM 日志适配器
+func NewLogger(level gormLogger.LogLevel, slowThreshold time.Duration, logParams bool) gormLogger.Interface {
+	return &slogLogger{
+		level:         level,
+		slowThreshold: slowThreshold,
+		logParams:     logParams,
+	}
+}
+
+// LogMode 实现 gormLogger.Interface
+func (l *slogLogger) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+// Info 实现 gormLogger.Interface
+func (l *slogLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Info {
+		logger.FromContext(ctx).InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn 实现 gormLogger.Interface
+func (l *slogLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Warn {
+		logger.FromContext(ctx).WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error 实现 gormLogger.Interface
+func (l *slogLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormLogger.Error {
+		logger.FromContext(ctx).ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace 实现 gormLogger.Interface，记录 SQL 执行情况
+func (l *slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	log := logger.FromContext(ctx)
+
+	switch {
+	case err != nil && l.level >= gormLogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		log.LogAttrs(ctx, slog.LevelError, "SQL 执行失败",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", durationMs(elapsed)),
+			slog.String("error", err.Error()),
+		)
+
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormLogger.Warn:
+		sql, rows := fc()
+		log.LogAttrs(ctx, slog.LevelWarn, "慢查询",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", durationMs(elapsed)),
+			slog.Float64("threshold_ms", durationMs(l.slowThreshold)),
+		)
+
+	case l.level >= gormLogger.Info:
+		sql, rows := fc()
+		log.LogAttrs(ctx, slog.LevelDebug, "SQL 执行",
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", durationMs(elapsed)),
+		)
+	}
+}
+
+// ParamsFilter 实现 gorm.ParamsFilter
+// 未开启 log_params 时丢弃参数值，日志中只保留占位符
+func (l *slogLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.logParams {
+		return sql, params
+	}
+	return sql, nil
+}
+
+// durationMs 转换为毫秒
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
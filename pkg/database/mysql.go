@@ -2,29 +2,55 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/pkg/tracing"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	otelgorm "gorm.io/plugin/opentelemetry/tracing"
 )
 
 var db *gorm.DB
 
 // Init 初始化数据库连接
-func Init(cfg *config.DatabaseConfig) (*gorm.DB, error) {
+func Init(cfg *config.DatabaseConfig, logCfg *config.LogConfig, tracer *tracing.Provider) (*gorm.DB, error) {
 	dsn := cfg.GetDSN()
 
 	var err error
 	db, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: NewLogger(
+			gormLogLevel(logCfg.Level),
+			time.Duration(cfg.SlowThreshold)*time.Millisecond,
+			cfg.LogParams,
+		),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("连接数据库失败: %w", err)
 	}
 
+	// 为 SQL 语句创建 Span，参数值不写入 Span 以免泄露敏感数据
+	if tracer.Enabled() {
+		plugin := otelgorm.NewPlugin(
+			otelgorm.WithTracerProvider(tracer.TracerProvider()),
+			otelgorm.WithDBSystem("mysql"),
+			otelgorm.WithoutQueryVariables(),
+			otelgorm.WithoutMetrics(),
+		)
+		if err := db.Use(plugin); err != nil {
+			return nil, fmt.Errorf("注册数据库追踪插件失败: %w", err)
+		}
+	}
+
+	// 单条 SQL 超时，客户端断开或请求超时时同样会取消查询
+	if timeout := cfg.GetQueryTimeout(); timeout > 0 {
+		if err := registerQueryTimeout(db, timeout); err != nil {
+			return nil, fmt.Errorf("注册查询超时失败: %w", err)
+		}
+	}
+
 	// 获取底层 sql.DB 设置连接池
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -41,7 +67,7 @@ func Init(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 		return nil, fmt.Errorf("数据库连接测试失败: %w", err)
 	}
 
-	log.Println("数据库连接成功")
+	slog.Info("数据库连接成功", slog.String("host", cfg.Host), slog.String("dbname", cfg.DBName))
 
 	return db, nil
 }
@@ -50,3 +76,15 @@ func Init(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 func GetDB() *gorm.DB {
 	return db
 }
+
+// gormLogLevel 将日志级别转换为 GORM 日志级别，debug 级别时打印全部 SQL
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case "debug":
+		return logger.Info
+	case "error":
+		return logger.Error
+	default:
+		return logger.Warn
+	}
+}
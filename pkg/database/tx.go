@@ -0,0 +1,172 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/pkg/logger"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// MySQL 可重试的错误码
+const (
+	errLockDeadlock         = 1213    // ER_LOCK_DEADLOCK
+	errLockWaitTimeout      = 1205    // ER_LOCK_WAIT_TIMEOUT
+	sqlStateSerializeFailed = "40001" // 序列化失败
+)
+
+// txKey 上下文中事务的键
+type txKey struct{}
+
+// txState 上下文中的事务状态
+type txState struct {
+	tx      *gorm.DB
+	managed bool // 由 TxManager 开启，提交后执行 afterCommit
+
+	mu          sync.Mutex
+	afterCommit []func(ctx context.Context)
+}
+
+// WithTx 将外部管理的事务写入上下文，仓储通过 Conn 获取连接时自动使用该事务
+// 外部事务的提交时机未知，AfterCommit 注册的函数会立即执行
+func WithTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, &txState{tx: tx})
+}
+
+// TxFromContext 从上下文中获取事务
+func TxFromContext(ctx context.Context) (*gorm.DB, bool) {
+	state, ok := txStateFromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	return state.tx, true
+}
+
+// txStateFromContext 从上下文中获取事务状态
+func txStateFromContext(ctx context.Context) (*txState, bool) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	return state, ok && state != nil && state.tx != nil
+}
+
+// AfterCommit 注册事务提交后执行的函数，如清除缓存、发送通知
+// 上下文中没有由 TxManager 开启的事务时立即执行；事务回滚时不执行
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	state, ok := txStateFromContext(ctx)
+	if !ok || !state.managed {
+		fn(ctx)
+		return
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	state.afterCommit = append(state.afterCommit, fn)
+}
+
+// TxManager 事务管理器
+// 在事务中执行函数，事务通过上下文传递给仓储；嵌套调用使用保存点，
+// 最外层事务遇到死锁或锁等待超时时按指数退避重试
+type TxManager struct {
+	db         *gorm.DB
+	maxRetries int
+	backoff    time.Duration
+}
+
+// NewTxManager 创建事务管理器
+func NewTxManager(db *gorm.DB, cfg *config.DatabaseConfig) *TxManager {
+	return &TxManager{
+		db:         db,
+		maxRetries: cfg.TxMaxRetries,
+		backoff:    cfg.GetTxRetryBackoff(),
+	}
+}
+
+// Transaction 在事务中执行 fn，fn 返回错误或发生 panic 时回滚
+// 上下文中已存在事务时在保存点中执行，fn 失败只回滚到保存点；
+// 重试时 fn 会被再次调用，因此 fn 中不应有数据库以外的副作用，需要时使用 AfterCommit
+func (m *TxManager) Transaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	if parent, ok := txStateFromContext(ctx); ok {
+		return m.savepoint(ctx, parent, fn)
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, fn, opts...)
+		if err == nil || !IsRetryable(err) || attempt >= m.maxRetries {
+			return err
+		}
+
+		delay := m.retryDelay(attempt)
+		logger.FromContext(ctx).WarnContext(ctx, "事务冲突，准备重试",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+			slog.Any("error", err),
+		)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// run 开启最外层事务执行 fn，提交后执行 AfterCommit 注册的函数
+func (m *TxManager) run(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	state := &txState{managed: true}
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	}, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range state.afterCommit {
+		hook(ctx)
+	}
+	return nil
+}
+
+// savepoint 在保存点中执行 fn，成功时将 AfterCommit 注册的函数合并到外层事务
+func (m *TxManager) savepoint(ctx context.Context, parent *txState, fn func(ctx context.Context) error) error {
+	child := &txState{managed: parent.managed}
+	err := parent.tx.Transaction(func(tx *gorm.DB) error {
+		child.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, child))
+	})
+	if err != nil {
+		return err
+	}
+
+	if !parent.managed {
+		for _, hook := range child.afterCommit {
+			hook(ctx)
+		}
+		return nil
+	}
+	parent.mu.Lock()
+	defer parent.mu.Unlock()
+	parent.afterCommit = append(parent.afterCommit, child.afterCommit...)
+	return nil
+}
+
+// retryDelay 计算第 attempt 次重试前的等待时间，指数退避并增加随机抖动
+func (m *TxManager) retryDelay(attempt int) time.Duration {
+	delay := m.backoff << attempt
+	return delay + time.Duration(rand.Int64N(int64(delay)/2+1))
+}
+
+// IsRetryable 判断错误是否为可重试的事务冲突（死锁、锁等待超时、序列化失败）
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errLockDeadlock ||
+		mysqlErr.Number == errLockWaitTimeout ||
+		string(mysqlErr.SQLState[:]) == sqlStateSerializeFailed
+}
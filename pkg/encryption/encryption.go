@@ -0,0 +1,225 @@
+// Package encryption 敏感字段加密
+//
+// 使用 AES-256-GCM 信封加密：每个值使用随机生成的数据密钥加密，数据密钥再由配置中的主密钥加密，
+// 两者与主密钥 ID 一起保存在密文中（enc:v1:<密钥 ID>:<base64>）。轮换主密钥后旧数据仍可用旧密钥解密，
+// 重新加密时只需替换加密后的数据密钥。加密值不能用于查询，需要按值查询的字段另存 HMAC 盲索引
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 密文格式
+const (
+	prefix  = "enc:"
+	version = "v1"
+	keySize = 32 // AES-256
+)
+
+var (
+	// ErrUnknownKey 密文使用的密钥 ID 不在配置中
+	ErrUnknownKey = errors.New("加密密钥不存在")
+	// ErrMalformed 密文格式错误或已被篡改
+	ErrMalformed = errors.New("密文格式错误")
+)
+
+// Keyring 主密钥集合
+// 使用 active 密钥加密新数据，其余密钥只用于解密轮换前写入的数据
+type Keyring struct {
+	activeID string
+	keys     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// Options 主密钥选项，密钥均为 base64 编码的 32 字节
+type Options struct {
+	ActiveKey string            // 加密新数据使用的密钥 ID
+	Keys      map[string]string // 密钥 ID 到密钥，ID 不区分大小写
+	IndexKey  string            // 盲索引（HMAC-SHA256）密钥
+}
+
+// New 创建主密钥集合
+func New(opts Options) (*Keyring, error) {
+	k := &Keyring{
+		activeID: strings.ToLower(opts.ActiveKey),
+		keys:     make(map[string]cipher.AEAD, len(opts.Keys)),
+	}
+	for id, encoded := range opts.Keys {
+		id = strings.ToLower(id)
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("密钥 %s: 密钥 ID 不能包含冒号", id)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("密钥 %s: %w", id, err)
+		}
+		aead, err := newAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		k.keys[id] = aead
+	}
+	if _, ok := k.keys[k.activeID]; !ok {
+		return nil, fmt.Errorf("active 密钥 %q: %w", opts.ActiveKey, ErrUnknownKey)
+	}
+
+	indexKey, err := decodeKey(opts.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("盲索引密钥: %w", err)
+	}
+	k.indexKey = indexKey
+	return k, nil
+}
+
+// ActiveKeyID 返回加密新数据使用的密钥 ID
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeID
+}
+
+// Encrypt 使用 active 密钥加密，aad 为附加认证数据（如表名和列名），解密时必须一致
+// 空字符串不加密
+func (k *Keyring) Encrypt(plaintext, aad string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrapped, err := seal(k.keys[k.activeID], dataKey, []byte(k.activeID))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataAEAD, []byte(plaintext), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+
+	payload := append(wrapped, sealed...)
+	return prefix + version + ":" + k.activeID + ":" + base64.RawStdEncoding.EncodeToString(payload), nil
+}
+
+// Decrypt 解密 Encrypt 生成的密文，未加密的值原样返回，便于迁移前写入的明文数据
+func (k *Keyring) Decrypt(value, aad string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix+version+":"), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+	masterAEAD, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	payload, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	wrappedSize := masterAEAD.NonceSize() + keySize + masterAEAD.Overhead()
+	if len(payload) < wrappedSize {
+		return "", ErrMalformed
+	}
+	dataKey, err := open(masterAEAD, payload[:wrappedSize], []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataAEAD, payload[wrappedSize:], []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// KeyID 返回密文使用的密钥 ID，未加密的值返回空字符串
+func KeyID(value string) string {
+	if !IsEncrypted(value) {
+		return ""
+	}
+	keyID, _, _ := strings.Cut(strings.TrimPrefix(value, prefix+version+":"), ":")
+	return keyID
+}
+
+// NeedsReencrypt 判断值是否需要使用 active 密钥重新加密（明文或使用其他密钥加密）
+func (k *Keyring) NeedsReencrypt(value string) bool {
+	return value != "" && KeyID(value) != k.activeID
+}
+
+// BlindIndex 计算值的盲索引（HMAC-SHA256 十六进制），相同的值得到相同的结果，用于等值查询和唯一约束
+// 空字符串返回空字符串
+func (k *Keyring) BlindIndex(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted 判断值是否为 Encrypt 生成的密文
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix+version+":")
+}
+
+// decodeKey 解码 base64 编码的 32 字节密钥
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.New("密钥必须是 base64 编码")
+	}
+	if len(key) != keySize {
+		return nil, fmt.Errorf("密钥长度必须是 %d 字节，当前为 %d 字节", keySize, len(key))
+	}
+	return key, nil
+}
+
+// newAEAD 创建 AES-GCM 加密器
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 加密，返回随机 nonce 和密文
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+// open 解密 seal 的结果
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], aad)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,169 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testKey 生成测试用的 base64 编码 32 字节密钥
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), keySize)))
+}
+
+func newTestKeyring(t *testing.T, active string, keys map[string]string) *Keyring {
+	t.Helper()
+	k, err := New(Options{ActiveKey: active, Keys: keys, IndexKey: testKey('i')})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return k
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	k := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	aad := ColumnAAD("u_user", "phone")
+
+	tests := []string{"+8613800138000", "张三", strings.Repeat("x", 4096)}
+	for _, plaintext := range tests {
+		encrypted, err := k.Encrypt(plaintext, aad)
+		if err != nil {
+			t.Fatalf("Encrypt() error = %v", err)
+		}
+		if !IsEncrypted(encrypted) || KeyID(encrypted) != "v1" || strings.Contains(encrypted, plaintext) {
+			t.Errorf("Encrypt(%q) = %q, want ciphertext with key v1", plaintext, encrypted)
+		}
+		got, err := k.Decrypt(encrypted, aad)
+		if err != nil {
+			t.Fatalf("Decrypt() error = %v", err)
+		}
+		if got != plaintext {
+			t.Errorf("Decrypt() = %q, want %q", got, plaintext)
+		}
+	}
+}
+
+func TestEncryptUsesRandomDataKey(t *testing.T) {
+	k := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	a, _ := k.Encrypt("same", "t.c")
+	b, _ := k.Encrypt("same", "t.c")
+	if a == b {
+		t.Error("Encrypt() returned identical ciphertexts for the same plaintext")
+	}
+}
+
+func TestEncryptDecryptEmptyAndPlaintext(t *testing.T) {
+	k := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	if got, err := k.Encrypt("", "t.c"); err != nil || got != "" {
+		t.Errorf("Encrypt(\"\") = %q, %v, want empty", got, err)
+	}
+	if got, err := k.Decrypt("+8613800138000", "t.c"); err != nil || got != "+8613800138000" {
+		t.Errorf("Decrypt(plaintext) = %q, %v, want plaintext unchanged", got, err)
+	}
+}
+
+func TestDecryptAfterRotation(t *testing.T) {
+	old := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	encrypted, err := old.Encrypt("+8613800138000", "u_user.phone")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	rotated := newTestKeyring(t, "v2", map[string]string{"v1": testKey('a'), "v2": testKey('b')})
+	got, err := rotated.Decrypt(encrypted, "u_user.phone")
+	if err != nil || got != "+8613800138000" {
+		t.Fatalf("Decrypt() with old key = %q, %v", got, err)
+	}
+	if !rotated.NeedsReencrypt(encrypted) {
+		t.Error("NeedsReencrypt() = false for value encrypted with old key")
+	}
+
+	reencrypted, err := rotated.Encrypt(got, "u_user.phone")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if KeyID(reencrypted) != "v2" || rotated.NeedsReencrypt(reencrypted) {
+		t.Errorf("Encrypt() after rotation used key %q, want v2", KeyID(reencrypted))
+	}
+}
+
+func TestDecryptFailures(t *testing.T) {
+	k := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	encrypted, err := k.Encrypt("+8613800138000", "u_user.phone")
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	// 同一密钥 ID 对应不同的密钥
+	wrongKey := newTestKeyring(t, "v1", map[string]string{"v1": testKey('b')})
+	withoutKey := newTestKeyring(t, "v2", map[string]string{"v2": testKey('b')})
+
+	tests := []struct {
+		name    string
+		keyring *Keyring
+		value   string
+		aad     string
+		wantErr error
+	}{
+		{"附加认证数据不一致", k, encrypted, "user_phone_changes.old_phone", nil},
+		{"密钥错误", wrongKey, encrypted, "u_user.phone", nil},
+		{"密钥不存在", withoutKey, encrypted, "u_user.phone", ErrUnknownKey},
+		{"缺少密钥 ID", k, "enc:v1:abc", "u_user.phone", ErrMalformed},
+		{"不是 base64", k, "enc:v1:v1:!!!", "u_user.phone", ErrMalformed},
+		{"密文被截断", k, encrypted[:len(encrypted)-20], "u_user.phone", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keyring.Decrypt(tt.value, tt.aad)
+			if err == nil {
+				t.Fatalf("Decrypt() = %q, want error", got)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Decrypt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlindIndex(t *testing.T) {
+	k := newTestKeyring(t, "v1", map[string]string{"v1": testKey('a')})
+	// 轮换主密钥不影响盲索引
+	rotated := newTestKeyring(t, "v2", map[string]string{"v1": testKey('a'), "v2": testKey('b')})
+
+	index := k.BlindIndex("+8613800138000")
+	if len(index) != 64 {
+		t.Errorf("BlindIndex() = %q, want 64 hex characters", index)
+	}
+	if got := k.BlindIndex("+8613800138000"); got != index {
+		t.Errorf("BlindIndex() is not stable: %q != %q", got, index)
+	}
+	if got := rotated.BlindIndex("+8613800138000"); got != index {
+		t.Errorf("BlindIndex() changed after key rotation: %q != %q", got, index)
+	}
+	if got := k.BlindIndex("+8613800138001"); got == index {
+		t.Error("BlindIndex() returned the same index for different values")
+	}
+	if got := k.BlindIndex(""); got != "" {
+		t.Errorf("BlindIndex(\"\") = %q, want empty", got)
+	}
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"active 密钥不存在", Options{ActiveKey: "v2", Keys: map[string]string{"v1": testKey('a')}, IndexKey: testKey('i')}},
+		{"密钥长度错误", Options{ActiveKey: "v1", Keys: map[string]string{"v1": base64.StdEncoding.EncodeToString([]byte("short"))}, IndexKey: testKey('i')}},
+		{"密钥 ID 包含冒号", Options{ActiveKey: "v:1", Keys: map[string]string{"v:1": testKey('a')}, IndexKey: testKey('i')}},
+		{"缺少盲索引密钥", Options{ActiveKey: "v1", Keys: map[string]string{"v1": testKey('a')}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opts); err == nil {
+				t.Error("New() error = nil, want error")
+			}
+		})
+	}
+}
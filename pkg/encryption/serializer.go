@@ -0,0 +1,82 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// GORM 序列化器名称
+const (
+	SerializerName          = "encrypted" // 模型字段使用 gorm:"serializer:encrypted" 加密存储
+	SensitiveSerializerName = "sensitive" // 模型字段使用 gorm:"serializer:sensitive"，同一行的 SensitiveFlagColumn 为 true 时加密存储
+)
+
+// SensitiveFlagColumn 按行决定是否加密的标记列
+const SensitiveFlagColumn = "is_sensitive"
+
+// serializer 加密字段的 GORM 序列化器，以"表名.列名"作为附加认证数据，密文不能复制到其他列使用
+type serializer struct {
+	keyring *Keyring
+}
+
+// sensitiveSerializer 按行加密的 GORM 序列化器，只加密标记为敏感的行，读取时明文原样返回
+type sensitiveSerializer struct {
+	serializer
+}
+
+// RegisterSerializer 注册 GORM 加密序列化器，需在首次查询使用加密字段的模型之前调用
+func RegisterSerializer(k *Keyring) {
+	schema.RegisterSerializer(SerializerName, &serializer{keyring: k})
+	schema.RegisterSerializer(SensitiveSerializerName, &sensitiveSerializer{serializer{keyring: k}})
+}
+
+// ColumnAAD 列的附加认证数据
+func ColumnAAD(table, column string) string {
+	return table + "." + column
+}
+
+// Scan 解密数据库中的值
+func (s *serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("加密字段 %s 的类型不支持: %T", field.Name, dbValue)
+	}
+
+	plaintext, err := s.keyring.Decrypt(value, ColumnAAD(field.Schema.Table, field.DBName))
+	if err != nil {
+		return fmt.Errorf("解密字段 %s 失败: %w", field.Name, err)
+	}
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+// Value 加密写入数据库的值
+func (s *serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("加密字段 %s 的类型不支持: %T", field.Name, fieldValue)
+	}
+	return s.keyring.Encrypt(plaintext, ColumnAAD(field.Schema.Table, field.DBName))
+}
+
+// Value 同一行的敏感标记为 true 时加密写入数据库的值，否则原样写入
+func (s *sensitiveSerializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	flag := field.Schema.LookUpField(SensitiveFlagColumn)
+	if flag == nil {
+		return nil, fmt.Errorf("加密字段 %s 所在的模型缺少 %s 列", field.Name, SensitiveFlagColumn)
+	}
+	sensitive, _ := flag.ValueOf(ctx, dst)
+	if sensitive, ok := sensitive.(bool); !ok || !sensitive {
+		return fieldValue, nil
+	}
+	return s.serializer.Value(ctx, field, dst, fieldValue)
+}
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// 业务错误码
+// 1xxx 通用错误，2xxx 用户相关，3xxx 资料字段模板相关，4xxx 媒体文件相关
+const (
+	CodeInvalidParams   = 1001 // 参数错误
+	CodeUnauthorized    = 1002 // 未认证
+	CodeForbidden       = 1003 // 无权限
+	CodeNotFound        = 1004 // 资源不存在
+	CodeConflict        = 1005 // 资源冲突
+	CodeInternal        = 1006 // 服务器内部错误
+	CodeTooManyRequests = 1007 // 请求过于频繁
+
+	CodeUserFrozen      = 2001 // 账号已冻结
+	CodeUserBanned      = 2002 // 账号已封禁
+	CodeUserDeactivated = 2003 // 账号已停用
+
+	CodeTemplateBundleInvalid = 3001 // 字段模板导入文件校验失败
+
+	CodeMediaTypeUnsupported = 4001 // 文件类型不支持
+	CodeMediaTooLarge        = 4002 // 文件过大
+	CodeMediaInvalid         = 4003 // 文件内容无效或与声明不一致
+)
+
+// AppError 业务错误
+// Service 层返回该类型错误，由 response.Error 转换为对应的 HTTP 状态码和业务错误码
+type AppError struct {
+	HTTPStatus int    // HTTP 状态码
+	Code       int    // 业务错误码
+	Message    string // 错误消息
+	Detail     string // 错误详情
+	Err        error  // 原始错误
+}
+
+// New 创建业务错误
+func New(httpStatus, code int, message string) *AppError {
+	return &AppError{
+		HTTPStatus: httpStatus,
+		Code:       code,
+		Message:    message,
+	}
+}
+
+// Error 实现 error 接口
+func (e *AppError) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%s: %s", e.Message, e.Detail)
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+// Unwrap 返回原始错误
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// WithDetail 返回附带错误详情的副本
+func (e *AppError) WithDetail(detail string) *AppError {
+	clone := *e
+	clone.Detail = detail
+	return &clone
+}
+
+// Wrap 返回包装原始错误的副本
+func (e *AppError) Wrap(err error) *AppError {
+	clone := *e
+	clone.Err = err
+	return &clone
+}
+
+// BadRequest 创建参数错误
+func BadRequest(message string) *AppError {
+	return New(http.StatusBadRequest, CodeInvalidParams, message)
+}
+
+// Unauthorized 创建未认证错误
+func Unauthorized(message string) *AppError {
+	return New(http.StatusUnauthorized, CodeUnauthorized, message)
+}
+
+// Forbidden 创建无权限错误
+func Forbidden(message string) *AppError {
+	return New(http.StatusForbidden, CodeForbidden, message)
+}
+
+// NotFound 创建资源不存在错误
+func NotFound(message string) *AppError {
+	return New(http.StatusNotFound, CodeNotFound, message)
+}
+
+// Conflict 创建资源冲突错误
+func Conflict(message string) *AppError {
+	return New(http.StatusConflict, CodeConflict, message)
+}
+
+// TooManyRequests 创建请求过于频繁错误
+func TooManyRequests(message string) *AppError {
+	return New(http.StatusTooManyRequests, CodeTooManyRequests, message)
+}
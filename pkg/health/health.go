@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// 检查状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ErrShuttingDown 服务正在关闭
+var ErrShuttingDown = errors.New("服务正在关闭")
+
+// CheckFunc 依赖检查函数，返回 nil 表示依赖可用
+type CheckFunc func(ctx context.Context) error
+
+// CheckResult 单个依赖的检查结果
+type CheckResult struct {
+	Status    string  `json:"status" example:"up"`
+	LatencyMs float64 `json:"latency_ms" example:"1.25"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status string                 `json:"status" example:"up"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Ready 是否就绪
+func (r *Report) Ready() bool {
+	return r.Status == StatusUp
+}
+
+// check 已注册的检查项
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker 存活和就绪检查
+// 新的子系统可以通过 Register 注册自己的依赖检查
+type Checker struct {
+	mu           sync.RWMutex
+	checks       []check
+	timeout      time.Duration
+	shuttingDown atomic.Bool
+}
+
+// NewChecker 创建检查器，并注册 MySQL 和 Redis 检查
+func NewChecker(cfg *config.HealthConfig, db *gorm.DB, redisClient *redis.Client) *Checker {
+	c := &Checker{timeout: cfg.GetCheckTimeout()}
+	if db != nil {
+		c.Register("mysql", func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		})
+	}
+	if redisClient != nil {
+		c.Register("redis", func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		})
+	}
+	return c
+}
+
+// Register 注册依赖检查，同名检查会被替换
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := range c.checks {
+		if c.checks[i].name == name {
+			c.checks[i].fn = fn
+			return
+		}
+	}
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown 标记服务正在关闭，之后的就绪检查均返回未就绪，让负载均衡先摘除流量
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// ShuttingDown 是否正在关闭
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Readiness 并发执行所有检查，每项检查单独计时和超时
+func (c *Checker) Readiness(ctx context.Context) *Report {
+	c.mu.RLock()
+	checks := make([]check, len(c.checks))
+	copy(checks, c.checks)
+	c.mu.RUnlock()
+
+	report := &Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(checks)+1),
+	}
+
+	if c.ShuttingDown() {
+		report.Status = StatusDown
+		report.Checks["shutdown"] = CheckResult{Status: StatusDown, Error: ErrShuttingDown.Error()}
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, chk := range checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			result := c.run(ctx, chk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[chk.name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run 执行单项检查
+func (c *Checker) run(ctx context.Context, chk check) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() {
+		result.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+		if r := recover(); r != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("检查异常: %v", r)
+		}
+	}()
+
+	if err := chk.fn(ctx); err != nil {
+		return CheckResult{Status: StatusDown, Error: err.Error()}
+	}
+	return CheckResult{Status: StatusUp}
+}
@@ -1,44 +1,66 @@
 package jwt
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var (
-	// JWTSecret JWT 密钥（生产环境应从配置中读取）
-	JWTSecret = []byte("dove-secret-key-change-in-production")
-	// TokenExpireDuration token 过期时间
-	TokenExpireDuration = 7 * 24 * time.Hour // 7天
-)
-
 // Claims JWT Claims
 type Claims struct {
-	UserID int `json:"user_id"`
+	UserID  int    `json:"user_id"`
+	Role    string `json:"role,omitempty"`
+	Version int    `json:"ver,omitempty"` // 用户的 token 版本，用户吊销会话后版本递增，旧 token 失效
 	jwt.RegisteredClaims
 }
 
+// Options JWT 选项
+type Options struct {
+	Secret string        // HS256 签名密钥
+	Expire time.Duration // token 有效期
+}
+
+// Manager JWT 签发和校验
+type Manager struct {
+	secret []byte
+	expire time.Duration
+}
+
+// New 创建 JWT 签发和校验器，密钥为空时返回错误
+func New(opts Options) (*Manager, error) {
+	if opts.Secret == "" {
+		return nil, errors.New("jwt 签名密钥不能为空")
+	}
+	return &Manager{
+		secret: []byte(opts.Secret),
+		expire: opts.Expire,
+	}, nil
+}
+
 // GenerateToken 生成 JWT token
-func GenerateToken(userID int) (string, error) {
+func (m *Manager) GenerateToken(userID int, role string, version int) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		UserID: userID,
+		UserID:  userID,
+		Role:    role,
+		Version: version,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpireDuration)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.expire)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(JWTSecret)
+	return token.SignedString(m.secret)
 }
 
-// ParseToken 解析 JWT token
-func ParseToken(tokenString string) (*Claims, error) {
+// ParseToken 解析 JWT token，只接受 HS256 签名
+func (m *Manager) ParseToken(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return JWTSecret, nil
-	})
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, err
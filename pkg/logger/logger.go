@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/deantook/dove/internal/config"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// level 全局日志级别
+var level = new(slog.LevelVar)
+
+// Init 根据配置初始化全局 slog 日志，同时接管标准库 log 的输出
+func Init(cfg *config.LogConfig) *slog.Logger {
+	return InitWithWriter(cfg, os.Stdout)
+}
+
+// InitWithWriter 根据配置初始化全局 slog 日志并输出到指定 Writer
+func InitWithWriter(cfg *config.LogConfig, w io.Writer) *slog.Logger {
+	level.Set(ParseLevel(cfg.Level))
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	if strings.EqualFold(cfg.Format, "text") {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+
+	l := slog.New(&contextHandler{Handler: handler})
+	slog.SetDefault(l)
+	return l
+}
+
+// ParseLevel 解析日志级别，无法识别时返回 info
+func ParseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// SetLevel 修改全局日志级别，已创建的 Logger 立即生效
+func SetLevel(s string) {
+	level.Set(ParseLevel(s))
+}
+
+// Level 返回当前日志级别
+func Level() slog.Level {
+	return level.Level()
+}
+
+// contextKey 上下文键
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	loggerKey
+)
+
+// WithRequestID 将请求 ID 写入上下文
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID 从上下文中获取请求 ID
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// NewContext 将 Logger 写入上下文
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext 获取上下文关联的 Logger
+// 上下文中有请求 ID 时，返回的 Logger 会带上 request_id 字段
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return slog.Default()
+	}
+	if l, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return l
+	}
+	if requestID := RequestID(ctx); requestID != "" {
+		return slog.Default().With(slog.String("request_id", requestID))
+	}
+	return slog.Default()
+}
+
+// contextHandler 在使用 *Context 方法记录日志时自动附加请求 ID 和链路追踪 ID
+type contextHandler struct {
+	slog.Handler
+	hasRequestID bool // 已通过 With 附加 request_id
+}
+
+// Handle 实现 slog.Handler
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.hasRequestID {
+		if requestID := RequestID(ctx); requestID != "" {
+			r.AddAttrs(slog.String("request_id", requestID))
+		}
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	hasRequestID := h.hasRequestID
+	for _, attr := range attrs {
+		if attr.Key == "request_id" {
+			hasRequestID = true
+		}
+	}
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs), hasRequestID: hasRequestID}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name), hasRequestID: h.hasRequestID}
+}
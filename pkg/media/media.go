@@ -0,0 +1,136 @@
+// Package media 媒体文件类型识别和缩略图生成
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"strings"
+
+	_ "image/gif" // 注册 GIF 解码器
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // 注册 WebP 解码器
+)
+
+// SniffLen 识别文件类型需要读取的字节数
+const SniffLen = 512
+
+// maxPixels 生成缩略图时允许的最大像素数，防止解码超大图片耗尽内存
+const maxPixels = 50_000_000
+
+// 支持的文件类型及扩展名
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+	"video/mp4":  ".mp4",
+	"video/webm": ".webm",
+}
+
+// ErrUnsupportedType 不支持的文件类型
+var ErrUnsupportedType = errors.New("不支持的文件类型")
+
+// DetectContentType 根据文件内容识别类型，忽略客户端声明的类型
+// 只识别支持的图片和视频类型，其余返回 ErrUnsupportedType
+func DetectContentType(head []byte) (string, error) {
+	contentType, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if _, ok := extensions[contentType]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	return contentType, nil
+}
+
+// Extension 返回文件类型对应的扩展名
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// IsImage 是否为图片类型
+func IsImage(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// IsVideo 是否为视频类型
+func IsVideo(contentType string) bool {
+	return strings.HasPrefix(contentType, "video/")
+}
+
+// Thumbnail 缩略图
+type Thumbnail struct {
+	Data        []byte
+	ContentType string
+	Width       int // 原图宽度
+	Height      int // 原图高度
+}
+
+// ImageSize 读取图片尺寸，只解析文件头
+func ImageSize(data []byte) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, fmt.Errorf("解析图片失败: %w", err)
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// MakeThumbnail 按最长边 size 等比缩放生成缩略图
+// 原图不大于 size 时不放大；PNG、GIF、WebP 输出 PNG 以保留透明通道，JPEG 输出 JPEG
+func MakeThumbnail(data []byte, size int) (*Thumbnail, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解析图片失败: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("图片尺寸过大: %dx%d", cfg.Width, cfg.Height)
+	}
+
+	src, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(h*size/w, 1)
+		} else {
+			w, h = max(w*size/h, 1), size
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+
+	var buf bytes.Buffer
+	thumb := &Thumbnail{Width: cfg.Width, Height: cfg.Height}
+	if format == "png" || format == "gif" || format == "webp" {
+		err = png.Encode(&buf, dst)
+		thumb.ContentType = "image/png"
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85})
+		thumb.ContentType = "image/jpeg"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("编码缩略图失败: %w", err)
+	}
+	thumb.Data = buf.Bytes()
+	return thumb, nil
+}
+
+// ReadAll 读取全部内容，超过 limit 字节时返回错误
+func ReadAll(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("文件超过 %d 字节", limit)
+	}
+	return data, nil
+}
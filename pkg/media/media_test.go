@@ -0,0 +1,136 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// encodeImage 生成指定尺寸和格式的测试图片
+func encodeImage(t *testing.T, format string, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, nil)
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode %s: %v", format, err)
+	}
+	return buf.Bytes()
+}
+
+func TestDetectContentType(t *testing.T) {
+	tests := []struct {
+		name    string
+		head    []byte
+		want    string
+		wantErr bool
+	}{
+		{"JPEG", encodeImage(t, "jpeg", 2, 2), "image/jpeg", false},
+		{"PNG", encodeImage(t, "png", 2, 2), "image/png", false},
+		{"GIF", encodeImage(t, "gif", 2, 2), "image/gif", false},
+		{"WebP", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "image/webp", false},
+		{"MP4", []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), "video/mp4", false},
+		{"WebM", []byte("\x1a\x45\xdf\xa3\x9f\x42\x86\x81\x01"), "video/webm", false},
+		{"HTML 伪装成图片", []byte("<html><script>alert(1)</script></html>"), "", true},
+		{"SVG", []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg"></svg>`), "", true},
+		{"纯文本", []byte("hello"), "", true},
+		{"PDF", []byte("%PDF-1.4"), "", true},
+		{"空内容", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectContentType(tt.head)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedType) {
+					t.Errorf("DetectContentType() = %q, %v, want ErrUnsupportedType", got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("DetectContentType() = %q, %v, want %q", got, err, tt.want)
+			}
+			if Extension(got) == "" {
+				t.Errorf("Extension(%q) is empty", got)
+			}
+		})
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		limit   int64
+		wantErr bool
+	}{
+		{"小于上限", 10, 11, false},
+		{"等于上限", 10, 10, false},
+		{"超过上限", 11, 10, true},
+		{"远超上限", 1 << 20, 1024, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ReadAll(strings.NewReader(strings.Repeat("x", tt.size)), tt.limit)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ReadAll() read %d bytes, want size limit error", len(data))
+				}
+				return
+			}
+			if err != nil || len(data) != tt.size {
+				t.Errorf("ReadAll() = %d bytes, %v, want %d bytes", len(data), err, tt.size)
+			}
+		})
+	}
+}
+
+func TestMakeThumbnail(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		size         int
+		wantType     string
+		wantW, wantH int
+		origW, origH int
+	}{
+		{"横图按宽缩放", encodeImage(t, "jpeg", 400, 200), 100, "image/jpeg", 100, 50, 400, 200},
+		{"竖图按高缩放", encodeImage(t, "png", 200, 400), 100, "image/png", 50, 100, 200, 400},
+		{"小图不放大", encodeImage(t, "png", 20, 10), 100, "image/png", 20, 10, 20, 10},
+		{"GIF 输出 PNG", encodeImage(t, "gif", 300, 300), 100, "image/png", 100, 100, 300, 300},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thumb, err := MakeThumbnail(tt.data, tt.size)
+			if err != nil {
+				t.Fatalf("MakeThumbnail() error = %v", err)
+			}
+			if thumb.ContentType != tt.wantType || thumb.Width != tt.origW || thumb.Height != tt.origH {
+				t.Errorf("MakeThumbnail() = %s %dx%d, want %s %dx%d", thumb.ContentType, thumb.Width, thumb.Height, tt.wantType, tt.origW, tt.origH)
+			}
+			w, h, err := ImageSize(thumb.Data)
+			if err != nil || w != tt.wantW || h != tt.wantH {
+				t.Errorf("thumbnail size = %dx%d, %v, want %dx%d", w, h, err, tt.wantW, tt.wantH)
+			}
+		})
+	}
+
+	if _, err := MakeThumbnail([]byte("not an image"), 100); err == nil {
+		t.Error("MakeThumbnail() invalid image error = nil, want error")
+	}
+}
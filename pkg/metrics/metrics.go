@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// namespace 指标命名空间
+const namespace = "dove"
+
+// HTTP 指标
+var (
+	// HTTPRequestsTotal HTTP 请求总数，按方法、路由模板和状态码统计
+	HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration HTTP 请求耗时，按方法和路由模板统计
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求耗时（秒）",
+		Buckets:   []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"method", "route"})
+
+	// HTTPRateLimited 被限流拒绝的请求数，按限流策略统计
+	HTTPRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "被限流拒绝的请求数",
+	}, []string{"policy"})
+
+	// HTTPRequestsInFlight 正在处理的 HTTP 请求数
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "正在处理的 HTTP 请求数",
+	})
+)
+
+// 缓存指标
+var (
+	// CacheRequests 缓存查询次数，按缓存名称和结果统计
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "缓存查询次数",
+	}, []string{"cache", "result"})
+)
+
+// 业务指标
+var (
+	// SMSCodesSent 发送的验证码数量
+	SMSCodesSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "sms_codes_sent_total",
+		Help:      "发送的验证码数量",
+	})
+
+	// Logins 登录次数，result 为 success 或失败原因
+	Logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "logins_total",
+		Help:      "登录次数",
+	}, []string{"result"})
+
+	// Registrations 新注册用户数量
+	Registrations = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "auth",
+		Name:      "registrations_total",
+		Help:      "新注册用户数量",
+	})
+
+	// TemplatesApplied 字段模板应用次数，result 为 ResultSuccess 或 ResultFailure
+	TemplatesApplied = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "profile",
+		Name:      "templates_applied_total",
+		Help:      "字段模板应用到用户的次数",
+	}, []string{"result"})
+
+	// MediaUploads 媒体文件上传次数，按用途和结果统计
+	MediaUploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "media",
+		Name:      "uploads_total",
+		Help:      "媒体文件上传次数",
+	}, []string{"purpose", "result"})
+
+	// MediaCollected 垃圾回收清理的媒体文件数量
+	MediaCollected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "media",
+		Name:      "collected_total",
+		Help:      "垃圾回收清理的媒体文件数量",
+	})
+)
+
+// 通用结果标签
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// 缓存查询结果
+const (
+	CacheHit         = "hit"
+	CacheNegativeHit = "negative_hit"
+	CacheMiss        = "miss"
+	CacheError       = "error"
+)
+
+// 登录结果
+const (
+	LoginSuccess      = "success"
+	LoginCodeExpired  = "code_expired"
+	LoginCodeMismatch = "code_mismatch"
+	LoginRefused      = "refused" // 账号冻结、封禁或停用
+	LoginError        = "error"
+)
+
+// NewRegistry 创建指标注册表
+// 包含 Go 运行时、进程、HTTP、业务指标，以及 GORM 连接池和 go-redis 连接池指标
+func NewRegistry(db *gorm.DB, redisClient *redis.Client) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+
+	cs := []prometheus.Collector{
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+		HTTPRequestsTotal,
+		HTTPRequestDuration,
+		HTTPRequestsInFlight,
+		HTTPRateLimited,
+		CacheRequests,
+		SMSCodesSent,
+		Logins,
+		Registrations,
+		TemplatesApplied,
+		MediaUploads,
+		MediaCollected,
+	}
+
+	if db != nil {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("获取数据库实例失败: %w", err)
+		}
+		cs = append(cs, collectors.NewDBStatsCollector(sqlDB, "mysql"))
+	}
+	if redisClient != nil {
+		cs = append(cs, newRedisPoolCollector(redisClient))
+	}
+
+	for _, c := range cs {
+		if err := registry.Register(c); err != nil {
+			return nil, fmt.Errorf("注册指标失败: %w", err)
+		}
+	}
+
+	return registry, nil
+}
+
+// Handler 返回指标注册表的 HTTP 处理器
+func Handler(registry *prometheus.Registry) http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
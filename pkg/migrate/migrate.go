@@ -0,0 +1,511 @@
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTable 默认的迁移版本表
+	DefaultTable = "schema_migrations"
+	// DefaultLockName 默认的迁移咨询锁名称
+	DefaultLockName = "dove:schema_migrations"
+	// DefaultLockTimeout 默认的获取咨询锁超时时间
+	DefaultLockTimeout = 60 * time.Second
+)
+
+var (
+	// ErrChecksumMismatch 已执行的迁移脚本被修改
+	ErrChecksumMismatch = errors.New("迁移脚本校验和不一致")
+	// ErrLockTimeout 获取迁移锁超时
+	ErrLockTimeout = errors.New("获取迁移锁超时")
+	// ErrNoDownMigration 迁移脚本缺少回滚文件
+	ErrNoDownMigration = errors.New("迁移脚本缺少回滚文件")
+)
+
+// fileNamePattern 迁移文件命名格式：{版本号}_{名称}.up.sql / {版本号}_{名称}.down.sql
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-zA-Z0-9_]+)\.(up|down)\.sql$`)
+
+// Func Go 实现的迁移步骤，用于无法用 SQL 表达的数据迁移（如需要使用应用配置中的密钥）
+// 在持有迁移锁的连接上执行
+type Func func(ctx context.Context, conn *sql.Conn) error
+
+// Migration 单个迁移
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	UpFunc   Func   // Go 迁移，与 Up 二选一
+	DownFunc Func   // Go 迁移的回滚，为空时不能回滚
+	Checksum string // up 脚本的 SHA-256，Go 迁移为名称的 SHA-256
+}
+
+// Status 迁移状态
+type Status struct {
+	Version          int64      `json:"version"`
+	Name             string     `json:"name"`
+	Applied          bool       `json:"applied"`
+	AppliedAt        *time.Time `json:"applied_at,omitempty"`
+	ChecksumMismatch bool       `json:"checksum_mismatch"` // 已执行后脚本被修改
+	Missing          bool       `json:"missing"`           // 已执行但本地没有对应脚本
+}
+
+// appliedMigration 版本表中的记录
+type appliedMigration struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Option 迁移器选项
+type Option func(*Migrator)
+
+// WithTable 设置迁移版本表名
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// WithLock 设置咨询锁名称和超时时间
+func WithLock(name string, timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockName = name
+		m.lockTimeout = timeout
+	}
+}
+
+// WithFunc 注册 Go 迁移，版本号与 SQL 脚本共用同一序列且不能重复
+func WithFunc(version int64, name string, up, down Func) Option {
+	return func(m *Migrator) {
+		sum := sha256.Sum256([]byte("func:" + name))
+		m.funcs = append(m.funcs, &Migration{
+			Version:  version,
+			Name:     name,
+			UpFunc:   up,
+			DownFunc: down,
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+}
+
+// Migrator 数据库迁移器（MySQL）
+// 迁移脚本内嵌在二进制中，执行记录保存在版本表中，多个副本之间通过 GET_LOCK 咨询锁互斥
+type Migrator struct {
+	db          *sql.DB
+	migrations  []*Migration
+	funcs       []*Migration // WithFunc 注册的 Go 迁移
+	table       string
+	lockName    string
+	lockTimeout time.Duration
+}
+
+// New 创建迁移器，从 fsys 根目录加载迁移脚本
+func New(db *sql.DB, fsys fs.FS, opts ...Option) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:          db,
+		migrations:  migrations,
+		table:       DefaultTable,
+		lockName:    DefaultLockName,
+		lockTimeout: DefaultLockTimeout,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	m.migrations, err = merge(m.migrations, m.funcs)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// merge 合并 SQL 脚本和 Go 迁移并按版本号排序
+func merge(migrations, funcs []*Migration) ([]*Migration, error) {
+	versions := make(map[int64]string, len(migrations)+len(funcs))
+	for _, migration := range migrations {
+		versions[migration.Version] = migration.Name
+	}
+	merged := slices.Clone(migrations)
+	for _, migration := range funcs {
+		if name, ok := versions[migration.Version]; ok {
+			return nil, fmt.Errorf("迁移版本 %d 存在多个名称: %s, %s", migration.Version, name, migration.Name)
+		}
+		versions[migration.Version] = migration.Name
+		merged = append(merged, migration)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Version < merged[j].Version })
+	return merged, nil
+}
+
+// Load 加载并按版本号排序迁移脚本
+func Load(fsys fs.FS) ([]*Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("读取迁移目录失败: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("迁移文件命名不合法: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("迁移文件版本号不合法: %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("读取迁移文件失败: %w", err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = migration
+		} else if migration.Name != matches[2] {
+			return nil, fmt.Errorf("迁移版本 %d 存在多个名称: %s, %s", version, migration.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("迁移版本 %d 缺少 up 脚本", migration.Version)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// Migrations 返回全部迁移
+func (m *Migrator) Migrations() []*Migration {
+	return m.migrations
+}
+
+// Up 执行未应用的迁移，steps <= 0 时执行全部
+func (m *Migrator) Up(ctx context.Context, steps int) ([]*Migration, error) {
+	var executed []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if steps > 0 && len(executed) >= steps {
+				break
+			}
+			if err := m.runUp(ctx, conn, migration); err != nil {
+				return err
+			}
+			executed = append(executed, migration)
+		}
+		return nil
+	})
+	return executed, err
+}
+
+// Down 回滚最近执行的迁移，steps <= 0 时回滚一个
+func (m *Migrator) Down(ctx context.Context, steps int) ([]*Migration, error) {
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var rolledBack []*Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if err := m.runDown(ctx, conn, migration); err != nil {
+				return err
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+	return rolledBack, err
+}
+
+// Redo 回滚并重新执行最近一次迁移
+func (m *Migrator) Redo(ctx context.Context) (*Migration, error) {
+	var redone *Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				redone = m.migrations[i]
+				break
+			}
+		}
+		if redone == nil {
+			return nil
+		}
+
+		if err := m.runDown(ctx, conn, redone); err != nil {
+			return err
+		}
+		return m.runUp(ctx, conn, redone)
+	})
+	return redone, err
+}
+
+// Status 返回全部迁移的状态
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.ChecksumMismatch = record.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+
+	for version, record := range applied {
+		if known[version] {
+			continue
+		}
+		appliedAt := record.AppliedAt
+		statuses = append(statuses, Status{
+			Version:   version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: &appliedAt,
+			Missing:   true,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// Close 关闭底层数据库连接
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// withLock 在持有咨询锁的独占连接上执行 fn
+// GET_LOCK 是会话级别的锁，因此加锁、迁移和解锁必须使用同一个连接
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %w", err)
+	}
+	defer conn.Close()
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", m.lockName, int(m.lockTimeout.Seconds())).Scan(&acquired); err != nil {
+		return fmt.Errorf("获取迁移锁失败: %w", err)
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		return ErrLockTimeout
+	}
+	defer func() {
+		// 使用独立的 context，保证调用方取消后仍能释放锁
+		if _, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", m.lockName); err != nil {
+			slog.Error("释放迁移锁失败", slog.Any("error", err))
+		}
+	}()
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+// ensureTable 创建迁移版本表
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS `%s` ("+
+		"`version` BIGINT NOT NULL PRIMARY KEY,"+
+		"`name` VARCHAR(255) NOT NULL,"+
+		"`checksum` CHAR(64) NOT NULL,"+
+		"`applied_at` DATETIME NOT NULL,"+
+		"`execution_ms` BIGINT NOT NULL DEFAULT 0"+
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COMMENT='数据库迁移版本表'", m.table))
+	if err != nil {
+		return fmt.Errorf("创建迁移版本表失败: %w", err)
+	}
+	return nil
+}
+
+// applied 查询已执行的迁移，版本表不存在时返回空
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	var exists int
+	err := conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?",
+		m.table,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("查询迁移版本表失败: %w", err)
+	}
+
+	applied := make(map[int64]appliedMigration)
+	if exists == 0 {
+		return applied, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT `version`, `name`, `checksum`, `applied_at` FROM `%s`", m.table))
+	if err != nil {
+		return nil, fmt.Errorf("查询迁移记录失败: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record appliedMigration
+		if err := rows.Scan(&record.Version, &record.Name, &record.Checksum, &record.AppliedAt); err != nil {
+			return nil, fmt.Errorf("读取迁移记录失败: %w", err)
+		}
+		applied[record.Version] = record
+	}
+	return applied, rows.Err()
+}
+
+// verify 校验已执行迁移的脚本是否被修改
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	var mismatched []string
+	for _, migration := range m.migrations {
+		record, ok := applied[migration.Version]
+		if ok && record.Checksum != migration.Checksum {
+			mismatched = append(mismatched, fmt.Sprintf("%d_%s", migration.Version, migration.Name))
+		}
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(mismatched, ", "))
+	}
+	return nil
+}
+
+// runUp 执行单个迁移并写入版本表
+// MySQL 的 DDL 会隐式提交事务，因此脚本按语句顺序执行，失败时需要人工处理
+func (m *Migrator) runUp(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	start := time.Now()
+	var err error
+	if migration.UpFunc != nil {
+		err = migration.UpFunc(ctx, conn)
+	} else {
+		err = execScript(ctx, conn, migration.Up)
+	}
+	if err != nil {
+		return fmt.Errorf("执行迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+
+	_, err = conn.ExecContext(ctx,
+		fmt.Sprintf("INSERT INTO `%s` (`version`, `name`, `checksum`, `applied_at`, `execution_ms`) VALUES (?, ?, ?, ?, ?)", m.table),
+		migration.Version, migration.Name, migration.Checksum, time.Now(), time.Since(start).Milliseconds(),
+	)
+	if err != nil {
+		return fmt.Errorf("记录迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+
+	slog.InfoContext(ctx, "迁移已执行", slog.Int64("version", migration.Version), slog.String("name", migration.Name), slog.Duration("elapsed", time.Since(start)))
+	return nil
+}
+
+// runDown 回滚单个迁移并删除版本记录
+func (m *Migrator) runDown(ctx context.Context, conn *sql.Conn, migration *Migration) error {
+	if migration.DownFunc == nil && strings.TrimSpace(migration.Down) == "" {
+		return fmt.Errorf("%w: %d_%s", ErrNoDownMigration, migration.Version, migration.Name)
+	}
+
+	start := time.Now()
+	var err error
+	if migration.DownFunc != nil {
+		err = migration.DownFunc(ctx, conn)
+	} else {
+		err = execScript(ctx, conn, migration.Down)
+	}
+	if err != nil {
+		return fmt.Errorf("回滚迁移 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM `%s` WHERE `version` = ?", m.table), migration.Version); err != nil {
+		return fmt.Errorf("删除迁移记录 %d_%s 失败: %w", migration.Version, migration.Name, err)
+	}
+
+	slog.InfoContext(ctx, "迁移已回滚", slog.Int64("version", migration.Version), slog.String("name", migration.Name), slog.Duration("elapsed", time.Since(start)))
+	return nil
+}
+
+// execScript 逐条执行脚本中的 SQL 语句
+func execScript(ctx context.Context, conn *sql.Conn, script string) error {
+	for _, stmt := range SplitStatements(script) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%w\n%s", err, stmt)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_add_index.up.sql":     file("CREATE INDEX idx ON t (c);"),
+		"000002_create_t.up.sql":      file("CREATE TABLE t (id INT);"),
+		"000002_create_t.down.sql":    file("DROP TABLE t;"),
+		"000010_add_index.down.sql":   file("DROP INDEX idx ON t;"),
+		"000001_init.up.sql":          file("SELECT 1;"),
+		"README.md":                   file("# 说明"),
+		"backfill.go":                 file("package migrations"),
+		"000003_without_down.up.sql":  file("SELECT 3;"),
+		"subdir/000004_nested.up.sql": file("SELECT 4;"),
+	}
+
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var versions []int64
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	if want := []int64{1, 2, 3, 10}; !reflect.DeepEqual(versions, want) {
+		t.Fatalf("versions = %v, want %v", versions, want)
+	}
+
+	createT := migrations[1]
+	if createT.Name != "create_t" || createT.Up != "CREATE TABLE t (id INT);" || createT.Down != "DROP TABLE t;" {
+		t.Errorf("migration 2 = %+v, want up and down paired", createT)
+	}
+	if migrations[2].Down != "" {
+		t.Errorf("migration 3 Down = %q, want empty", migrations[2].Down)
+	}
+	if migrations[0].Checksum == "" || migrations[0].Checksum == migrations[1].Checksum {
+		t.Errorf("checksums = %q, %q, want distinct up script hashes", migrations[0].Checksum, migrations[1].Checksum)
+	}
+}
+
+func TestLoadRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"命名不合法", fstest.MapFS{"init.up.sql": file("SELECT 1;")}},
+		{"缺少 up 脚本", fstest.MapFS{"000001_init.down.sql": file("SELECT 1;")}},
+		{"同一版本多个名称", fstest.MapFS{
+			"000001_init.up.sql":  file("SELECT 1;"),
+			"000001_other.up.sql": file("SELECT 2;"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(tt.fsys); err == nil {
+				t.Error("Load() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestNewMergesFuncMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_init.up.sql":   file("SELECT 1;"),
+		"000003_switch.up.sql": file("SELECT 3;"),
+	}
+	noop := func(ctx context.Context, conn *sql.Conn) error { return nil }
+
+	m, err := New(nil, fsys, WithFunc(2, "backfill", noop, nil))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var names []string
+	for _, migration := range m.Migrations() {
+		names = append(names, migration.Name)
+	}
+	if want := []string{"init", "backfill", "switch"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	if _, err := New(nil, fsys, WithFunc(3, "backfill", noop, nil)); err == nil {
+		t.Error("New() with duplicate version error = nil, want error")
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "多条语句",
+			script: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:   []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:   "缺少结尾分号",
+			script: "SELECT 1;\nSELECT 2",
+			want:   []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:   "字符串中的分号",
+			script: `INSERT INTO t VALUES ('a;b', "c;d");`,
+			want:   []string{`INSERT INTO t VALUES ('a;b', "c;d")`},
+		},
+		{
+			name:   "转义引号",
+			script: `INSERT INTO t VALUES ('it\'s;ok');SELECT 1;`,
+			want:   []string{`INSERT INTO t VALUES ('it\'s;ok')`, "SELECT 1"},
+		},
+		{
+			name:   "反引号标识符中的分号",
+			script: "SELECT `a;b` FROM t;",
+			want:   []string{"SELECT `a;b` FROM t"},
+		},
+		{
+			name:   "单行注释中的分号",
+			script: "-- 创建表;\nCREATE TABLE a (id INT); # 结尾注释;\n",
+			want:   []string{"CREATE TABLE a (id INT)"},
+		},
+		{
+			name:   "多行注释中的分号",
+			script: "/* 第一行;\n第二行; */ SELECT 1;",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "空语句和纯注释",
+			script: ";;\n-- 只有注释\n/* 注释 */;\n",
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
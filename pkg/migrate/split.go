@@ -0,0 +1,77 @@
+package migrate
+
+import "strings"
+
+// SplitStatements 按分号拆分 SQL 脚本
+// 会跳过字符串、反引号标识符和注释中的分号，并丢弃空语句和纯注释
+func SplitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		hasContent bool
+	)
+
+	flush := func() {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(current.String()))
+		}
+		current.Reset()
+		hasContent = false
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		// 单行注释：-- 或 #
+		case (r == '-' && next == '-') || r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+
+		// 多行注释
+		case r == '/' && next == '*':
+			i += 2
+			for i < len(runes) && !(runes[i] == '*' && i+1 < len(runes) && runes[i+1] == '/') {
+				i++
+			}
+			i++
+			current.WriteRune(' ')
+
+		// 字符串和标识符
+		case r == '\'' || r == '"' || r == '`':
+			quote := r
+			current.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				current.WriteRune(runes[i])
+				if runes[i] == '\\' && quote != '`' && i+1 < len(runes) {
+					i++
+					current.WriteRune(runes[i])
+					continue
+				}
+				if runes[i] == quote {
+					break
+				}
+			}
+			hasContent = true
+
+		case r == ';':
+			flush()
+
+		default:
+			current.WriteRune(r)
+			if !strings.ContainsRune(" \t\r\n", r) {
+				hasContent = true
+			}
+		}
+	}
+	flush()
+
+	return statements
+}
@@ -0,0 +1,219 @@
+// Package phone 手机号解析和 E.164 格式规范化
+//
+// 支持带国家码的国际格式（+8613800138000、008613800138000）和默认地区的本地格式（13800138000），
+// 按地区规则校验后统一转换为 E.164 格式（+ 国家码 + 国内号码）存储和查询
+package phone
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrInvalid 手机号格式错误或不符合所属地区的号码规则
+	ErrInvalid = errors.New("手机号格式错误")
+	// ErrRegionNotAllowed 手机号所属地区不在允许范围内
+	ErrRegionNotAllowed = errors.New("不支持该地区的手机号")
+)
+
+// Region 地区的手机号规则
+type Region struct {
+	Code        string         // ISO 3166-1 二位地区代码，如 CN
+	CallingCode string         // 国际电话区号，如 86
+	TrunkPrefix string         // 本地格式的长途前缀，如英国号码 07700900123 中的 0，解析时去掉
+	pattern     *regexp.Regexp // 国内号码（不含国家码和长途前缀）的手机号规则
+}
+
+// regions 支持的地区，同一国际电话区号的多个地区按顺序匹配
+// 美国和加拿大共用 +1 且号码规则相同，按国际格式解析时归属列表中第一个允许的地区
+var regions = []*Region{
+	{Code: "CN", CallingCode: "86", pattern: regexp.MustCompile(`^1[3-9]\d{9}$`)},
+	{Code: "HK", CallingCode: "852", pattern: regexp.MustCompile(`^[4-79]\d{7}$`)},
+	{Code: "MO", CallingCode: "853", pattern: regexp.MustCompile(`^6\d{7}$`)},
+	{Code: "TW", CallingCode: "886", TrunkPrefix: "0", pattern: regexp.MustCompile(`^9\d{8}$`)},
+	{Code: "US", CallingCode: "1", pattern: regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`)},
+	{Code: "CA", CallingCode: "1", pattern: regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`)},
+	{Code: "GB", CallingCode: "44", TrunkPrefix: "0", pattern: regexp.MustCompile(`^7\d{9}$`)},
+	{Code: "DE", CallingCode: "49", TrunkPrefix: "0", pattern: regexp.MustCompile(`^1[5-7]\d{8,9}$`)},
+	{Code: "FR", CallingCode: "33", TrunkPrefix: "0", pattern: regexp.MustCompile(`^[67]\d{8}$`)},
+	{Code: "JP", CallingCode: "81", TrunkPrefix: "0", pattern: regexp.MustCompile(`^[789]0\d{8}$`)},
+	{Code: "KR", CallingCode: "82", TrunkPrefix: "0", pattern: regexp.MustCompile(`^1\d{8,9}$`)},
+	{Code: "SG", CallingCode: "65", pattern: regexp.MustCompile(`^[89]\d{7}$`)},
+	{Code: "MY", CallingCode: "60", TrunkPrefix: "0", pattern: regexp.MustCompile(`^1\d{8,9}$`)},
+	{Code: "AU", CallingCode: "61", TrunkPrefix: "0", pattern: regexp.MustCompile(`^4\d{8}$`)},
+	{Code: "IN", CallingCode: "91", TrunkPrefix: "0", pattern: regexp.MustCompile(`^[6-9]\d{9}$`)},
+}
+
+// separators 输入中允许出现的分隔符，解析前去掉
+var separators = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+
+// digits 纯数字
+var digits = regexp.MustCompile(`^\d+$`)
+
+// LookupRegion 根据地区代码查找规则，地区代码不区分大小写
+func LookupRegion(code string) (*Region, bool) {
+	code = strings.ToUpper(code)
+	for _, r := range regions {
+		if r.Code == code {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// SupportedRegions 返回支持的地区代码
+func SupportedRegions() []string {
+	codes := make([]string, 0, len(regions))
+	for _, r := range regions {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+// Number 解析后的手机号
+type Number struct {
+	Region      string // 地区代码
+	CallingCode string // 国际电话区号
+	National    string // 国内号码
+}
+
+// E164 返回 E.164 格式，如 +8613800138000
+func (n Number) E164() string {
+	return "+" + n.CallingCode + n.National
+}
+
+// Parser 手机号解析器
+type Parser struct {
+	defaultRegion *Region
+	allowed       []*Region // 按 regions 顺序排列
+}
+
+// NewParser 创建手机号解析器
+// defaultRegion 为本地格式号码所属的地区；allowed 为允许的地区，为空时允许全部支持的地区
+func NewParser(defaultRegion string, allowed []string) (*Parser, error) {
+	def, ok := LookupRegion(defaultRegion)
+	if !ok {
+		return nil, errors.New("不支持的默认地区: " + defaultRegion)
+	}
+
+	p := &Parser{defaultRegion: def}
+	if len(allowed) == 0 {
+		p.allowed = regions
+	} else {
+		for _, r := range regions {
+			if slices.ContainsFunc(allowed, func(code string) bool { return strings.EqualFold(code, r.Code) }) {
+				p.allowed = append(p.allowed, r)
+			}
+		}
+		for _, code := range allowed {
+			if _, ok := LookupRegion(code); !ok {
+				return nil, errors.New("不支持的地区: " + code)
+			}
+		}
+	}
+	if !p.isAllowed(def) {
+		return nil, errors.New("默认地区不在允许的地区中: " + def.Code)
+	}
+	return p, nil
+}
+
+// Parse 解析手机号
+// 以 + 或 00 开头时按国际格式解析，否则按默认地区的本地格式解析
+func (p *Parser) Parse(raw string) (Number, error) {
+	s := separators.Replace(strings.TrimSpace(raw))
+
+	international := false
+	if rest, ok := strings.CutPrefix(s, "+"); ok {
+		s, international = rest, true
+	} else if rest, ok := strings.CutPrefix(s, "00"); ok {
+		s, international = rest, true
+	}
+	if !digits.MatchString(s) || len(s) > 15 {
+		return Number{}, ErrInvalid
+	}
+
+	if !international {
+		return p.match(p.defaultRegion, s)
+	}
+
+	// 国际电话区号为 1 到 3 位，且互不为前缀
+	var candidates []*Region
+	for _, r := range regions {
+		if strings.HasPrefix(s, r.CallingCode) {
+			candidates = append(candidates, r)
+		}
+	}
+	if len(candidates) == 0 {
+		return Number{}, ErrInvalid
+	}
+
+	err := ErrInvalid
+	for _, r := range candidates {
+		n, matchErr := p.match(r, strings.TrimPrefix(s, r.CallingCode))
+		if matchErr == nil {
+			return n, nil
+		}
+		// 号码格式正确但地区不允许时，继续尝试同区号的其他地区
+		if errors.Is(matchErr, ErrRegionNotAllowed) {
+			err = matchErr
+		}
+	}
+	return Number{}, err
+}
+
+// Normalize 解析手机号并返回 E.164 格式
+func (p *Parser) Normalize(raw string) (string, error) {
+	n, err := p.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return n.E164(), nil
+}
+
+// Valid 判断手机号是否有效且属于允许的地区
+func (p *Parser) Valid(raw string) bool {
+	_, err := p.Parse(raw)
+	return err == nil
+}
+
+// match 按地区规则校验国内号码，号码带有长途前缀时去掉
+func (p *Parser) match(r *Region, national string) (Number, error) {
+	if r.TrunkPrefix != "" && !r.pattern.MatchString(national) {
+		national = strings.TrimPrefix(national, r.TrunkPrefix)
+	}
+	if !r.pattern.MatchString(national) {
+		return Number{}, ErrInvalid
+	}
+	if !p.isAllowed(r) {
+		return Number{}, ErrRegionNotAllowed
+	}
+	return Number{Region: r.Code, CallingCode: r.CallingCode, National: national}, nil
+}
+
+// isAllowed 判断地区是否允许
+func (p *Parser) isAllowed(r *Region) bool {
+	return slices.Contains(p.allowed, r)
+}
+
+// Mask 脱敏 E.164 格式手机号，保留国家码，国内号码至少一半的数字脱敏，如 +86 13******000、+852 51****78
+// 无法识别国家码时按整体脱敏
+func Mask(e164 string) string {
+	if rest, ok := strings.CutPrefix(e164, "+"); ok {
+		for _, r := range regions {
+			if national, ok := strings.CutPrefix(rest, r.CallingCode); ok {
+				return "+" + r.CallingCode + " " + maskDigits(national)
+			}
+		}
+	}
+	return maskDigits(e164)
+}
+
+// maskDigits 至少脱敏一半的数字，保留的前缀和后缀随长度增加，前缀最多 3 位，后缀最多 4 位
+func maskDigits(s string) string {
+	keep := len(s) / 2
+	suffix := min((keep+1)/2, 4)
+	prefix := min(keep-suffix, 3)
+	return s[:prefix] + strings.Repeat("*", len(s)-prefix-suffix) + s[len(s)-suffix:]
+}
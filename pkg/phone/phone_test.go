@@ -0,0 +1,174 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustParser(t *testing.T, defaultRegion string, allowed ...string) *Parser {
+	t.Helper()
+	p, err := NewParser(defaultRegion, allowed)
+	if err != nil {
+		t.Fatalf("NewParser(%q, %v) error = %v", defaultRegion, allowed, err)
+	}
+	return p
+}
+
+func TestParse(t *testing.T) {
+	cn := mustParser(t, "CN")
+	gb := mustParser(t, "GB")
+	us := mustParser(t, "US")
+
+	tests := []struct {
+		name       string
+		parser     *Parser
+		raw        string
+		wantE164   string
+		wantRegion string
+		wantErr    error
+	}{
+		// 中国大陆
+		{"CN 本地格式", cn, "13800138000", "+8613800138000", "CN", nil},
+		{"CN 带 +", cn, "+8613800138000", "+8613800138000", "CN", nil},
+		{"CN 00 前缀", cn, "008613800138000", "+8613800138000", "CN", nil},
+		{"CN 分隔符", cn, " +86 138-0013-8000 ", "+8613800138000", "CN", nil},
+		{"CN 位数不足", cn, "1380013800", "", "", ErrInvalid},
+		{"CN 位数过多", cn, "138001380000", "", "", ErrInvalid},
+		{"CN 号段错误", cn, "+8612800138000", "", "", ErrInvalid},
+
+		// 美国，+1 与加拿大共用，归属列表中第一个允许的地区
+		{"US 国际格式", cn, "+14155552671", "+14155552671", "US", nil},
+		{"US 括号和分隔符", cn, "+1 (415) 555-2671", "+14155552671", "US", nil},
+		{"US 本地格式按默认地区 CN 解析", cn, "4155552671", "", "", ErrInvalid},
+		{"US 默认地区本地格式", us, "415-555-2671", "+14155552671", "US", nil},
+		{"US 区号不能以 1 开头", us, "1155552671", "", "", ErrInvalid},
+		{"US 默认地区解析 CN 本地号码", us, "13800138000", "", "", ErrInvalid},
+
+		// 英国，本地格式带长途前缀 0
+		{"GB 国际格式", cn, "+447700900123", "+447700900123", "GB", nil},
+		{"GB 国际格式带长途前缀", cn, "+44 07700 900123", "+447700900123", "GB", nil},
+		{"GB 默认地区带长途前缀", gb, "07700 900123", "+447700900123", "GB", nil},
+		{"GB 默认地区不带长途前缀", gb, "7700900123", "+447700900123", "GB", nil},
+		{"GB 位数不足", gb, "0770090012", "", "", ErrInvalid},
+		{"GB 固定电话", gb, "02079460000", "", "", ErrInvalid},
+
+		// 格式错误
+		{"空字符串", cn, "", "", "", ErrInvalid},
+		{"包含字母", cn, "138abc38000", "", "", ErrInvalid},
+		{"只有 +", cn, "+", "", "", ErrInvalid},
+		{"未知国际区号", cn, "+999123456789", "", "", ErrInvalid},
+		{"超过 15 位", cn, "+1415555267112345", "", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.parser.Parse(tt.raw)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse(%q) = %+v, %v, want %v", tt.raw, n, err, tt.wantErr)
+				}
+				if tt.parser.Valid(tt.raw) {
+					t.Errorf("Valid(%q) = true, want false", tt.raw)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.raw, err)
+			}
+			if n.E164() != tt.wantE164 || n.Region != tt.wantRegion {
+				t.Errorf("Parse(%q) = %s (%s), want %s (%s)", tt.raw, n.E164(), n.Region, tt.wantE164, tt.wantRegion)
+			}
+			if got, err := tt.parser.Normalize(tt.raw); err != nil || got != tt.wantE164 {
+				t.Errorf("Normalize(%q) = %q, %v, want %q", tt.raw, got, err, tt.wantE164)
+			}
+		})
+	}
+}
+
+func TestParseAllowedRegions(t *testing.T) {
+	tests := []struct {
+		name       string
+		parser     *Parser
+		raw        string
+		wantRegion string
+		wantErr    error
+	}{
+		{"地区不允许", mustParser(t, "CN", "CN"), "+14155552671", "", ErrRegionNotAllowed},
+		{"同区号归属第一个允许的地区", mustParser(t, "CN", "CN", "CA"), "+14155552671", "CA", nil},
+		{"地区代码不区分大小写", mustParser(t, "cn", "cn", "gb"), "+447700900123", "GB", nil},
+		{"格式错误优先于地区不允许", mustParser(t, "CN", "CN"), "+1415555", "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.parser.Parse(tt.raw)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Parse(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || n.Region != tt.wantRegion {
+				t.Errorf("Parse(%q) = %+v, %v, want region %s", tt.raw, n, err, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestNewParserRejectsInvalidRegions(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultRegion string
+		allowed       []string
+	}{
+		{"不支持的默认地区", "XX", nil},
+		{"不支持的允许地区", "CN", []string{"CN", "XX"}},
+		{"默认地区不在允许的地区中", "CN", []string{"US"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewParser(tt.defaultRegion, tt.allowed); err == nil {
+				t.Error("NewParser() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		e164 string
+		want string
+	}{
+		{"+8613800138000", "+86 13******000"},
+		{"+14155552671", "+1 41*****671"},
+		{"+447700900123", "+44 77*****123"},
+		{"+85251234578", "+852 51****78"},
+		{"+6591234567", "+65 91****67"},
+		{"+4915123456789", "+49 15******789"},
+		{"+999123", "+****23"},
+		{"123456789012345", "123********2345"},
+		{"1234", "1**4"},
+		{"12", "*2"},
+		{"1", "*"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.e164, func(t *testing.T) {
+			if got := Mask(tt.e164); got != tt.want {
+				t.Errorf("Mask(%q) = %q, want %q", tt.e164, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskDigitsMasksAtLeastHalf(t *testing.T) {
+	for n := 1; n <= 15; n++ {
+		s := strings.Repeat("9", n)
+		got := maskDigits(s)
+		if len(got) != n {
+			t.Fatalf("maskDigits(%d digits) = %q, length changed", n, got)
+		}
+		if masked := strings.Count(got, "*"); masked*2 < n {
+			t.Errorf("maskDigits(%d digits) = %q, masked %d, want at least half", n, got, masked)
+		}
+	}
+}
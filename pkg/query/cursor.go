@@ -0,0 +1,259 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Cursor 游标，记录翻页位置的排序键值
+type Cursor struct {
+	Values   []any // 与 Spec.Sorts 一一对应
+	Backward bool  // true 表示向前翻页（上一页）
+}
+
+// CursorPage 游标分页结果
+type CursorPage[T any] struct {
+	Items []T
+	Next  *Cursor // nil 表示没有下一页
+	Prev  *Cursor // nil 表示没有上一页
+}
+
+// MapPage 转换分页结果中的元素类型
+func MapPage[T, U any](page *CursorPage[T], fn func(T) U) *CursorPage[U] {
+	items := make([]U, len(page.Items))
+	for i, item := range page.Items {
+		items[i] = fn(item)
+	}
+	return &CursorPage[U]{Items: items, Next: page.Next, Prev: page.Prev}
+}
+
+// FindPage 按游标查询一页数据
+// 使用排序键比较代替 OFFSET，排序键需以唯一列结尾（Builder 会自动追加）
+func FindPage[T any](db *gorm.DB, spec *Spec, cursor *Cursor, limit int) (*CursorPage[*T], error) {
+	backward := cursor != nil && cursor.Backward
+
+	q := spec.ApplyFilters(db)
+	if cursor != nil {
+		if len(cursor.Values) != len(spec.Sorts) {
+			return nil, appErrors.BadRequest("游标无效")
+		}
+		q = q.Where(keysetCondition(spec.Sorts, cursor.Values, backward))
+	}
+	for _, sort := range spec.Sorts {
+		// 向前翻页时反向排序，取到结果后再翻转
+		q = q.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc != backward})
+	}
+
+	var items []*T
+	if err := q.Limit(limit + 1).Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+
+	page := &CursorPage[*T]{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	first, err := keyValues(db, items[0], spec.Sorts)
+	if err != nil {
+		return nil, err
+	}
+	last, err := keyValues(db, items[len(items)-1], spec.Sorts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 向后翻页时，有更多数据才有下一页，带游标说明前面还有数据
+	// 向前翻页时相反
+	if (!backward && hasMore) || backward {
+		page.Next = &Cursor{Values: last}
+	}
+	if (backward && hasMore) || (!backward && cursor != nil) {
+		page.Prev = &Cursor{Values: first, Backward: true}
+	}
+	return page, nil
+}
+
+// keysetCondition 构造排序键比较条件
+// (a, b, c) 之后的行：a > ? OR (a = ? AND b > ?) OR (a = ? AND b = ? AND c > ?)，降序列使用 <
+func keysetCondition(sorts []Sort, values []any, backward bool) clause.Expression {
+	var ors []clause.Expression
+	for i := range sorts {
+		var ands []clause.Expression
+		for j := 0; j < i; j++ {
+			ands = append(ands, clause.Eq{Column: clause.Column{Name: sorts[j].Column}, Value: values[j]})
+		}
+		column := clause.Column{Name: sorts[i].Column}
+		if sorts[i].Desc != backward {
+			ands = append(ands, clause.Lt{Column: column, Value: values[i]})
+		} else {
+			ands = append(ands, clause.Gt{Column: column, Value: values[i]})
+		}
+		ors = append(ors, clause.And(ands...))
+	}
+	return clause.Or(ors...)
+}
+
+// keyValues 读取记录的排序键值
+func keyValues(db *gorm.DB, item any, sorts []Sort) ([]any, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(item); err != nil {
+		return nil, fmt.Errorf("解析模型失败: %w", err)
+	}
+	rv := reflect.Indirect(reflect.ValueOf(item))
+	values := make([]any, len(sorts))
+	for i, sort := range sorts {
+		field := stmt.Schema.LookUpField(sort.Column)
+		if field == nil {
+			return nil, fmt.Errorf("排序列 %s 不存在", sort.Column)
+		}
+		values[i], _ = field.ValueOf(context.Background(), rv)
+	}
+	return values, nil
+}
+
+// CursorCodec 游标编解码器
+// 游标令牌对客户端不透明，使用 HMAC 签名防止篡改，并绑定查询条件和排序，条件变化时旧游标失效
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec 创建游标编解码器
+// 未配置密钥时使用随机密钥，游标在重启后或多副本之间失效
+func NewCursorCodec(cfg *config.PaginationConfig) *CursorCodec {
+	secret := []byte(cfg.CursorSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+		slog.Warn("未配置 pagination.cursor_secret，使用随机密钥，游标在重启后或多副本之间失效")
+	}
+	return &CursorCodec{secret: secret}
+}
+
+// cursorPayload 游标令牌内容
+type cursorPayload struct {
+	Spec     string        `json:"s"`
+	Values   []cursorValue `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+// cursorValue 带类型的排序键值，保证时间等类型解码后与编码前一致
+type cursorValue struct {
+	Type  string `json:"t,omitempty"`
+	Value any    `json:"v"`
+}
+
+// Encode 编码游标，cursor 为 nil 时返回空字符串
+func (c *CursorCodec) Encode(cursor *Cursor, spec *Spec) string {
+	if cursor == nil {
+		return ""
+	}
+	payload := cursorPayload{Spec: spec.signature(), Backward: cursor.Backward}
+	for _, v := range cursor.Values {
+		if t, ok := v.(time.Time); ok {
+			payload.Values = append(payload.Values, cursorValue{Type: "time", Value: t.Format(time.RFC3339Nano)})
+			continue
+		}
+		payload.Values = append(payload.Values, cursorValue{Value: v})
+	}
+
+	data, _ := json.Marshal(payload)
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(c.sign(data))
+}
+
+// Decode 解码并校验游标
+func (c *CursorCodec) Decode(token string, spec *Spec) (*Cursor, error) {
+	invalid := appErrors.BadRequest("游标无效")
+
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalid
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid
+	}
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, c.sign(data)) {
+		return nil, invalid
+	}
+
+	var payload cursorPayload
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		return nil, invalid
+	}
+	if payload.Spec != spec.signature() {
+		return nil, invalid.WithDetail("查询条件或排序已变化，请从第一页重新查询")
+	}
+
+	cursor := &Cursor{Backward: payload.Backward, Values: make([]any, len(payload.Values))}
+	for i, v := range payload.Values {
+		switch value := v.Value.(type) {
+		case json.Number:
+			n, err := value.Int64()
+			if err != nil {
+				return nil, invalid
+			}
+			cursor.Values[i] = n
+		case string:
+			if v.Type == "time" {
+				t, err := time.Parse(time.RFC3339Nano, value)
+				if err != nil {
+					return nil, invalid
+				}
+				cursor.Values[i] = t
+				continue
+			}
+			cursor.Values[i] = value
+		default:
+			cursor.Values[i] = value
+		}
+	}
+	return cursor, nil
+}
+
+// sign 计算签名
+func (c *CursorCodec) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// signature 查询规格摘要，用于将游标绑定到查询条件和排序
+func (s *Spec) signature() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "deleted=%t;", s.IncludeDeleted)
+	for _, f := range s.Filters {
+		fmt.Fprintf(h, "f:%s:%d:%v;", f.Column, f.Op, f.Value)
+	}
+	for _, sort := range s.Sorts {
+		fmt.Fprintf(h, "s:%s:%t;", sort.Column, sort.Desc)
+	}
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:12])
+}
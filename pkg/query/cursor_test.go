@@ -0,0 +1,179 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func testSpec() *Spec {
+	return &Spec{
+		Filters: []Filter{{Column: "status", Op: Eq, Value: int64(1)}},
+		Sorts: []Sort{
+			{Field: "created_at", Column: "created_at", Desc: true},
+			{Field: "id", Column: "id"},
+		},
+	}
+}
+
+func TestCursorCodecRoundTrip(t *testing.T) {
+	codec := NewCursorCodec(&config.PaginationConfig{CursorSecret: "test-secret"})
+	createdAt := time.Date(2024, 5, 1, 8, 30, 0, 123456789, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor *Cursor
+	}{
+		{"向后翻页", &Cursor{Values: []any{createdAt, int64(42)}}},
+		{"向前翻页", &Cursor{Values: []any{createdAt, int64(7)}, Backward: true}},
+		{"字符串排序键", &Cursor{Values: []any{"alice", int64(3)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec()
+			token := codec.Encode(tt.cursor, spec)
+			got, err := codec.Decode(token, spec)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got.Backward != tt.cursor.Backward {
+				t.Errorf("Backward = %v, want %v", got.Backward, tt.cursor.Backward)
+			}
+			if len(got.Values) != len(tt.cursor.Values) {
+				t.Fatalf("Values = %v, want %v", got.Values, tt.cursor.Values)
+			}
+			for i, want := range tt.cursor.Values {
+				if wantTime, ok := want.(time.Time); ok {
+					if gotTime, ok := got.Values[i].(time.Time); !ok || !gotTime.Equal(wantTime) {
+						t.Errorf("Values[%d] = %v, want %v", i, got.Values[i], want)
+					}
+					continue
+				}
+				if !reflect.DeepEqual(got.Values[i], want) {
+					t.Errorf("Values[%d] = %#v, want %#v", i, got.Values[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCursorCodecEncodeNil(t *testing.T) {
+	codec := NewCursorCodec(&config.PaginationConfig{CursorSecret: "test-secret"})
+	if got := codec.Encode(nil, testSpec()); got != "" {
+		t.Errorf("Encode(nil) = %q, want empty", got)
+	}
+}
+
+func TestCursorCodecDecodeRejects(t *testing.T) {
+	codec := NewCursorCodec(&config.PaginationConfig{CursorSecret: "test-secret"})
+	token := codec.Encode(&Cursor{Values: []any{time.Now(), int64(1)}}, testSpec())
+	encoded, sig, _ := strings.Cut(token, ".")
+
+	otherSort := testSpec()
+	otherSort.Sorts[0].Desc = false
+	otherFilter := testSpec()
+	otherFilter.Filters[0].Value = int64(2)
+	withDeleted := testSpec()
+	withDeleted.IncludeDeleted = true
+
+	tests := []struct {
+		name  string
+		codec *CursorCodec
+		token string
+		spec  *Spec
+	}{
+		{"缺少签名", codec, encoded, testSpec()},
+		{"内容被篡改", codec, encoded[:len(encoded)-2] + "AA." + sig, testSpec()},
+		{"签名被篡改", codec, encoded + "." + sig[:len(sig)-2] + "AA", testSpec()},
+		{"签名不是 base64", codec, encoded + ".!!", testSpec()},
+		{"其他密钥签名", NewCursorCodec(&config.PaginationConfig{CursorSecret: "other-secret"}), token, testSpec()},
+		{"排序方向变化", codec, token, otherSort},
+		{"过滤条件变化", codec, token, otherFilter},
+		{"包含已删除变化", codec, token, withDeleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.codec.Decode(tt.token, tt.spec)
+			var appErr *appErrors.AppError
+			if !errors.As(err, &appErr) || appErr.HTTPStatus != 400 {
+				t.Errorf("Decode() error = %v, want bad request", err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	mixed := []Sort{{Column: "created_at", Desc: true}, {Column: "id"}}
+
+	tests := []struct {
+		name     string
+		sorts    []Sort
+		values   []any
+		backward bool
+		wantSQL  string
+		wantVars []any
+	}{
+		{
+			name:     "单列升序",
+			sorts:    []Sort{{Column: "id"}},
+			values:   []any{int64(10)},
+			wantSQL:  "`id` > ?",
+			wantVars: []any{int64(10)},
+		},
+		{
+			name:     "混合排序方向，排序键相同时按唯一列比较",
+			sorts:    mixed,
+			values:   []any{createdAt, int64(10)},
+			wantSQL:  "(`created_at` < ? OR (`created_at` = ? AND `id` > ?))",
+			wantVars: []any{createdAt, createdAt, int64(10)},
+		},
+		{
+			name:     "混合排序方向向前翻页",
+			sorts:    mixed,
+			values:   []any{createdAt, int64(10)},
+			backward: true,
+			wantSQL:  "(`created_at` > ? OR (`created_at` = ? AND `id` < ?))",
+			wantVars: []any{createdAt, createdAt, int64(10)},
+		},
+		{
+			name:     "三列",
+			sorts:    []Sort{{Column: "display_order"}, {Column: "field_key", Desc: true}, {Column: "id"}},
+			values:   []any{int64(1), "name", int64(5)},
+			wantSQL:  "(`display_order` > ? OR (`display_order` = ? AND `field_key` < ?) OR (`display_order` = ? AND `field_key` = ? AND `id` > ?))",
+			wantVars: []any{int64(1), int64(1), "name", int64(1), "name", int64(5)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := buildCondition(t, keysetCondition(tt.sorts, tt.values, tt.backward))
+			if sql != tt.wantSQL {
+				t.Errorf("SQL = %s, want %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("Vars = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+// buildCondition 使用 MySQL 方言生成条件 SQL，不连接数据库
+func buildCondition(t *testing.T, expr clause.Expression) (string, []any) {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+	stmt := &gorm.Statement{DB: db, Clauses: map[string]clause.Clause{}}
+	expr.Build(stmt)
+	return stmt.SQL.String(), stmt.Vars
+}
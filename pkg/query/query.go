@@ -0,0 +1,243 @@
+package query
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	appErrors "github.com/deantook/dove/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Op 过滤操作符
+type Op int
+
+const (
+	Eq       Op = iota // 等于
+	Gte                // 大于等于
+	Lte                // 小于等于
+	Prefix             // 前缀匹配
+	Contains           // 包含关键字
+)
+
+// Kind 参数值类型
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	Time // RFC3339 或 2006-01-02
+)
+
+// Filter 单个过滤条件
+type Filter struct {
+	Column string
+	Op     Op
+	Value  any
+}
+
+// Sort 单个排序键
+type Sort struct {
+	Field  string // 查询参数中的排序字段名
+	Column string // 数据库列名
+	Desc   bool
+}
+
+// Spec 列表查询规格，由 Builder 从查询参数解析
+type Spec struct {
+	Filters        []Filter
+	Sorts          []Sort
+	IncludeDeleted bool // 包含已软删除的记录
+}
+
+// Apply 将过滤条件和排序应用到查询
+func (s *Spec) Apply(db *gorm.DB) *gorm.DB {
+	db = s.ApplyFilters(db)
+	for _, sort := range s.Sorts {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column}, Desc: sort.Desc})
+	}
+	return db
+}
+
+// ApplyFilters 只应用过滤条件，用于统计总数
+func (s *Spec) ApplyFilters(db *gorm.DB) *gorm.DB {
+	if s.IncludeDeleted {
+		db = db.Unscoped()
+	}
+	for _, f := range s.Filters {
+		column := clause.Column{Name: f.Column}
+		switch f.Op {
+		case Eq:
+			db = db.Where(clause.Eq{Column: column, Value: f.Value})
+		case Gte:
+			db = db.Where(clause.Gte{Column: column, Value: f.Value})
+		case Lte:
+			db = db.Where(clause.Lte{Column: column, Value: f.Value})
+		case Prefix:
+			db = db.Where(clause.Like{Column: column, Value: escapeLike(f.Value.(string)) + "%"})
+		case Contains:
+			db = db.Where(clause.Like{Column: column, Value: "%" + escapeLike(f.Value.(string)) + "%"})
+		}
+	}
+	return db
+}
+
+// filterDef 过滤参数定义
+type filterDef struct {
+	param  string
+	column string
+	op     Op
+	kind   Kind
+}
+
+// Builder 查询规格构建器
+// 声明允许的过滤参数和排序字段，未声明的参数不会进入 SQL
+type Builder struct {
+	filters     []filterDef
+	sortColumns map[string]string
+	defaultSort string
+	tieBreaker  string
+}
+
+// NewBuilder 创建查询规格构建器
+// tieBreaker 为唯一列（通常是主键），总是作为最后一个排序键以保证顺序稳定
+func NewBuilder(tieBreaker string) *Builder {
+	return &Builder{
+		sortColumns: make(map[string]string),
+		tieBreaker:  tieBreaker,
+	}
+}
+
+// Filter 声明过滤参数
+func (b *Builder) Filter(param, column string, op Op, kind Kind) *Builder {
+	b.filters = append(b.filters, filterDef{param: param, column: column, op: op, kind: kind})
+	return b
+}
+
+// Sortable 声明可排序字段
+func (b *Builder) Sortable(field, column string) *Builder {
+	b.sortColumns[field] = column
+	return b
+}
+
+// DefaultSort 设置未传 sort 参数时的排序，格式同 sort 参数
+func (b *Builder) DefaultSort(sort string) *Builder {
+	b.defaultSort = sort
+	return b
+}
+
+// SortFields 返回可排序字段，用于错误提示
+func (b *Builder) SortFields() []string {
+	fields := make([]string, 0, len(b.sortColumns))
+	for field := range b.sortColumns {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// Parse 从查询参数解析查询规格
+// sort 参数为逗号分隔的字段列表，字段前加 - 表示降序，如 sort=-created_at,username
+func (b *Builder) Parse(values url.Values) (*Spec, error) {
+	spec := &Spec{}
+	var problems []string
+
+	for _, def := range b.filters {
+		raw := strings.TrimSpace(values.Get(def.param))
+		if raw == "" {
+			continue
+		}
+		value, err := parseValue(raw, def.kind)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", def.param, err))
+			continue
+		}
+		// 日期作为上界时包含当天
+		if t, ok := value.(time.Time); ok && def.op == Lte && len(raw) == len(time.DateOnly) {
+			value = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		spec.Filters = append(spec.Filters, Filter{Column: def.column, Op: def.op, Value: value})
+	}
+
+	sortParam := values.Get("sort")
+	if sortParam == "" {
+		sortParam = b.defaultSort
+	}
+	sorts, err := b.parseSort(sortParam)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	spec.Sorts = sorts
+
+	if len(problems) > 0 {
+		return nil, appErrors.BadRequest("查询参数错误").WithDetail(strings.Join(problems, "; "))
+	}
+	return spec, nil
+}
+
+// parseSort 解析排序参数并追加唯一列
+func (b *Builder) parseSort(param string) ([]Sort, error) {
+	var sorts []Sort
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := strings.HasPrefix(part, "-")
+		field := strings.TrimLeft(part, "+-")
+		column, ok := b.sortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("sort: 不支持按 %s 排序（可选 %s）", field, strings.Join(b.SortFields(), "、"))
+		}
+		if seen[column] {
+			continue
+		}
+		seen[column] = true
+		sorts = append(sorts, Sort{Field: field, Column: column, Desc: desc})
+	}
+
+	if b.tieBreaker != "" && !seen[b.tieBreaker] {
+		desc := len(sorts) > 0 && sorts[len(sorts)-1].Desc
+		sorts = append(sorts, Sort{Field: b.tieBreaker, Column: b.tieBreaker, Desc: desc})
+	}
+	return sorts, nil
+}
+
+// parseValue 按类型解析参数值
+func parseValue(raw string, kind Kind) (any, error) {
+	switch kind {
+	case Int:
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("应为整数")
+		}
+		return v, nil
+	case Bool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("应为 true 或 false")
+		}
+		return v, nil
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		if t, err := time.ParseInLocation(time.DateOnly, raw, time.Local); err == nil {
+			return t, nil
+		}
+		return nil, fmt.Errorf("应为 RFC3339 时间或 YYYY-MM-DD 日期")
+	default:
+		return raw, nil
+	}
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,129 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// memoryCleanupInterval 内存限流器清理过期条目的间隔
+const memoryCleanupInterval = time.Minute
+
+// bucket 内存中的限流状态
+type bucket struct {
+	tokens    float64     // 令牌桶剩余令牌
+	updatedAt time.Time   // 令牌桶上次更新时间
+	requests  []time.Time // 滑动窗口内的请求时间
+	expireAt  time.Time
+}
+
+// MemoryLimiter 进程内限流器
+// 配额不在副本之间共享，用于测试和未配置 Redis 的场景
+type MemoryLimiter struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	now         func() time.Time
+	lastCleanup time.Time
+}
+
+// NewMemoryLimiter 创建内存限流器
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Allow 实现 Limiter
+func (l *MemoryLimiter) Allow(ctx context.Context, key string, policy Policy) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.cleanup(now)
+
+	key = policy.Algorithm + ":" + key
+	b, ok := l.buckets[key]
+	if !ok || now.After(b.expireAt) {
+		b = &bucket{tokens: float64(policy.capacity()), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	switch policy.Algorithm {
+	case AlgorithmTokenBucket:
+		return l.tokenBucket(b, policy, now), nil
+	case AlgorithmSlidingWindow:
+		return l.slidingWindow(b, policy, now), nil
+	default:
+		return nil, fmt.Errorf("不支持的限流算法: %q", policy.Algorithm)
+	}
+}
+
+// tokenBucket 令牌桶，与 Redis 脚本保持相同语义
+func (l *MemoryLimiter) tokenBucket(b *bucket, policy Policy, now time.Time) *Result {
+	rate := policy.ratePerMs()
+	capacity := float64(policy.capacity())
+
+	elapsed := float64(max(now.Sub(b.updatedAt).Milliseconds(), 0))
+	b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+	b.updatedAt = now
+
+	result := &Result{Limit: policy.capacity()}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = msDuration(math.Ceil((1 - b.tokens) / rate))
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.ResetAfter = msDuration(math.Ceil((capacity - b.tokens) / rate))
+	b.expireAt = now.Add(max(result.ResetAfter, time.Second))
+	return result
+}
+
+// slidingWindow 滑动窗口日志，与 Redis 脚本保持相同语义
+func (l *MemoryLimiter) slidingWindow(b *bucket, policy Policy, now time.Time) *Result {
+	boundary := now.Add(-policy.Window)
+	kept := b.requests[:0]
+	for _, t := range b.requests {
+		if t.After(boundary) {
+			kept = append(kept, t)
+		}
+	}
+	b.requests = kept
+
+	result := &Result{Limit: policy.Limit, ResetAfter: policy.Window}
+	if len(b.requests) < policy.Limit {
+		b.requests = append(b.requests, now)
+		result.Allowed = true
+	}
+	result.Remaining = policy.Limit - len(b.requests)
+	if len(b.requests) > 0 {
+		result.ResetAfter = b.requests[0].Add(policy.Window).Sub(now)
+	}
+	if !result.Allowed {
+		result.RetryAfter = result.ResetAfter
+	}
+	b.expireAt = now.Add(policy.Window)
+	return result
+}
+
+// cleanup 定期清理过期条目，避免内存无限增长
+func (l *MemoryLimiter) cleanup(now time.Time) {
+	if now.Sub(l.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+	l.lastCleanup = now
+	for key, b := range l.buckets {
+		if now.After(b.expireAt) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// msDuration 毫秒数转换为 time.Duration
+func msDuration(ms float64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
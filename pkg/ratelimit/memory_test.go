@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// step 一次请求：先推进时钟，再校验限流结果
+type step struct {
+	advance    time.Duration
+	allowed    bool
+	remaining  int
+	retryAfter time.Duration
+}
+
+func TestMemoryLimiterAllow(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		steps  []step
+	}{
+		{
+			name:   "令牌桶放行到配额上限",
+			policy: Policy{Algorithm: AlgorithmTokenBucket, Limit: 3, Window: 3 * time.Second},
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name:   "令牌桶突发容量",
+			policy: Policy{Algorithm: AlgorithmTokenBucket, Limit: 1, Window: time.Second, Burst: 3},
+			steps: []step{
+				{allowed: true, remaining: 2},
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: time.Second},
+			},
+		},
+		{
+			name:   "令牌桶按速率补充",
+			policy: Policy{Algorithm: AlgorithmTokenBucket, Limit: 2, Window: 2 * time.Second},
+			steps: []step{
+				{allowed: true, remaining: 1},
+				{allowed: true, remaining: 0},
+				{advance: 500 * time.Millisecond, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond},
+				{advance: 500 * time.Millisecond, allowed: true, remaining: 0},
+				{advance: 10 * time.Second, allowed: true, remaining: 1},
+			},
+		},
+		{
+			name:   "滑动窗口放行到配额上限",
+			policy: Policy{Algorithm: AlgorithmSlidingWindow, Limit: 2, Window: 10 * time.Second},
+			steps: []step{
+				{allowed: true, remaining: 1},
+				{advance: time.Second, allowed: true, remaining: 0},
+				{advance: time.Second, allowed: false, remaining: 0, retryAfter: 8 * time.Second},
+			},
+		},
+		{
+			name:   "滑动窗口移出过期请求",
+			policy: Policy{Algorithm: AlgorithmSlidingWindow, Limit: 2, Window: 10 * time.Second},
+			steps: []step{
+				{allowed: true, remaining: 1},
+				{advance: 4 * time.Second, allowed: true, remaining: 0},
+				{advance: 6 * time.Second, allowed: true, remaining: 0},
+				{allowed: false, remaining: 0, retryAfter: 4 * time.Second},
+				{advance: 4 * time.Second, allowed: true, remaining: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			l := NewMemoryLimiter()
+			l.now = func() time.Time { return now }
+
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				got, err := l.Allow(context.Background(), "k", tt.policy)
+				if err != nil {
+					t.Fatalf("step %d: Allow() error = %v", i, err)
+				}
+				if got.Allowed != s.allowed || got.Remaining != s.remaining || got.RetryAfter != s.retryAfter {
+					t.Errorf("step %d: Allow() = {allowed: %v, remaining: %d, retryAfter: %s}, want {allowed: %v, remaining: %d, retryAfter: %s}",
+						i, got.Allowed, got.Remaining, got.RetryAfter, s.allowed, s.remaining, s.retryAfter)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryLimiterKeysAreIndependent(t *testing.T) {
+	l := NewMemoryLimiter()
+	policy := Policy{Algorithm: AlgorithmTokenBucket, Limit: 1, Window: time.Minute}
+
+	for _, key := range []string{"a", "b"} {
+		got, err := l.Allow(context.Background(), key, policy)
+		if err != nil || !got.Allowed {
+			t.Fatalf("Allow(%q) = %+v, %v, want allowed", key, got, err)
+		}
+	}
+	if got, _ := l.Allow(context.Background(), "a", policy); got.Allowed {
+		t.Errorf("Allow(%q) allowed after quota was used", "a")
+	}
+}
+
+func TestMemoryLimiterUnknownAlgorithm(t *testing.T) {
+	l := NewMemoryLimiter()
+	if _, err := l.Allow(context.Background(), "k", Policy{Algorithm: "fixed_window", Limit: 1, Window: time.Second}); err == nil {
+		t.Error("Allow() error = nil, want unsupported algorithm error")
+	}
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 限流算法
+const (
+	AlgorithmTokenBucket   = "token_bucket"
+	AlgorithmSlidingWindow = "sliding_window"
+)
+
+// Policy 限流策略
+type Policy struct {
+	Algorithm string        // token_bucket 或 sliding_window
+	Limit     int           // 每个窗口允许的请求数
+	Window    time.Duration // 窗口长度
+	Burst     int           // 令牌桶容量，默认等于 Limit；滑动窗口不使用
+}
+
+// Validate 校验限流策略
+func (p Policy) Validate() error {
+	switch p.Algorithm {
+	case AlgorithmTokenBucket, AlgorithmSlidingWindow:
+	default:
+		return fmt.Errorf("不支持的限流算法: %q", p.Algorithm)
+	}
+	if p.Limit <= 0 {
+		return fmt.Errorf("limit 必须大于 0: %d", p.Limit)
+	}
+	if p.Window <= 0 {
+		return fmt.Errorf("window 必须大于 0: %s", p.Window)
+	}
+	if p.Burst < 0 {
+		return fmt.Errorf("burst 不能为负数: %d", p.Burst)
+	}
+	return nil
+}
+
+// capacity 令牌桶容量
+func (p Policy) capacity() int {
+	if p.Burst > 0 {
+		return p.Burst
+	}
+	return p.Limit
+}
+
+// ratePerMs 令牌桶每毫秒补充的令牌数
+func (p Policy) ratePerMs() float64 {
+	return float64(p.Limit) / float64(p.Window.Milliseconds())
+}
+
+// Result 限流结果
+type Result struct {
+	Allowed    bool          // 是否放行
+	Limit      int           // 配额
+	Remaining  int           // 剩余配额
+	RetryAfter time.Duration // 被拒绝时距离可重试的时间
+	ResetAfter time.Duration // 配额完全恢复的时间
+}
+
+// Limiter 限流器
+type Limiter interface {
+	// Allow 消耗 key 的一次配额
+	Allow(ctx context.Context, key string, policy Policy) (*Result, error)
+}
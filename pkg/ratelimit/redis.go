@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript 令牌桶
+// 使用 Redis 服务器时间，避免多副本之间的时钟偏差
+// 返回 {是否放行, 剩余令牌, 重试等待毫秒, 恢复满桶毫秒}
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local data = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(data[1])
+local ts = tonumber(data[2])
+if tokens == nil or ts == nil then
+  tokens = capacity
+  ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry_after = math.ceil((1 - tokens) / rate)
+end
+
+local reset_after = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', key, 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', key, math.max(reset_after, 1000))
+
+return {allowed, math.floor(tokens), retry_after, reset_after}
+`)
+
+// slidingWindowScript 滑动窗口日志
+// 每个请求以有序集合成员记录，成员分值为请求时间
+// 返回 {是否放行, 剩余次数, 重试等待毫秒, 窗口内最早请求过期毫秒}
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', key, now, member)
+  count = count + 1
+  allowed = 1
+end
+
+local reset_after = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+  reset_after = tonumber(oldest[2]) + window - now
+end
+
+local retry_after = 0
+if allowed == 0 then
+  retry_after = reset_after
+end
+
+redis.call('PEXPIRE', key, window)
+
+return {allowed, limit - count, retry_after, reset_after}
+`)
+
+// RedisLimiter 基于 Redis Lua 脚本的限流器，多副本共享配额
+type RedisLimiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisLimiter 创建 Redis 限流器
+func NewRedisLimiter(client *redis.Client, prefix string) *RedisLimiter {
+	return &RedisLimiter{client: client, prefix: prefix}
+}
+
+// Allow 实现 Limiter
+func (l *RedisLimiter) Allow(ctx context.Context, key string, policy Policy) (*Result, error) {
+	var (
+		values []interface{}
+		err    error
+	)
+	redisKey := l.prefix + policy.Algorithm + ":" + key
+
+	switch policy.Algorithm {
+	case AlgorithmTokenBucket:
+		values, err = tokenBucketScript.Run(ctx, l.client, []string{redisKey},
+			policy.ratePerMs(), policy.capacity()).Slice()
+	case AlgorithmSlidingWindow:
+		values, err = slidingWindowScript.Run(ctx, l.client, []string{redisKey},
+			policy.Limit, policy.Window.Milliseconds(), newMember()).Slice()
+	default:
+		return nil, fmt.Errorf("不支持的限流算法: %q", policy.Algorithm)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("执行限流脚本失败: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("限流脚本返回值异常: %v", values)
+	}
+
+	nums := make([]int64, len(values))
+	for i, v := range values {
+		n, ok := v.(int64)
+		if !ok {
+			return nil, fmt.Errorf("限流脚本返回值异常: %v", values)
+		}
+		nums[i] = n
+	}
+
+	limit := policy.Limit
+	if policy.Algorithm == AlgorithmTokenBucket {
+		limit = policy.capacity()
+	}
+
+	return &Result{
+		Allowed:    nums[0] == 1,
+		Limit:      limit,
+		Remaining:  int(max(nums[1], 0)),
+		RetryAfter: time.Duration(nums[2]) * time.Millisecond,
+		ResetAfter: time.Duration(nums[3]) * time.Millisecond,
+	}, nil
+}
+
+// newMember 生成滑动窗口中唯一的成员，避免同一毫秒内的请求相互覆盖
+func newMember() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), hex.EncodeToString(b))
+}
@@ -3,17 +3,19 @@ package redis
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/deantook/dove/internal/config"
+	"github.com/deantook/dove/pkg/tracing"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
 var client *redis.Client
 
 // Init 初始化 Redis 连接
-func Init(cfg *config.RedisConfig) (*redis.Client, error) {
+func Init(cfg *config.RedisConfig, tracer *tracing.Provider) (*redis.Client, error) {
 	client = redis.NewClient(&redis.Options{
 		Addr:     cfg.GetAddr(),
 		Password: cfg.Password,
@@ -21,6 +23,13 @@ func Init(cfg *config.RedisConfig) (*redis.Client, error) {
 		PoolSize: cfg.PoolSize,
 	})
 
+	// 为 Redis 命令创建 Span
+	if tracer.Enabled() {
+		if err := redisotel.InstrumentTracing(client, redisotel.WithTracerProvider(tracer.TracerProvider())); err != nil {
+			return nil, fmt.Errorf("注册 Redis 追踪失败: %w", err)
+		}
+	}
+
 	// 测试连接
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -29,7 +38,7 @@ func Init(cfg *config.RedisConfig) (*redis.Client, error) {
 		return nil, fmt.Errorf("Redis 连接失败: %w", err)
 	}
 
-	log.Println("Redis 连接成功!")
+	slog.Info("Redis 连接成功", slog.String("addr", cfg.GetAddr()))
 	return client, nil
 }
 
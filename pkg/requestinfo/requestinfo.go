@@ -0,0 +1,33 @@
+// Package requestinfo 在 context 中传递请求来源信息，供 Service 层记录操作来源
+package requestinfo
+
+import "context"
+
+// Info 请求来源信息
+type Info struct {
+	IP        string
+	UserAgent string
+	RequestID string
+	ActorID   int // 当前登录用户 ID，匿名请求为 0
+}
+
+// infoKey context 键
+type infoKey struct{}
+
+// WithInfo 将请求来源信息写入 context
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// FromContext 读取请求来源信息，非 HTTP 请求（如命令行、后台任务）返回零值
+func FromContext(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
+
+// WithActor 将当前登录用户 ID 写入 context 中的请求来源信息
+func WithActor(ctx context.Context, actorID int) context.Context {
+	info := FromContext(ctx)
+	info.ActorID = actorID
+	return WithInfo(ctx, info)
+}
@@ -1,8 +1,10 @@
 package response
 
 import (
+	"errors"
 	"net/http"
 
+	appErrors "github.com/deantook/dove/pkg/errors"
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,6 +24,15 @@ type ListResponse struct {
 	PageSize int         `json:"page_size"` // 每页数量
 }
 
+// CursorListResponse 游标分页列表响应结构
+type CursorListResponse struct {
+	List       interface{} `json:"list"`                          // 列表数据
+	NextCursor string      `json:"next_cursor"`                   // 下一页游标，为空表示没有下一页
+	PrevCursor string      `json:"prev_cursor"`                   // 上一页游标，为空表示没有上一页
+	Limit      int         `json:"limit"`                         // 每页数量
+	Total      *int64      `json:"total,omitempty" example:"100"` // 总记录数，仅在 with_total=true 时返回
+}
+
 // Success 成功响应
 func Success(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -63,8 +74,37 @@ func SuccessList(c *gin.Context, list interface{}, total int64, page, pageSize i
 	})
 }
 
+// SuccessCursorList 成功游标分页列表响应
+func SuccessCursorList(c *gin.Context, list interface{}, nextCursor, prevCursor string, limit int, total *int64) {
+	c.JSON(http.StatusOK, Response{
+		Code:    200,
+		Message: "success",
+		Data: CursorListResponse{
+			List:       list,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+			Limit:      limit,
+			Total:      total,
+		},
+	})
+}
+
 // Error 错误响应
 func Error(c *gin.Context, err error) {
+	// 业务错误
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		detail := appErr.Detail
+		if detail == "" && appErr.Err != nil {
+			detail = appErr.Err.Error()
+		}
+		c.JSON(appErr.HTTPStatus, Response{
+			Code:    appErr.Code,
+			Message: appErr.Message,
+			Detail:  detail,
+		})
+		return
+	}
 
 	// 默认错误处理
 	c.JSON(http.StatusInternalServerError, Response{
@@ -82,3 +122,38 @@ func ErrorWithCode(c *gin.Context, httpCode, code int, message string, detail st
 		Detail:  detail,
 	})
 }
+
+// BadRequest 400 错误响应
+func BadRequest(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusBadRequest, appErrors.CodeInvalidParams, message, detail)
+}
+
+// Unauthorized 401 错误响应
+func Unauthorized(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusUnauthorized, appErrors.CodeUnauthorized, message, detail)
+}
+
+// Forbidden 403 错误响应
+func Forbidden(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusForbidden, appErrors.CodeForbidden, message, detail)
+}
+
+// NotFound 404 错误响应
+func NotFound(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusNotFound, appErrors.CodeNotFound, message, detail)
+}
+
+// Conflict 409 错误响应
+func Conflict(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusConflict, appErrors.CodeConflict, message, detail)
+}
+
+// TooManyRequests 429 错误响应
+func TooManyRequests(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusTooManyRequests, appErrors.CodeTooManyRequests, message, detail)
+}
+
+// InternalServerError 500 错误响应
+func InternalServerError(c *gin.Context, message string, detail string) {
+	ErrorWithCode(c, http.StatusInternalServerError, appErrors.CodeInternal, message, detail)
+}
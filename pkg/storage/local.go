@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+)
+
+// ErrInvalidSignature 预签名地址无效或已过期
+var ErrInvalidSignature = errors.New("地址无效或已过期")
+
+// Local 本地文件系统存储
+// 预签名上传、下载地址指向本服务的上传、下载接口，由 VerifyPresigned、VerifyPresignedGet 校验签名
+type Local struct {
+	root        string
+	baseURL     string
+	uploadURL   string
+	downloadURL string
+	secret      []byte
+}
+
+// NewLocal 创建本地文件系统存储
+func NewLocal(cfg *config.LocalStorageConfig) (*Local, error) {
+	if err := os.MkdirAll(cfg.Root, 0o755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+
+	secret := []byte(cfg.SignSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, _ = rand.Read(secret)
+		slog.Warn("未配置 storage.local.sign_secret，使用随机密钥，预签名地址在重启后或多副本之间失效")
+	}
+
+	return &Local{
+		root:        cfg.Root,
+		baseURL:     strings.TrimSuffix(cfg.BaseURL, "/"),
+		uploadURL:   strings.TrimSuffix(cfg.UploadURL, "/"),
+		downloadURL: strings.TrimSuffix(cfg.DownloadURL, "/"),
+		secret:      secret,
+	}, nil
+}
+
+// Put 写入对象，先写入临时文件再重命名，避免读到写了一半的文件
+func (s *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("写入文件失败: %w", err)
+	}
+	return nil
+}
+
+// Get 读取对象
+func (s *Local) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Stat 获取对象信息，文件类型按扩展名推断
+func (s *Local) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Key: key, Size: fi.Size(), ContentType: mime.TypeByExtension(path.Ext(key))}, nil
+}
+
+// Delete 删除对象
+func (s *Local) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL 返回对象的访问地址
+func (s *Local) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+// PresignPut 生成指向本服务上传接口的预签名上传请求
+func (s *Local) PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error) {
+	if _, err := s.path(key); err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.sign(http.MethodPut, key, contentType, expires))
+
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	return &PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       s.uploadURL + "/" + key + "?" + q.Encode(),
+		Headers:   headers,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyPresigned 校验预签名上传地址
+func (s *Local) VerifyPresigned(key, contentType string, query url.Values) error {
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(s.sign(http.MethodPut, key, contentType, expires))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// PresignGet 生成指向本服务下载接口的预签名下载地址
+func (s *Local) PresignGet(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("filename", filename)
+	q.Set("signature", s.sign(http.MethodGet, key, filename, expires))
+	return s.downloadURL + "/" + key + "?" + q.Encode(), nil
+}
+
+// VerifyPresignedGet 校验预签名下载地址，返回下载时保存的文件名
+func (s *Local) VerifyPresignedGet(key string, query url.Values) (string, error) {
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return "", ErrInvalidSignature
+	}
+	filename := query.Get("filename")
+	if !hmac.Equal([]byte(query.Get("signature")), []byte(s.sign(http.MethodGet, key, filename, expires))) {
+		return "", ErrInvalidSignature
+	}
+	return filename, nil
+}
+
+// sign 计算预签名地址的签名，绑定请求方法、对象路径、过期时间，上传时绑定文件类型，下载时绑定文件名
+func (s *Local) sign(method, key, extra, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s", method, key, extra, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path 将对象路径转换为文件路径，拒绝越出存储目录的路径
+func (s *Local) path(key string) (string, error) {
+	if key == "" || !fs.ValidPath(key) {
+		return "", fmt.Errorf("无效的对象路径: %q", key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,220 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+)
+
+func newTestLocal(t *testing.T) (*Local, string) {
+	t.Helper()
+	root := filepath.Join(t.TempDir(), "uploads")
+	s, err := NewLocal(&config.LocalStorageConfig{
+		Root:        root,
+		BaseURL:     "/uploads/",
+		UploadURL:   "/api/v1/media/uploads",
+		DownloadURL: "/api/v1/downloads",
+		SignSecret:  "test-secret",
+	})
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	return s, root
+}
+
+func TestLocalPutGetStatDelete(t *testing.T) {
+	s, root := newTestLocal(t)
+	ctx := context.Background()
+	key := "avatar/1/2024/01/a.jpg"
+
+	if err := s.Put(ctx, key, strings.NewReader("image"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "avatar", "1", "2024", "01", "a.jpg")); err != nil {
+		t.Fatalf("file not written under root: %v", err)
+	}
+
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "image" {
+		t.Errorf("Get() = %q, want %q", data, "image")
+	}
+
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size != 5 || info.ContentType != "image/jpeg" {
+		t.Errorf("Stat() = %+v, want size 5 and image/jpeg", info)
+	}
+
+	// 覆盖写入
+	if err := s.Put(ctx, key, strings.NewReader("new"), -1, "image/jpeg"); err != nil {
+		t.Fatalf("Put() overwrite error = %v", err)
+	}
+	if info, _ := s.Stat(ctx, key); info.Size != 3 {
+		t.Errorf("Stat() after overwrite size = %d, want 3", info.Size)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() missing object error = %v, want nil", err)
+	}
+
+	if got := s.URL(key); got != "/uploads/"+key {
+		t.Errorf("URL() = %q, want %q", got, "/uploads/"+key)
+	}
+}
+
+func TestLocalRejectsInvalidKeys(t *testing.T) {
+	s, root := newTestLocal(t)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../escape.txt", "a/../../escape.txt", "/abs.txt", "a//b.txt", "./a.txt", "a/"} {
+		t.Run(key, func(t *testing.T) {
+			if err := s.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+				t.Error("Put() error = nil, want invalid key error")
+			}
+			if _, err := s.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+				t.Errorf("Get() error = %v, want invalid key error", err)
+			}
+			if err := s.Delete(ctx, key); err == nil {
+				t.Error("Delete() error = nil, want invalid key error")
+			}
+			if _, err := s.PresignPut(ctx, key, "text/plain", time.Minute); err == nil {
+				t.Error("PresignPut() error = nil, want invalid key error")
+			}
+			if _, err := s.PresignGet(ctx, key, "a.txt", time.Minute); err == nil {
+				t.Error("PresignGet() error = nil, want invalid key error")
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escape.txt")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file written outside root: %v", err)
+	}
+}
+
+// presignedQuery 解析预签名地址，校验路径并返回查询参数
+func presignedQuery(t *testing.T, rawURL, wantPath string) url.Values {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("url.Parse(%q) error = %v", rawURL, err)
+	}
+	if u.Path != wantPath {
+		t.Errorf("presigned path = %q, want %q", u.Path, wantPath)
+	}
+	return u.Query()
+}
+
+func TestLocalPresignPut(t *testing.T) {
+	s, _ := newTestLocal(t)
+	key := "media/1/a.png"
+
+	req, err := s.PresignPut(context.Background(), key, "image/png", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut() error = %v", err)
+	}
+	if req.Method != http.MethodPut || req.Headers.Get("Content-Type") != "image/png" {
+		t.Errorf("PresignPut() = %+v, want PUT with Content-Type image/png", req)
+	}
+	q := presignedQuery(t, req.URL, "/api/v1/media/uploads/"+key)
+
+	if err := s.VerifyPresigned(key, "image/png", q); err != nil {
+		t.Errorf("VerifyPresigned() error = %v", err)
+	}
+	if err := s.VerifyPresigned(key, "image/jpeg", q); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresigned() other content type error = %v, want ErrInvalidSignature", err)
+	}
+	if err := s.VerifyPresigned("media/2/a.png", "image/png", q); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresigned() other key error = %v, want ErrInvalidSignature", err)
+	}
+
+	expired, _ := s.PresignPut(context.Background(), key, "image/png", -time.Minute)
+	if err := s.VerifyPresigned(key, "image/png", presignedQuery(t, expired.URL, "/api/v1/media/uploads/"+key)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresigned() expired error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestLocalPresignGet(t *testing.T) {
+	s, _ := newTestLocal(t)
+	key := "exports/1/a.zip"
+
+	rawURL, err := s.PresignGet(context.Background(), key, "export.zip", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	q := presignedQuery(t, rawURL, "/api/v1/downloads/"+key)
+
+	filename, err := s.VerifyPresignedGet(key, q)
+	if err != nil || filename != "export.zip" {
+		t.Errorf("VerifyPresignedGet() = %q, %v, want export.zip", filename, err)
+	}
+
+	tampered := url.Values{}
+	for k, v := range q {
+		tampered[k] = v
+	}
+	tampered.Set("filename", "other.zip")
+	if _, err := s.VerifyPresignedGet(key, tampered); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresignedGet() tampered filename error = %v, want ErrInvalidSignature", err)
+	}
+	if _, err := s.VerifyPresignedGet("exports/2/a.zip", q); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresignedGet() other key error = %v, want ErrInvalidSignature", err)
+	}
+
+	// 上传签名不能用于下载
+	put, _ := s.PresignPut(context.Background(), key, "export.zip", time.Minute)
+	if _, err := s.VerifyPresignedGet(key, presignedQuery(t, put.URL, "/api/v1/media/uploads/"+key)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresignedGet() with upload signature error = %v, want ErrInvalidSignature", err)
+	}
+
+	expired, _ := s.PresignGet(context.Background(), key, "export.zip", -time.Minute)
+	if _, err := s.VerifyPresignedGet(key, presignedQuery(t, expired, "/api/v1/downloads/"+key)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyPresignedGet() expired error = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestNewPrivateUsesPrivateRoot(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.StorageConfig{Driver: "local", Local: config.LocalStorageConfig{
+		Root:        filepath.Join(dir, "uploads"),
+		PrivateRoot: filepath.Join(dir, "private"),
+		BaseURL:     "/uploads",
+		SignSecret:  "test-secret",
+	}}
+	s, err := NewPrivate(cfg)
+	if err != nil {
+		t.Fatalf("NewPrivate() error = %v", err)
+	}
+	if err := s.Put(context.Background(), "exports/1/a.zip", strings.NewReader("zip"), 3, "application/zip"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "private", "exports", "1", "a.zip")); err != nil {
+		t.Errorf("file not written under private root: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "uploads", "exports")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file written under public root: %v", err)
+	}
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3 S3 兼容对象存储，适用于 AWS S3、MinIO 等
+type S3 struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3 创建 S3 兼容对象存储
+func NewS3(cfg *config.S3StorageConfig) (*S3, error) {
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       cfg.UseSSL,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建 S3 客户端失败: %w", err)
+	}
+
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if publicURL == "" {
+		u := *client.EndpointURL()
+		if cfg.PathStyle {
+			u.Path = "/" + cfg.Bucket
+		} else {
+			u.Host = cfg.Bucket + "." + u.Host
+		}
+		publicURL = strings.TrimSuffix(u.String(), "/")
+	}
+
+	return &S3{client: client, bucket: cfg.Bucket, publicURL: publicURL}, nil
+}
+
+// Put 写入对象
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Get 读取对象
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	// GetObject 不会立即发起请求，先 Stat 以便返回 ErrNotFound
+	if _, err := s.Stat(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+}
+
+// Stat 获取对象信息
+func (s *S3) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s.translate(err)
+	}
+	return &ObjectInfo{Key: key, Size: info.Size, ContentType: info.ContentType}, nil
+}
+
+// Delete 删除对象
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		if errors.Is(s.translate(err), ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// URL 返回对象的访问地址
+func (s *S3) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// PresignPut 生成预签名上传请求，Content-Type 参与签名，上传时必须一致
+func (s *S3) PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+
+	u, err := s.client.PresignHeader(ctx, http.MethodPut, s.bucket, key, ttl, url.Values{}, headers)
+	if err != nil {
+		return nil, fmt.Errorf("生成预签名地址失败: %w", err)
+	}
+	return &PresignedRequest{
+		Method:    http.MethodPut,
+		URL:       u.String(),
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+// PresignGet 生成预签名下载地址，下载时以附件形式保存
+func (s *S3) PresignGet(ctx context.Context, key, filename string, ttl time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, params)
+	if err != nil {
+		return "", fmt.Errorf("生成预签名地址失败: %w", err)
+	}
+	return u.String(), nil
+}
+
+// translate 将对象不存在的错误转换为 ErrNotFound
+func (s *S3) translate(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case minio.NoSuchKey, "NotFound":
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+)
+
+// fakeS3 最小的 S3 兼容服务，只实现单个对象的 PUT、GET、HEAD、DELETE（路径风格地址），不校验签名
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]fakeObject // bucket/key
+}
+
+type fakeObject struct {
+	data        []byte
+	contentType string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		data, err := readPayload(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.objects[name] = fakeObject{data: data, contentType: r.Header.Get("Content-Type")}
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		obj, ok := f.objects[name]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			}
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		if r.Method == http.MethodGet {
+			w.Write(obj.data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// readPayload 读取请求体，客户端通过 HTTP 上传时使用 aws-chunked 分块签名编码
+func readPayload(r *http.Request) ([]byte, error) {
+	if !strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+		return io.ReadAll(r.Body)
+	}
+	var buf bytes.Buffer
+	br := bufio.NewReader(r.Body)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(line), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return buf.Bytes(), nil
+		}
+		if _, err := io.CopyN(&buf, br, size); err != nil {
+			return nil, err
+		}
+		if _, err := br.Discard(2); err != nil { // \r\n
+			return nil, err
+		}
+	}
+}
+
+func newTestS3(t *testing.T) (*S3, *fakeS3, *httptest.Server) {
+	t.Helper()
+	fake := &fakeS3{objects: make(map[string]fakeObject)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	s, err := NewS3(&config.S3StorageConfig{
+		Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+		Region:    "us-east-1",
+		Bucket:    "dove",
+		AccessKey: "access",
+		SecretKey: "secret",
+		PathStyle: true,
+	})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	return s, fake, server
+}
+
+func TestS3PutGetStatDelete(t *testing.T) {
+	s, fake, _ := newTestS3(t)
+	ctx := context.Background()
+	key := "avatar/1/a.jpg"
+
+	if err := s.Put(ctx, key, strings.NewReader("image"), 5, "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if obj := fake.objects["dove/"+key]; string(obj.data) != "image" || obj.contentType != "image/jpeg" {
+		t.Errorf("stored object = %q (%s), want image (image/jpeg)", obj.data, obj.contentType)
+	}
+
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Size != 5 || info.ContentType != "image/jpeg" {
+		t.Errorf("Stat() = %+v, want size 5 and image/jpeg", info)
+	}
+
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "image" {
+		t.Errorf("Get() = %q, %v, want image", data, err)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := s.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat() after delete error = %v, want ErrNotFound", err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete() missing object error = %v, want nil", err)
+	}
+}
+
+func TestS3URL(t *testing.T) {
+	s, _, server := newTestS3(t)
+	if got, want := s.URL("avatar/1/a.jpg"), server.URL+"/dove/avatar/1/a.jpg"; got != want {
+		t.Errorf("URL() = %q, want %q", got, want)
+	}
+
+	cdn, err := NewS3(&config.S3StorageConfig{Endpoint: "s3.example.com", Bucket: "dove", PublicURL: "https://cdn.example.com/"})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	if got, want := cdn.URL("a.jpg"), "https://cdn.example.com/a.jpg"; got != want {
+		t.Errorf("URL() with public_url = %q, want %q", got, want)
+	}
+
+	vhost, err := NewS3(&config.S3StorageConfig{Endpoint: "s3.example.com", Bucket: "dove", UseSSL: true})
+	if err != nil {
+		t.Fatalf("NewS3() error = %v", err)
+	}
+	if got, want := vhost.URL("a.jpg"), "https://dove.s3.example.com/a.jpg"; got != want {
+		t.Errorf("URL() virtual-hosted = %q, want %q", got, want)
+	}
+}
+
+func TestS3Presign(t *testing.T) {
+	s, _, server := newTestS3(t)
+	ctx := context.Background()
+	key := "exports/1/a.zip"
+
+	req, err := s.PresignPut(ctx, key, "application/zip", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignPut() error = %v", err)
+	}
+	if req.Method != http.MethodPut || req.Headers.Get("Content-Type") != "application/zip" {
+		t.Errorf("PresignPut() = %+v, want PUT with Content-Type application/zip", req)
+	}
+	u, _ := url.Parse(req.URL)
+	if u.Path != "/dove/"+key || u.Query().Get("X-Amz-Signature") == "" ||
+		!strings.Contains(u.Query().Get("X-Amz-SignedHeaders"), "content-type") {
+		t.Errorf("PresignPut() URL = %s, want signed content-type for /dove/%s", req.URL, key)
+	}
+
+	// 按预签名请求上传
+	put, _ := http.NewRequest(req.Method, req.URL, strings.NewReader("zip"))
+	put.Header = req.Headers.Clone()
+	resp, err := http.DefaultClient.Do(put)
+	if err != nil {
+		t.Fatalf("upload with presigned request error = %v", err)
+	}
+	resp.Body.Close()
+	if info, err := s.Stat(ctx, key); err != nil || info.Size != 3 {
+		t.Errorf("Stat() after presigned upload = %+v, %v, want size 3", info, err)
+	}
+
+	rawURL, err := s.PresignGet(ctx, key, "export.zip", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	u, _ = url.Parse(rawURL)
+	if !strings.HasPrefix(rawURL, server.URL+"/dove/"+key+"?") || u.Query().Get("X-Amz-Signature") == "" {
+		t.Errorf("PresignGet() = %s, want signed URL for /dove/%s", rawURL, key)
+	}
+	if got := u.Query().Get("response-content-disposition"); got != `attachment; filename=export.zip` {
+		t.Errorf("response-content-disposition = %q, want attachment with filename", got)
+	}
+}
+
+func TestNewPrivateUsesPrivateBucket(t *testing.T) {
+	s, err := NewPrivate(&config.StorageConfig{Driver: "s3", S3: config.S3StorageConfig{
+		Endpoint:      "s3.example.com",
+		Region:        "us-east-1",
+		Bucket:        "dove",
+		PrivateBucket: "dove-private",
+		AccessKey:     "access",
+		SecretKey:     "secret",
+		PathStyle:     true,
+		PublicURL:     "https://cdn.example.com",
+	}})
+	if err != nil {
+		t.Fatalf("NewPrivate() error = %v", err)
+	}
+	rawURL, err := s.PresignGet(context.Background(), "exports/1/a.zip", "a.zip", time.Minute)
+	if err != nil {
+		t.Fatalf("PresignGet() error = %v", err)
+	}
+	if !strings.Contains(rawURL, "/dove-private/exports/1/a.zip?") {
+		t.Errorf("PresignGet() = %s, want private bucket", rawURL)
+	}
+	if got := s.URL("a.zip"); strings.HasPrefix(got, "https://cdn.example.com") {
+		t.Errorf("URL() = %s, private storage must not use public_url", got)
+	}
+}
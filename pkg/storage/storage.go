@@ -0,0 +1,105 @@
+// Package storage 对象存储抽象，提供本地文件系统和 S3 兼容存储两种实现
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/deantook/dove/internal/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("对象不存在")
+
+// Storage 对象存储接口
+// key 为存储桶内的相对路径，使用 / 分隔，如 avatar/1/2024/01/abc.jpg
+type Storage interface {
+	// Put 写入对象，size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，对象不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat 获取对象信息，对象不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete 删除对象，对象不存在时不返回错误
+	Delete(ctx context.Context, key string) error
+	// URL 返回对象的访问地址
+	URL(key string) string
+	// PresignPut 生成预签名上传请求，客户端直接向返回的地址上传文件
+	PresignPut(ctx context.Context, key, contentType string, ttl time.Duration) (*PresignedRequest, error)
+	// PresignGet 生成限时下载地址，filename 为下载时保存的文件名
+	PresignGet(ctx context.Context, key, filename string, ttl time.Duration) (string, error)
+}
+
+// Private 私有对象存储，对象不提供公开访问地址，只能通过预签名下载地址访问
+// 用于个人数据导出等不能被猜测路径直接下载的文件
+type Private interface {
+	Storage
+}
+
+// ObjectInfo 对象信息
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+}
+
+// PresignedRequest 预签名上传请求
+type PresignedRequest struct {
+	Method    string      `json:"method"`     // 上传方法，固定为 PUT
+	URL       string      `json:"url"`        // 上传地址
+	Headers   http.Header `json:"headers"`    // 上传时必须携带的请求头
+	ExpiresAt time.Time   `json:"expires_at"` // 过期时间
+}
+
+// New 根据配置创建对象存储
+func New(cfg *config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case "s3":
+		return NewS3(&cfg.S3)
+	case "", "local":
+		return NewLocal(&cfg.Local)
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s", cfg.Driver)
+	}
+}
+
+// NewPrivate 根据配置创建私有对象存储
+// 本地存储使用 local.private_root 目录，S3 存储使用 s3.private_bucket（为空时使用 s3.bucket）
+func NewPrivate(cfg *config.StorageConfig) (Private, error) {
+	switch cfg.Driver {
+	case "s3":
+		s3Config := cfg.S3
+		if s3Config.PrivateBucket != "" {
+			s3Config.Bucket = s3Config.PrivateBucket
+		}
+		s3Config.PublicURL = ""
+		return NewS3(&s3Config)
+	case "", "local":
+		localConfig := cfg.Local
+		localConfig.Root = localConfig.PrivateRoot
+		localConfig.BaseURL = ""
+		return NewLocal(&localConfig)
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s", cfg.Driver)
+	}
+}
+
+// ReadHead 读取对象开头的若干字节，用于识别文件类型
+func ReadHead(ctx context.Context, s Storage, key string, n int) ([]byte, error) {
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	buf := make([]byte, n)
+	read, err := io.ReadFull(rc, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return buf[:read], nil
+}
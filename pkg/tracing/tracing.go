@@ -0,0 +1,201 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deantook/dove/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 业务代码使用的 Tracer 名称
+const instrumentationName = "github.com/deantook/dove"
+
+// 导出器类型
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Provider 链路追踪提供者
+// 未启用时为空实现，Shutdown 不做任何操作
+type Provider struct {
+	tp          *sdktrace.TracerProvider
+	closer      io.Closer
+	serviceName string
+}
+
+// Init 根据配置初始化全局 TracerProvider 和 W3C trace-context 传播器
+func Init(cfg *config.TracingConfig) (*Provider, error) {
+	// 无论是否启用都设置传播器，保证上下游的 traceparent 能够透传
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !cfg.Enabled {
+		return &Provider{}, nil
+	}
+
+	exporter, closer, err := newExporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(semconv.ServiceName(cfg.GetServiceName())),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("创建追踪资源失败: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+
+	slog.Info("链路追踪已启用",
+		slog.String("exporter", cfg.Exporter),
+		slog.Float64("sample_ratio", cfg.SampleRatio),
+	)
+	return &Provider{tp: tp, closer: closer, serviceName: cfg.GetServiceName()}, nil
+}
+
+// newExporter 根据配置创建导出器
+func newExporter(cfg *config.TracingConfig) (sdktrace.SpanExporter, io.Closer, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err := newOTLPExporter(cfg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建 OTLP 导出器失败: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, nil, fmt.Errorf("创建 stdout 导出器失败: %w", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		if dir := filepath.Dir(cfg.FilePath); dir != "" {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return nil, nil, fmt.Errorf("创建追踪文件目录失败: %w", err)
+			}
+		}
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("打开追踪文件失败: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, nil, fmt.Errorf("创建文件导出器失败: %w", err)
+		}
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("不支持的追踪导出器: %q", cfg.Exporter)
+	}
+}
+
+// newOTLPExporter 创建 OTLP 导出器，支持 grpc 和 http 协议
+func newOTLPExporter(cfg *config.TracingConfig) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+	if strings.EqualFold(cfg.Protocol, "http") {
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	opts := []otlptracegrpc.Option{}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
+
+// Noop 返回未启用的 Provider，用于迁移等不需要追踪的场景
+func Noop() *Provider {
+	return &Provider{}
+}
+
+// TracerProvider 返回底层 TracerProvider，未启用时返回 nil
+func (p *Provider) TracerProvider() trace.TracerProvider {
+	if !p.Enabled() {
+		return nil
+	}
+	return p.tp
+}
+
+// ServiceName 返回服务名
+func (p *Provider) ServiceName() string {
+	if p == nil || p.serviceName == "" {
+		return "dove"
+	}
+	return p.serviceName
+}
+
+// Enabled 是否启用了链路追踪
+func (p *Provider) Enabled() bool {
+	return p != nil && p.tp != nil
+}
+
+// Shutdown 导出剩余的 Span 并关闭导出器
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if !p.Enabled() {
+		return nil
+	}
+	var errs []error
+	if err := p.tp.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if p.closer != nil {
+		if err := p.closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Start 创建业务 Span
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// RecordError 在 Span 上记录错误并标记状态
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -1,20 +1,14 @@
 package validator
 
 import (
-	"regexp"
-
+	"github.com/deantook/dove/pkg/phone"
 	"github.com/go-playground/validator/v10"
 )
 
 // RegisterPhoneValidator 注册手机号验证器
-func RegisterPhoneValidator(v *validator.Validate) error {
-	return v.RegisterValidation("phone", validatePhone)
-}
-
-// validatePhone 验证手机号（中国手机号格式：11位数字，以1开头）
-func validatePhone(fl validator.FieldLevel) bool {
-	phone := fl.Field().String()
-	// 中国手机号正则：11位数字，以1开头，第二位为3-9
-	matched, _ := regexp.MatchString(`^1[3-9]\d{9}$`, phone)
-	return matched
+// phone 标签接受 E.164 国际格式或默认地区的本地格式，号码需符合所属地区规则且地区在允许范围内
+func RegisterPhoneValidator(v *validator.Validate, parser *phone.Parser) error {
+	return v.RegisterValidation("phone", func(fl validator.FieldLevel) bool {
+		return parser.Valid(fl.Field().String())
+	})
 }
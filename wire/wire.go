@@ -4,65 +4,331 @@
 package wire
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/deantook/dove/internal/app"
 	"github.com/deantook/dove/internal/config"
 	"github.com/deantook/dove/internal/handler"
+	"github.com/deantook/dove/internal/job"
+	"github.com/deantook/dove/internal/middleware"
 	"github.com/deantook/dove/internal/repository"
 	"github.com/deantook/dove/internal/router"
 	"github.com/deantook/dove/internal/service"
+	"github.com/deantook/dove/migrations"
+	"github.com/deantook/dove/pkg/cache"
 	"github.com/deantook/dove/pkg/database"
+	"github.com/deantook/dove/pkg/encryption"
+	"github.com/deantook/dove/pkg/health"
+	"github.com/deantook/dove/pkg/jwt"
+	"github.com/deantook/dove/pkg/metrics"
+	"github.com/deantook/dove/pkg/migrate"
+	"github.com/deantook/dove/pkg/phone"
+	"github.com/deantook/dove/pkg/query"
 	redisPkg "github.com/deantook/dove/pkg/redis"
+	"github.com/deantook/dove/pkg/storage"
+	"github.com/deantook/dove/pkg/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/wire"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
-// InitializeServer 初始化服务器
-func InitializeServer(cfg *config.Config) (*gin.Engine, error) {
+// InitializeApp 初始化应用依赖（HTTP 服务和命令行共用）
+// 依赖从配置提供者的当前快照构建，仅可热更新的组件持有提供者本身
+func InitializeApp(provider *config.Provider) (*app.App, error) {
 	wire.Build(
+		// 配置
+		configProvider,
+
 		// 数据库和 Redis
 		database.Init,
 		redisPkg.Init,
-		wire.FieldsOf(new(*config.Config), "Database", "Redis"),
+		wire.FieldsOf(new(*config.Config), "Server", "Database", "Redis", "JWT", "Log", "Metrics", "Tracing", "Health", "Cache", "Pagination", "Storage", "Upload", "Phone", "SMS", "User", "DataExport", "Audit", "Encryption"),
+
+		// 链路追踪
+		tracing.Init,
+
+		// JWT
+		jwtProvider,
+
+		// 健康检查
+		health.NewChecker,
+
+		// 指标
+		metrics.NewRegistry,
+
+		// 事务
+		database.NewTxManager,
+
+		// 缓存
+		cacheProvider,
+
+		// 分页
+		query.NewCursorCodec,
+
+		// 对象存储
+		storage.New,
+		storage.NewPrivate,
+
+		// 手机号解析
+		phoneParserProvider,
+
+		// 敏感字段加密
+		keyringProvider,
 
 		// Repository
-		repository.NewUserRepository,
-		repository.NewProfileFieldTemplateRepository,
+		userRepositoryProvider,
+		profileFieldTemplateRepositoryProvider,
 		repository.NewProfileFieldRepository,
+		repository.NewMediaRepository,
+		repository.NewPhoneChangeRepository,
+		repository.NewDataExportRepository,
+		repository.NewAuditLogRepository,
+		repository.NewEncryptedColumnRepository,
 
 		// Service
+		service.NewAuditService,
+		auditorProvider,
 		service.NewUserService,
 		service.NewProfileFieldTemplateService,
+		service.NewProfileFieldService,
+		service.NewMediaService,
+		service.NewUserPurgeService,
+		service.NewDataExportService,
+		service.NewEncryptionService,
 
 		// Handler
 		handler.NewUserHandler,
 		handler.NewProfileFieldTemplateHandler,
+		handler.NewProfileFieldHandler,
+		handler.NewMediaHandler,
+		handler.NewDataExportHandler,
+		handler.NewAuditLogHandler,
+		handler.NewHealthHandler,
+
+		// 中间件
+		middleware.NewRateLimiter,
+		middleware.NewAuthenticator,
 
 		// Router
 		router.NewRouter,
 		routerProvider,
+
+		// 后台任务
+		jobRegistryProvider,
+
+		app.New,
+	)
+
+	return nil, nil
+}
+
+// InitializeMigrator 初始化数据库迁移器
+func InitializeMigrator(cfg *config.Config) (*migrate.Migrator, error) {
+	wire.Build(
+		database.Init,
+		tracing.Noop,
+		keyringProvider,
+		wire.FieldsOf(new(*config.Config), "Database", "Log", "Encryption"),
+		migrations.NewMigrator,
 	)
 
 	return nil, nil
 }
 
+// configProvider 提供当前配置快照
+func configProvider(p *config.Provider) *config.Config {
+	return p.Get()
+}
+
+// jwtProvider 按配置提供 JWT 签发和校验器，拒绝内置的默认密钥
+func jwtProvider(cfg *config.JWTConfig) (*jwt.Manager, error) {
+	if cfg.Secret == config.InsecureJWTSecret {
+		return nil, errors.New("jwt.secret 不能使用内置的默认密钥")
+	}
+	return jwt.New(jwt.Options{Secret: cfg.Secret, Expire: cfg.GetExpire()})
+}
+
+// cacheProvider 按配置提供缓存，未启用时返回 nil
+func cacheProvider(cfg *config.CacheConfig, client *redis.Client) *cache.Cache {
+	if !cfg.Enabled {
+		return nil
+	}
+	return cache.New(cache.Options{
+		KeyPrefix:   cfg.KeyPrefix,
+		TTL:         cfg.GetTTL(),
+		NegativeTTL: cfg.GetNegativeTTL(),
+		Jitter:      cfg.Jitter,
+	}, client)
+}
+
+// userRepositoryProvider 提供用户仓储，启用缓存时包装缓存层
+func userRepositoryProvider(db *gorm.DB, c *cache.Cache, keyring *encryption.Keyring) repository.UserRepository {
+	return repository.NewCachedUserRepository(repository.NewUserRepository(db, keyring), c, keyring)
+}
+
+// profileFieldTemplateRepositoryProvider 提供字段模板仓储，启用缓存时包装缓存层
+func profileFieldTemplateRepositoryProvider(db *gorm.DB, c *cache.Cache) repository.ProfileFieldTemplateRepository {
+	return repository.NewCachedProfileFieldTemplateRepository(repository.NewProfileFieldTemplateRepository(db), c)
+}
+
+// phoneParserProvider 按配置的默认地区和允许地区提供手机号解析器
+func phoneParserProvider(cfg *config.PhoneConfig) (*phone.Parser, error) {
+	return phone.NewParser(cfg.DefaultRegion, cfg.AllowedRegions)
+}
+
+// keyringProvider 按配置提供敏感字段加密密钥，并注册 GORM 加密序列化器
+func keyringProvider(cfg *config.EncryptionConfig) (*encryption.Keyring, error) {
+	keyring, err := encryption.New(encryption.Options{
+		ActiveKey: cfg.ActiveKey,
+		Keys:      cfg.Keys,
+		IndexKey:  cfg.IndexKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encryption 配置错误: %w", err)
+	}
+	encryption.RegisterSerializer(keyring)
+	return keyring, nil
+}
+
+// auditorProvider 提供审计日志钩子
+func auditorProvider(s service.AuditService) service.Auditor {
+	return s
+}
+
 // routerProvider 提供 Router 的 Engine
 func routerProvider(r *router.Router) *gin.Engine {
 	r.SetupRoutes()
 	return r.GetEngine()
 }
 
+// jobRegistryProvider 提供后台任务注册表
+func jobRegistryProvider(
+	mediaService service.MediaService,
+	userService service.UserService,
+	purgeService service.UserPurgeService,
+	exportService service.DataExportService,
+	auditService service.AuditService,
+) *job.Registry {
+	registry := job.NewRegistry()
+	registry.Register(&job.Job{
+		Name:        "media-gc",
+		Description: "清理未完成上传或未被引用的媒体文件",
+		Run: func(ctx context.Context) error {
+			collected, err := mediaService.CollectGarbage(ctx)
+			slog.InfoContext(ctx, "媒体文件清理完成", slog.Int("collected", collected))
+			return err
+		},
+	})
+	registry.Register(&job.Job{
+		Name:        "user-status-expire",
+		Description: "恢复冻结或封禁已到期的用户",
+		Interval:    time.Minute,
+		Run: func(ctx context.Context) error {
+			released, err := userService.ReleaseExpiredStatuses(ctx)
+			if released > 0 {
+				slog.InfoContext(ctx, "用户状态到期恢复完成", slog.Int("released", released))
+			}
+			return err
+		},
+	})
+	registry.Register(&job.Job{
+		Name:        "user-purge",
+		Description: "物理删除超过保留期的注销用户及其资料字段、媒体文件、数据导出文件",
+		Interval:    time.Hour,
+		Run: func(ctx context.Context) error {
+			purged, err := purgeService.PurgeDeletedUsers(ctx)
+			if purged > 0 {
+				slog.InfoContext(ctx, "注销用户数据清除完成", slog.Int("purged", purged))
+			}
+			return err
+		},
+	})
+	registry.Register(&job.Job{
+		Name:        "data-export",
+		Description: "生成用户申请的个人数据导出文件",
+		Interval:    30 * time.Second,
+		Run: func(ctx context.Context) error {
+			processed, err := exportService.ProcessPending(ctx)
+			if processed > 0 {
+				slog.InfoContext(ctx, "个人数据导出生成完成", slog.Int("processed", processed))
+			}
+			return err
+		},
+	})
+	registry.Register(&job.Job{
+		Name:        "data-export-cleanup",
+		Description: "删除超过保留时间的个人数据导出文件",
+		Interval:    time.Hour,
+		Run: func(ctx context.Context) error {
+			cleaned, err := exportService.CleanupExpired(ctx)
+			if cleaned > 0 {
+				slog.InfoContext(ctx, "个人数据导出文件清理完成", slog.Int("cleaned", cleaned))
+			}
+			return err
+		},
+	})
+	registry.Register(&job.Job{
+		Name:        "audit-log-purge",
+		Description: "删除超过保留期的审计日志",
+		Interval:    time.Hour,
+		Run: func(ctx context.Context) error {
+			purged, err := auditService.PurgeExpired(ctx)
+			if purged > 0 {
+				slog.InfoContext(ctx, "过期审计日志清理完成", slog.Int64("purged", purged))
+			}
+			return err
+		},
+	})
+	return registry
+}
+
 // ProviderSet 提供者集合
 var ProviderSet = wire.NewSet(
 	database.Init,
 	redisPkg.Init,
+	metrics.NewRegistry,
+	tracing.Init,
+	jwtProvider,
+	health.NewChecker,
+	database.NewTxManager,
+	cacheProvider,
+	query.NewCursorCodec,
+	storage.New,
+	storage.NewPrivate,
+	phoneParserProvider,
+	keyringProvider,
 	repository.NewUserRepository,
 	repository.NewProfileFieldTemplateRepository,
 	repository.NewProfileFieldRepository,
+	repository.NewMediaRepository,
+	repository.NewPhoneChangeRepository,
+	repository.NewDataExportRepository,
+	repository.NewAuditLogRepository,
+	repository.NewEncryptedColumnRepository,
+	service.NewAuditService,
+	auditorProvider,
 	service.NewUserService,
 	service.NewProfileFieldTemplateService,
+	service.NewProfileFieldService,
+	service.NewMediaService,
+	service.NewUserPurgeService,
+	service.NewDataExportService,
+	service.NewEncryptionService,
 	handler.NewUserHandler,
 	handler.NewProfileFieldTemplateHandler,
+	handler.NewProfileFieldHandler,
+	handler.NewMediaHandler,
+	handler.NewDataExportHandler,
+	handler.NewAuditLogHandler,
+	handler.NewHealthHandler,
+	middleware.NewRateLimiter,
+	middleware.NewAuthenticator,
 	router.NewRouter,
 )
 
@@ -73,9 +339,41 @@ var (
 	_ repository.UserRepository
 	_ repository.ProfileFieldTemplateRepository
 	_ repository.ProfileFieldRepository
+	_ repository.MediaRepository
+	_ repository.PhoneChangeRepository
+	_ repository.DataExportRepository
+	_ repository.AuditLogRepository
+	_ repository.EncryptedColumnRepository
+	_ service.AuditService
+	_ service.Auditor
 	_ service.UserService
 	_ service.ProfileFieldTemplateService
+	_ service.ProfileFieldService
+	_ service.MediaService
+	_ service.UserPurgeService
+	_ service.DataExportService
+	_ service.EncryptionService
 	_ *handler.UserHandler
 	_ *handler.ProfileFieldTemplateHandler
+	_ *handler.ProfileFieldHandler
+	_ *handler.MediaHandler
+	_ *handler.DataExportHandler
+	_ *handler.AuditLogHandler
+	_ *handler.HealthHandler
+	_ *health.Checker
+	_ *cache.Cache
+	_ *query.CursorCodec
+	_ storage.Storage
+	_ storage.Private
+	_ *phone.Parser
+	_ *encryption.Keyring
+	_ *database.TxManager
+	_ *middleware.RateLimiter
+	_ *middleware.Authenticator
 	_ *router.Router
+	_ *job.Registry
+	_ *prometheus.Registry
+	_ *tracing.Provider
+	_ *jwt.Manager
+	_ *app.App
 )